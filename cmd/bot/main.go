@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/fadedpez/tucoramirez/pkg/discord"
+	"github.com/fadedpez/tucoramirez/pkg/entities"
 	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
 	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
 	walletService "github.com/fadedpez/tucoramirez/pkg/services/wallet"
 	"github.com/joho/godotenv"
 )
@@ -80,11 +85,137 @@ func main() {
 	}
 
 	wService := walletService.NewService(walletRepository)
+
+	// LOAN_TERMS overrides what Tuco charges on loans, e.g. interest=10%,every=24h,term=168h,cut=25%
+	if spec := os.Getenv("LOAN_TERMS"); spec != "" {
+		terms, err := walletService.ParseLoanTerms(spec)
+		if err != nil {
+			log.Fatalf("Invalid LOAN_TERMS: %v", err)
+		}
+		wService.SetLoanTerms(terms)
+	}
+
+	// Loans taken before they were tracked one by one fall due a term from now
+	if tracked, err := wService.TrackOutstandingLoans(context.Background()); err != nil {
+		log.Printf("Error tracking outstanding loans: %v", err)
+	} else if tracked > 0 {
+		log.Printf("Now tracking %d loans taken before loans were tracked", tracked)
+	}
+
+	// Check every chip in the economy is accounted for before the tables open
+	if reconciliation, err := wService.Reconcile(context.Background()); err != nil {
+		log.Printf("Wallet ledger check failed: %v", err)
+		if reconciliation != nil {
+			for _, problem := range reconciliation.Problems {
+				log.Printf("Ledger: %s", problem)
+			}
+		}
+	} else {
+		log.Printf("Wallet ledger reconciles: $%d issued, $%d burned, $%d in circulation",
+			reconciliation.Issued, reconciliation.Burned, reconciliation.Circulating)
+	}
 	bot, err := discord.NewBot(token, gameRepo, wService)
 	if err != nil {
 		log.Fatalf("Error creating bot: %v", err)
 	}
 
+	// Configure table rules
+	betLimits := os.Getenv("BET_LIMITS")
+	if name := os.Getenv("DEFAULT_RULESET"); name != "" || betLimits != "" {
+		rules := blackjack.DefaultRuleSet()
+		if name != "" {
+			if rules, err = blackjack.RuleSetByName(name); err != nil {
+				log.Fatalf("Invalid DEFAULT_RULESET: %v", err)
+			}
+		}
+		// BET_LIMITS sets the table limits as min-max/increment, e.g. 10-500/5
+		if betLimits != "" {
+			if rules, err = rules.WithBetLimits(betLimits); err != nil {
+				log.Fatalf("Invalid BET_LIMITS: %v", err)
+			}
+		}
+		if err := bot.SetDefaultRules(rules); err != nil {
+			log.Fatalf("Invalid DEFAULT_RULESET: %v", err)
+		}
+		log.Printf("Using %s rules by default, bets of %s", rules.Name, rules.LimitsSummary())
+	}
+
+	// CHANNEL_RULESETS is a comma separated list of channelID:ruleset pairs,
+	// each with optional limits that replace BET_LIMITS, e.g. 123:european:25-1000/25
+	for _, entry := range strings.Split(os.Getenv("CHANNEL_RULESETS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		channelID, name, found := strings.Cut(entry, ":")
+		if !found {
+			log.Fatalf("Invalid CHANNEL_RULESETS entry %q, expected channelID:ruleset", entry)
+		}
+		name, limits, hasLimits := strings.Cut(name, ":")
+		if !hasLimits {
+			limits = betLimits
+		}
+		rules, err := blackjack.RuleSetByName(name)
+		if err != nil {
+			log.Fatalf("Invalid CHANNEL_RULESETS entry %q: %v", entry, err)
+		}
+		if limits != "" {
+			if rules, err = rules.WithBetLimits(limits); err != nil {
+				log.Fatalf("Invalid CHANNEL_RULESETS entry %q: %v", entry, err)
+			}
+		}
+		if err := bot.SetTableRules(strings.TrimSpace(channelID), rules); err != nil {
+			log.Fatalf("Invalid CHANNEL_RULESETS entry %q: %v", entry, err)
+		}
+		log.Printf("Using %s rules in channel %s, bets of %s", rules.Name, channelID, rules.LimitsSummary())
+	}
+
+	// SHUFFLE_SEED makes every shoe follow from a fixed seed, for reproducing a reported hand
+	if hexSeed := os.Getenv("SHUFFLE_SEED"); hexSeed != "" {
+		seed, err := entities.ParseSeed(hexSeed)
+		if err != nil {
+			log.Fatalf("Invalid SHUFFLE_SEED: %v", err)
+		}
+		bot.SetShuffler(entities.NewSeededShuffler(seed))
+		log.Printf("Using seeded shuffler, shoes are not random!")
+	}
+
+	// PROVABLY_FAIR commits to every shoe and lets players mix in their own seeds
+	if os.Getenv("PROVABLY_FAIR") == "true" {
+		if os.Getenv("SHUFFLE_SEED") != "" {
+			log.Fatalf("PROVABLY_FAIR and SHUFFLE_SEED can't be used together")
+		}
+		bot.EnableProvablyFair()
+		log.Printf("Provably fair mode enabled")
+	}
+
+	// TURN_TIMERS overrides how long players get per phase, e.g. betting=90s,playing=1m
+	if spec := os.Getenv("TURN_TIMERS"); spec != "" {
+		timers, err := blackjack.ParseTurnTimers(spec)
+		if err != nil {
+			log.Fatalf("Invalid TURN_TIMERS: %v", err)
+		}
+		bot.SetTurnTimers(timers)
+	}
+
+	// PERFECT_PAIRS_PAYS overrides the Perfect Pairs pay table, e.g. perfect=30,colored=10,mixed=5
+	if spec := os.Getenv("PERFECT_PAIRS_PAYS"); spec != "" {
+		pays, err := blackjack.ParsePairPayTable(spec)
+		if err != nil {
+			log.Fatalf("Invalid PERFECT_PAIRS_PAYS: %v", err)
+		}
+		bot.RegisterSideBet(blackjack.PerfectPairs{Pays: pays})
+	}
+
+	// AFK_KICK_AFTER is how many turns in a row a player can time out before they're kicked
+	if value := os.Getenv("AFK_KICK_AFTER"); value != "" {
+		timeouts, err := strconv.Atoi(value)
+		if err != nil || timeouts < 0 {
+			log.Fatalf("Invalid AFK_KICK_AFTER %q", value)
+		}
+		bot.SetAFKKickAfter(timeouts)
+	}
+
 	// Start the bot
 	if err := bot.Start(); err != nil {
 		log.Fatalf("Error starting bot: %v", err)
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+	"github.com/fadedpez/tucoramirez/pkg/services/simulation"
+)
+
+func main() {
+	ruleSet := flag.String("rules", blackjack.RuleSetHouse, "Rule set to play: house, vegas_strip, atlantic_city or european")
+	betLimits := flag.String("limits", "", "Table limits as min-max/increment, e.g. 10-500/5")
+	strategy := flag.String("strategy", string(blackjack.BotBasic), "How every seat bets and plays: basic, reckless or counter")
+	seats := flag.Int("seats", 1, "Seats at the table")
+	rounds := flag.Int("rounds", 1000000, "Rounds to deal")
+	sideBets := flag.String("side-bets", "", "Comma separated side bets every seat places each round, e.g. perfect_pairs")
+	sideBetAmount := flag.Int64("side-bet-amount", 0, "Stake on each side bet, 0 for its minimum or the table's")
+	perfectPairsPays := flag.String("perfect-pairs-pays", "", "Perfect Pairs pay table, e.g. perfect=30,colored=10,mixed=5")
+	hexSeed := flag.String("seed", "", "Hex seed for the shuffles, so a run can be repeated")
+	asJSON := flag.Bool("json", false, "Print the report as JSON")
+	flag.Parse()
+
+	// The game logs every move, which would drown millions of rounds
+	logger := log.New(os.Stderr, "", 0)
+	log.SetOutput(io.Discard)
+
+	rules, err := blackjack.RuleSetByName(*ruleSet)
+	if err != nil {
+		logger.Fatalf("Invalid -rules: %v", err)
+	}
+	if *betLimits != "" {
+		if rules, err = rules.WithBetLimits(*betLimits); err != nil {
+			logger.Fatalf("Invalid -limits: %v", err)
+		}
+	}
+
+	config := simulation.Config{
+		Rules:         rules,
+		Strategy:      blackjack.BotPersonality(*strategy),
+		Seats:         *seats,
+		Rounds:        *rounds,
+		SideBetAmount: *sideBetAmount,
+	}
+	for _, name := range strings.Split(*sideBets, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			config.SideBets = append(config.SideBets, name)
+		}
+	}
+	if *perfectPairsPays != "" {
+		pays, err := blackjack.ParsePairPayTable(*perfectPairsPays)
+		if err != nil {
+			logger.Fatalf("Invalid -perfect-pairs-pays: %v", err)
+		}
+		config.Registry = blackjack.DefaultSideBetRegistry()
+		config.Registry.Register(blackjack.PerfectPairs{Pays: pays})
+	}
+	if *hexSeed != "" {
+		seed, err := entities.ParseSeed(*hexSeed)
+		if err != nil {
+			logger.Fatalf("Invalid -seed: %v", err)
+		}
+		config.Shuffler = entities.NewSeededShuffler(seed)
+	}
+
+	report, err := simulation.Run(context.Background(), config)
+	if err != nil {
+		logger.Fatalf("Simulation failed: %v", err)
+	}
+
+	if *asJSON {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			logger.Fatalf("Error encoding report: %v", err)
+		}
+		fmt.Println(string(output))
+		return
+	}
+	if err := report.WriteText(os.Stdout); err != nil {
+		logger.Fatalf("Error writing report: %v", err)
+	}
+}
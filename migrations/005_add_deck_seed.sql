@@ -0,0 +1,25 @@
+-- Migration: add deck seed
+-- Created: 2026-10-16T09:28:40Z
+
+
+-- SQLite Examples:
+
+-- Create a new table
+-- CREATE TABLE IF NOT EXISTS table_name (
+--   id INTEGER PRIMARY KEY AUTOINCREMENT,
+--   name TEXT NOT NULL,
+--   value INTEGER DEFAULT 0,
+--   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+-- );
+
+-- Add a column to existing table
+-- ALTER TABLE table_name ADD COLUMN new_column TEXT;
+
+-- Create an index
+-- CREATE INDEX IF NOT EXISTS idx_table_column ON table_name(column_name);
+
+-- Your migration SQL goes below this line:
+
+-- Track the shuffle seed and how far into the shoe each channel is, so rounds can be dealt again
+ALTER TABLE decks ADD COLUMN seed TEXT;
+ALTER TABLE decks ADD COLUMN dealt INTEGER DEFAULT 0;
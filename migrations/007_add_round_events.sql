@@ -0,0 +1,37 @@
+-- Migration: add round events
+-- Created: 2026-10-16T09:28:45Z
+
+
+-- SQLite Examples:
+
+-- Create a new table
+-- CREATE TABLE IF NOT EXISTS table_name (
+--   id INTEGER PRIMARY KEY AUTOINCREMENT,
+--   name TEXT NOT NULL,
+--   value INTEGER DEFAULT 0,
+--   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+-- );
+
+-- Add a column to existing table
+-- ALTER TABLE table_name ADD COLUMN new_column TEXT;
+
+-- Create an index
+-- CREATE INDEX IF NOT EXISTS idx_table_column ON table_name(column_name);
+
+-- Your migration SQL goes below this line:
+
+-- Append-only log of everything that happened in a round, in order
+CREATE TABLE IF NOT EXISTS round_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  round_id TEXT NOT NULL,
+  channel_id TEXT NOT NULL,
+  sequence INTEGER NOT NULL,
+  type TEXT NOT NULL,
+  player_id TEXT,
+  hand_id TEXT,
+  data TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  UNIQUE (round_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_round_events_channel ON round_events(channel_id);
@@ -0,0 +1,30 @@
+-- Migration: add table snapshots
+-- Created: 2026-10-16T09:28:47Z
+
+
+-- SQLite Examples:
+
+-- Create a new table
+-- CREATE TABLE IF NOT EXISTS table_name (
+--   id INTEGER PRIMARY KEY AUTOINCREMENT,
+--   name TEXT NOT NULL,
+--   value INTEGER DEFAULT 0,
+--   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+-- );
+
+-- Add a column to existing table
+-- ALTER TABLE table_name ADD COLUMN new_column TEXT;
+
+-- Create an index
+-- CREATE INDEX IF NOT EXISTS idx_table_column ON table_name(column_name);
+
+-- Your migration SQL goes below this line:
+
+-- The live lobby or game of each channel, so tables survive a restart
+CREATE TABLE IF NOT EXISTS table_snapshots (
+  channel_id TEXT PRIMARY KEY,
+  kind TEXT NOT NULL,
+  round_id TEXT,
+  data TEXT NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,24 @@
+-- Migration: add player result metadata
+-- Created: 2026-10-16T09:28:49Z
+
+
+-- SQLite Examples:
+
+-- Create a new table
+-- CREATE TABLE IF NOT EXISTS table_name (
+--   id INTEGER PRIMARY KEY AUTOINCREMENT,
+--   name TEXT NOT NULL,
+--   value INTEGER DEFAULT 0,
+--   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+-- );
+
+-- Add a column to existing table
+-- ALTER TABLE table_name ADD COLUMN new_column TEXT;
+
+-- Create an index
+-- CREATE INDEX IF NOT EXISTS idx_table_column ON table_name(column_name);
+
+-- Your migration SQL goes below this line:
+
+-- Side bets and other per-hand details, so they show up in game history
+ALTER TABLE player_results ADD COLUMN metadata TEXT;
@@ -0,0 +1,35 @@
+-- Migration: add count drills
+-- Created: 2026-10-16T09:28:51Z
+
+
+-- SQLite Examples:
+
+-- Create a new table
+-- CREATE TABLE IF NOT EXISTS table_name (
+--   id INTEGER PRIMARY KEY AUTOINCREMENT,
+--   name TEXT NOT NULL,
+--   value INTEGER DEFAULT 0,
+--   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+-- );
+
+-- Add a column to existing table
+-- ALTER TABLE table_name ADD COLUMN new_column TEXT;
+
+-- Create an index
+-- CREATE INDEX IF NOT EXISTS idx_table_column ON table_name(column_name);
+
+-- Your migration SQL goes below this line:
+
+-- Every answer given in the card counting trainer, for accuracy over time
+CREATE TABLE IF NOT EXISTS count_drills (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  player_id TEXT NOT NULL,
+  system TEXT NOT NULL,
+  question TEXT NOT NULL,
+  answer REAL NOT NULL,
+  count REAL NOT NULL,
+  correct BOOLEAN NOT NULL,
+  answered_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_count_drills_player ON count_drills(player_id);
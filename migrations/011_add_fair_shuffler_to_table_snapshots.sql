@@ -0,0 +1,25 @@
+-- Migration: add fair shuffler to table snapshots
+-- Created: 2026-10-16T09:44:41Z
+
+
+-- SQLite Examples:
+
+-- Create a new table
+-- CREATE TABLE IF NOT EXISTS table_name (
+--   id INTEGER PRIMARY KEY AUTOINCREMENT,
+--   name TEXT NOT NULL,
+--   value INTEGER DEFAULT 0,
+--   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+-- );
+
+-- Add a column to existing table
+-- ALTER TABLE table_name ADD COLUMN new_column TEXT;
+
+-- Create an index
+-- CREATE INDEX IF NOT EXISTS idx_table_column ON table_name(column_name);
+
+-- Your migration SQL goes below this line:
+
+
+-- The provably fair shoes of each channel, so a committed shoe can still be revealed after a restart
+ALTER TABLE table_snapshots ADD COLUMN fair TEXT;
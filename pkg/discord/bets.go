@@ -0,0 +1,160 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// betOptions returns the amounts offered as bet buttons at a table, the
+// minimum and a couple of multiples of it that fit under the maximum
+func betOptions(rules blackjack.RuleSet) []int64 {
+	minBet := rules.MinBet
+	if minBet <= 0 {
+		minBet = 5
+	}
+
+	options := []int64{}
+	for _, multiple := range []int64{1, 2, 5} {
+		amount := minBet * multiple
+		if rules.MaxBet > 0 && amount > rules.MaxBet {
+			break
+		}
+		options = append(options, amount)
+	}
+	return options
+}
+
+// lastBet returns what a player bet last round at a table, or 0 if they haven't bet there yet
+func (b *Bot) lastBet(channelID, playerID string) int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastBets[afkKey(channelID, playerID)]
+}
+
+// setLastBet remembers a player's bet so they can repeat it next round
+func (b *Bot) setLastBet(channelID, playerID string, amount int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastBets[afkKey(channelID, playerID)] = amount
+}
+
+// checkBetAmount makes sure a bet is within the table's limits and that the
+// player can cover it, with the standard loan Tuco hands out if they're short
+func (b *Bot) checkBetAmount(game *blackjack.Game, playerID string, amount int64) error {
+	if err := game.Rules.CheckBet(amount); err != nil {
+		return err
+	}
+
+	// A player's first bet opens their wallet
+	wallet, _, err := b.walletService.GetOrCreateWallet(context.Background(), playerID)
+	if err != nil {
+		return fmt.Errorf("error getting wallet: %w", err)
+	}
+	balance := wallet.Balance
+	if covered := balance + b.walletService.GetStandardLoanIncrement(); amount > covered {
+		return fmt.Errorf("you have $%d and Tuco only lends $%d at a time, that won't cover $%d",
+			balance, b.walletService.GetStandardLoanIncrement(), amount)
+	}
+	return nil
+}
+
+// betErrorMessage is what Tuco says when a bet is turned down
+func betErrorMessage(game *blackjack.Game, err error) string {
+	switch {
+	case errors.Is(err, blackjack.ErrBetBelowMinimum), errors.Is(err, blackjack.ErrBetAboveMaximum),
+		errors.Is(err, blackjack.ErrBetIncrement):
+		return fmt.Sprintf("¡No es posible! *taps the sign* This table takes bets of %s.", game.Rules.LimitsSummary())
+	}
+	return fmt.Sprintf("¡No es posible! *shakes head* %v", err)
+}
+
+// handleCustomBetButton opens the modal where a player types their bet.
+func (b *Bot) handleCustomBetButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b.mu.RLock()
+	game, exists := b.games[i.ChannelID]
+	b.mu.RUnlock()
+
+	placeholder := "How much, amigo?"
+	if exists {
+		placeholder = "Bets of " + game.Rules.LimitsSummary()
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: "custom_bet_modal",
+			Title:    "Place your bet",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "bet_amount",
+							Label:       "Bet amount in dollars",
+							Style:       discordgo.TextInputShort,
+							Placeholder: placeholder,
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   12,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error opening custom bet modal: %v", err)
+	}
+}
+
+// handleCustomBetSubmit places the bet a player typed into the custom bet modal
+func (b *Bot) handleCustomBetSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	// Acknowledge the modal, the betting message is updated once the bet is placed
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		log.Printf("Error acknowledging custom bet: %v", err)
+		return
+	}
+
+	value := strings.TrimPrefix(strings.TrimSpace(modalTextValue(i.ModalSubmitData(), "bet_amount")), "$")
+	betAmount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: fmt.Sprintf("*Tuco squints at the chips* ¿Qué? `%s` isn't a bet, amigo. Whole dollars only.", value),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return
+	}
+
+	if err := b.handleBet(s, i, betAmount); err != nil {
+		log.Printf("Error placing custom bet: %v", err)
+	}
+}
+
+// handleRebet bets what the player bet last round at this table, or twice that
+func (b *Bot) handleRebet(s *discordgo.Session, i *discordgo.InteractionCreate, multiplier int64) error {
+	lastBet := b.lastBet(i.ChannelID, i.Member.User.ID)
+	if lastBet == 0 {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: "*Tuco flips through his ledger* You haven't bet at this table yet, amigo. Pick an amount!",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return fmt.Errorf("no last bet for player %s in channel %s", i.Member.User.ID, i.ChannelID)
+	}
+
+	return b.handleBet(s, i, lastBet*multiplier)
+}
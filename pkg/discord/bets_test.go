@@ -0,0 +1,23 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+	"github.com/fadedpez/tucoramirez/pkg/services/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBetAmountOpensAFirstTimePlayersWallet(t *testing.T) {
+	b := &Bot{walletService: wallet.NewService(walletRepo.NewMemoryRepository())}
+	g := blackjack.NewGame("test-channel", game.NewMemoryRepository(), blackjack.DefaultRuleSet(), nil)
+
+	// Someone who never ran /wallet can still bet
+	assert.NoError(t, b.checkBetAmount(g, "newcomer", g.Rules.MinBet))
+
+	// Their new wallet and one loan still have to cover it
+	tooMuch := wallet.StartingBalance + b.walletService.GetStandardLoanIncrement() + g.Rules.BetIncrement
+	assert.Error(t, b.checkBetAmount(g, "newcomer", tooMuch))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/entities"
 	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
 	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
 	"github.com/fadedpez/tucoramirez/pkg/services/image"
@@ -15,7 +16,9 @@ import (
 
 type GameLobby struct {
 	OwnerID string
-	Players map[string]bool // playerID -> joined
+	Players map[string]bool   // playerID -> joined, computer players included
+	Seats   map[string]int    // playerID -> seats they play, only set for players with more than one
+	Rules   blackjack.RuleSet // Rules the table will be played under
 }
 
 // Bot represents the Discord bot instance
@@ -39,8 +42,41 @@ type Bot struct {
 	processedInteractions map[string]bool
 	lastCleanupTime       time.Time
 
-	// Wallet service
+	// Wallet service, and the wallets the tables play with where computer
+	// players stake house money instead of a real wallet
 	walletService *wallet.Service
+	tableWallet   *blackjack.HouseMoneyWallet
+
+	// Table rules, optionally overridden per channel
+	rulesMu      sync.RWMutex
+	defaultRules blackjack.RuleSet
+	tableRules   map[string]blackjack.RuleSet
+
+	// Shuffles every new shoe dealt by the bot
+	shuffler entities.Shuffler
+
+	// Provably fair mode gives every channel its own committed shuffler
+	fairMu        sync.Mutex
+	provablyFair  bool
+	fairShufflers map[string]*blackjack.FairShuffler
+
+	// Side bets offered at the bot's tables, with their pay tables
+	sideBets *blackjack.SideBetRegistry
+
+	// Turn timers, and the players who keep letting them run out
+	turnTimers       blackjack.TurnTimers
+	afkKickAfter     int // Timeouts in a row before a player is kicked, 0 never kicks
+	afkMu            sync.Mutex
+	afkStrikes       map[string]int              // channelID/playerID -> turns timed out in a row
+	kickedUntil      map[string]time.Time        // channelID/playerID -> when the player may join again
+	tableMessages    map[string]string           // channelID -> message showing the table, protected by mu
+	lastBets         map[string]int64            // channelID/playerID -> last round's bet for the rebet buttons, protected by mu
+	spectatorThreads map[string]*spectatorThread // channelID -> thread the table is watched from, protected by mu
+	stopChan         chan struct{}
+
+	// Card counting drills, one per player
+	trainersMu sync.Mutex
+	trainers   map[string]*blackjack.CountTrainer
 
 	// Channel to signal when the bot is ready
 	readyChan chan struct{}
@@ -69,6 +105,21 @@ func NewBot(token string, repository game.Repository, walletService *wallet.Serv
 		processedInteractions: make(map[string]bool),
 		lastCleanupTime:       time.Now(),
 		walletService:         walletService,
+		tableWallet:           blackjack.NewHouseMoneyWallet(walletService),
+		defaultRules:          blackjack.DefaultRuleSet(),
+		tableRules:            make(map[string]blackjack.RuleSet),
+		shuffler:              entities.NewCryptoShuffler(),
+		fairShufflers:         make(map[string]*blackjack.FairShuffler),
+		sideBets:              blackjack.DefaultSideBetRegistry(),
+		turnTimers:            blackjack.DefaultTurnTimers(),
+		afkKickAfter:          DefaultAFKKickAfter,
+		afkStrikes:            make(map[string]int),
+		kickedUntil:           make(map[string]time.Time),
+		tableMessages:         make(map[string]string),
+		lastBets:              make(map[string]int64),
+		spectatorThreads:      make(map[string]*spectatorThread),
+		trainers:              make(map[string]*blackjack.CountTrainer),
+		stopChan:              make(chan struct{}),
 		readyChan:             make(chan struct{}),
 	}
 
@@ -82,9 +133,84 @@ func NewBot(token string, repository game.Repository, walletService *wallet.Serv
 	return bot, nil
 }
 
+// SetShuffler replaces the shuffler used for new shoes, e.g. with a seeded one
+// to reproduce a reported hand. Call it before the bot starts.
+func (b *Bot) SetShuffler(shuffler entities.Shuffler) {
+	b.shuffler = shuffler
+}
+
+// EnableProvablyFair makes every table commit to its shoes before dealing them
+// and reveal the server seeds once the shoes are retired
+func (b *Bot) EnableProvablyFair() {
+	b.fairMu.Lock()
+	defer b.fairMu.Unlock()
+	b.provablyFair = true
+}
+
+// fairShufflerForChannel returns the channel's provably fair shuffler, or nil
+// if provably fair mode is off
+func (b *Bot) fairShufflerForChannel(channelID string) *blackjack.FairShuffler {
+	b.fairMu.Lock()
+	defer b.fairMu.Unlock()
+
+	if !b.provablyFair {
+		return nil
+	}
+
+	shuffler, ok := b.fairShufflers[channelID]
+	if !ok {
+		shuffler = blackjack.NewFairShuffler()
+		b.fairShufflers[channelID] = shuffler
+	}
+	return shuffler
+}
+
+// shufflerForChannel returns the shuffler new games in the channel should use
+func (b *Bot) shufflerForChannel(channelID string) entities.Shuffler {
+	if fair := b.fairShufflerForChannel(channelID); fair != nil {
+		return fair
+	}
+	return b.shuffler
+}
+
+// SetDefaultRules sets the rules used by channels without their own table rules
+func (b *Bot) SetDefaultRules(rules blackjack.RuleSet) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+	b.defaultRules = rules
+	return nil
+}
+
+// SetTableRules sets the rules for games played in a specific channel
+func (b *Bot) SetTableRules(channelID string, rules blackjack.RuleSet) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	b.rulesMu.Lock()
+	defer b.rulesMu.Unlock()
+	b.tableRules[channelID] = rules
+	return nil
+}
+
+// rulesForChannel returns the rules configured for a channel
+func (b *Bot) rulesForChannel(channelID string) blackjack.RuleSet {
+	b.rulesMu.RLock()
+	defer b.rulesMu.RUnlock()
+
+	if rules, ok := b.tableRules[channelID]; ok {
+		return rules
+	}
+	return b.defaultRules
+}
+
 // Start initializes the bot and connects to Discord
 func (b *Bot) Start() error {
-	// Clean up any stale state
+	// Clean up any stale state, saved tables are restored once Discord is ready
 	b.mu.Lock()
 	log.Printf("Cleaning up stale state: %d games, %d lobbies", len(b.games), len(b.lobbies))
 	b.games = make(map[string]*blackjack.Game)
@@ -97,12 +223,16 @@ func (b *Bot) Start() error {
 		return fmt.Errorf("error opening connection: %w", err)
 	}
 
+	go b.runTurnTimers()
+	go b.runLoanScheduler()
 	return nil
 }
 
 // Stop gracefully shuts down the bot and closes the Discord connection
 func (b *Bot) Stop() error {
-	// Clean up any active games and lobbies
+	close(b.stopChan)
+
+	// Clean up any active games and lobbies, they stay saved for the next start
 	b.mu.Lock()
 	b.games = make(map[string]*blackjack.Game)
 	b.lobbies = make(map[string]*GameLobby)
@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// BotTurnDelay is how long a computer player takes over a decision. It's a bit
+// under TurnTimerInterval, so each decision comes on the tick after the last.
+const BotTurnDelay = 1500 * time.Millisecond
+
+// handleAddBot sits a computer player with the personality down in the lobby.
+// Only the lobby owner can fill seats with them.
+func (b *Bot) handleAddBot(s *discordgo.Session, i *discordgo.InteractionCreate, name string) {
+	personality, err := blackjack.ParseBotPersonality(name)
+	if err != nil {
+		log.Printf("Error adding computer player: %v", err)
+		return
+	}
+
+	b.mu.Lock()
+	lobby, exists := b.lobbies[i.ChannelID]
+	var content string
+	switch {
+	case !exists:
+		content = "¡Ay caramba! *frantically searches the casino* No lobby found in this channel! Start a new game with /blackjack"
+	case lobby.OwnerID != i.Member.User.ID:
+		content = "¡No, no, no! *wags finger* Only the lobby owner can invite Tuco's amigos to the table!"
+	case lobby.seatsTaken() >= lobby.Rules.MaxPlayers:
+		content = "*Tuco counts the chairs* The table is full, amigo. No room for anyone else."
+	default:
+		// Number each personality's seats so they can be told apart
+		n := 1
+		for lobby.Players[blackjack.NewBotID(personality, n)] {
+			n++
+		}
+		lobby.Players[blackjack.NewBotID(personality, n)] = true
+	}
+	b.mu.Unlock()
+
+	if content != "" {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return
+	}
+
+	b.updateLobbyDisplay(s, i, lobby)
+}
+
+// checkBotTurns plays the turn of every computer player who's done thinking
+func (b *Bot) checkBotTurns(now time.Time) {
+	var due []string
+	b.mu.Lock()
+	for channelID, game := range b.games {
+		if game.BotTurnDue(now, BotTurnDelay) {
+			due = append(due, channelID)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, channelID := range due {
+		b.playBotTurn(b.session, channelID)
+	}
+}
+
+// playBotTurn makes the current computer player's decision and shows the table again
+func (b *Bot) playBotTurn(s *discordgo.Session, channelID string) {
+	b.mu.Lock()
+	game, exists := b.games[channelID]
+	if !exists || !game.BotTurnDue(time.Now(), BotTurnDelay) {
+		b.mu.Unlock()
+		return
+	}
+	playerID, action, err := game.PlayBotTurn(context.Background(), b.tableWallet)
+	b.mu.Unlock()
+	if err != nil {
+		// Don't leave the table waiting on a computer player that can't decide
+		log.Printf("Error playing computer player's turn in channel %s: %v", channelID, err)
+		b.mu.Lock()
+		playerID, err = game.TimeOutTurn(context.Background(), b.tableWallet)
+		b.mu.Unlock()
+		if err != nil {
+			log.Printf("Error timing out computer player's turn in channel %s: %v", channelID, err)
+			return
+		}
+		action = ""
+	}
+
+	content := fmt.Sprintf("*%s %s*", blackjack.BotName(playerID), botActionText(game, playerID, action))
+	b.refreshTable(s, channelID, game, content)
+	b.saveTable(channelID)
+	b.updateSpectators(s, channelID)
+}
+
+// botActionText describes what a computer player did
+func botActionText(game *blackjack.Game, playerID string, action blackjack.EventType) string {
+	switch action {
+	case blackjack.EventBetPlaced:
+		return fmt.Sprintf("slides $%d onto the table", game.Bets[playerID])
+	case blackjack.EventSplit:
+		return "splits the pair"
+	case blackjack.EventSplitDeclined:
+		return "keeps the pair together"
+	case blackjack.EventSideBetPlaced:
+		return "takes insurance"
+	case blackjack.EventDoubleDown:
+		return "doubles down"
+	case blackjack.EventSurrender:
+		return "surrenders the hand"
+	case blackjack.EventDeclined:
+		return "passes on the special bets"
+	case blackjack.EventHit:
+		return "taps the table for a card"
+	case blackjack.EventStand:
+		return "waves a hand and stands"
+	}
+	return "sits there blinking, so Tuco plays for them"
+}
+
+// playerMention mentions a player in a message, computer players by name
+func playerMention(playerID string) string {
+	if blackjack.IsBotPlayer(playerID) {
+		return blackjack.BotName(playerID)
+	}
+	return fmt.Sprintf("<@%s>", playerID)
+}
+
+// playerUsername returns a player's Discord username, or a computer player's name
+func playerUsername(s *discordgo.Session, playerID string) (string, error) {
+	if blackjack.IsBotPlayer(playerID) {
+		return blackjack.BotName(playerID), nil
+	}
+	user, err := s.User(playerID)
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
@@ -0,0 +1,263 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// verifyCommand checks a revealed shoe against its commitment
+var verifyCommand = &discordgo.ApplicationCommand{
+	Name:        "verify",
+	Description: "Check a revealed shoe against Tuco's commitment, amigo",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "server_seed",
+			Description: "The revealed server seed",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "commitment",
+			Description: "The commitment posted before the shoe was dealt",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "client_seeds",
+			Description: "The client seeds in order, separated by commas",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "decks",
+			Description: "Number of decks in the shoe",
+			Required:    false,
+			MinValue:    &[]float64{1}[0],
+			MaxValue:    8,
+		},
+	},
+}
+
+// verifyPreviewCards is how many cards of a verified shoe are shown
+const verifyPreviewCards = 10
+
+func (b *Bot) handleVerifyCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var (
+		serverSeed  string
+		commitment  string
+		clientSeeds []string
+		decks       = blackjack.StandardDecks
+	)
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "server_seed":
+			serverSeed = option.StringValue()
+		case "commitment":
+			commitment = option.StringValue()
+		case "client_seeds":
+			for _, seed := range strings.Split(option.StringValue(), ",") {
+				if seed = strings.TrimSpace(seed); seed != "" {
+					clientSeeds = append(clientSeeds, seed)
+				}
+			}
+		case "decks":
+			decks = int(option.IntValue())
+		}
+	}
+
+	var content string
+	shoe, err := blackjack.VerifyShoe(serverSeed, commitment, clientSeeds, decks)
+	switch {
+	case errors.Is(err, blackjack.ErrCommitmentMismatch):
+		content = "❌ ¡Mentira! That server seed does not match the commitment."
+	case err != nil:
+		content = fmt.Sprintf("❌ ¡No es posible! *squints at the numbers* %v", err)
+	default:
+		preview := shoe.Cards
+		if len(preview) > verifyPreviewCards {
+			preview = preview[:verifyPreviewCards]
+		}
+		content = fmt.Sprintf("✅ ¡Todo legal! The server seed matches the commitment.\n**Shoe seed:** `%s`\n**First %d cards:** %s",
+			shoe.Seed, len(preview), FormatCards(preview))
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to verify command: %v", err)
+	}
+}
+
+// handleClientSeedButton opens the modal where a player types their client seed.
+func (b *Bot) handleClientSeedButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: "client_seed_modal",
+			Title:    "Add your client seed",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "client_seed",
+							Label:       "Client seed, mixed into the next shoe",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "Anything you like, amigo",
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   blackjack.MaxClientSeedLength,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error opening client seed modal: %v", err)
+	}
+}
+
+func (b *Bot) handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.ModalSubmitData().CustomID {
+	case "client_seed_modal":
+		b.handleClientSeedSubmit(s, i)
+	case "custom_bet_modal":
+		b.handleCustomBetSubmit(s, i)
+	case "train_answer_modal":
+		b.handleTrainerAnswerSubmit(s, i)
+	}
+}
+
+func (b *Bot) handleClientSeedSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	seed := modalTextValue(i.ModalSubmitData(), "client_seed")
+
+	b.mu.RLock()
+	game, exists := b.games[i.ChannelID]
+	b.mu.RUnlock()
+
+	var content string
+	if !exists {
+		content = "¡Ay caramba! *looks around confused* No game found in this channel!"
+	} else if err := game.AddClientSeed(i.Member.User.ID, seed); err != nil {
+		switch {
+		case errors.Is(err, blackjack.ErrInvalidAction):
+			content = "¡Demasiado tarde! Client seeds are only taken while the table is betting."
+		case errors.Is(err, blackjack.ErrPlayerNotFound):
+			content = "¡Oye! Only players at the table can add a client seed."
+		default:
+			content = fmt.Sprintf("¡No es posible! *shakes head* %v", err)
+		}
+	} else {
+		content = fmt.Sprintf("🌱 *Tuco nods slowly* Your seed `%s` goes into a fresh shoe, dealt from this round on. Check it with `/verify` once the shoe is retired.", strings.TrimSpace(seed))
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to client seed: %v", err)
+	}
+}
+
+// modalTextValue returns the value of a text input in a submitted modal
+func modalTextValue(data discordgo.ModalSubmitInteractionData, customID string) string {
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, rowComponent := range row.Components {
+			if input, ok := rowComponent.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
+
+// postCommitment posts the commitment for the channel's next shoe before any cards are dealt
+func (b *Bot) postCommitment(s *discordgo.Session, channelID string) {
+	fair := b.fairShufflerForChannel(channelID)
+	if fair == nil {
+		return
+	}
+
+	message := fmt.Sprintf("🔒 *Tuco seals an envelope* The next shoe is committed: `%s`\nAdd your own client seed while betting and it comes out for the deal, the server seed is revealed when the shoe is retired.",
+		fair.NextCommitment())
+	if _, err := s.ChannelMessageSend(channelID, message); err != nil {
+		log.Printf("Error posting shoe commitment: %v", err)
+	}
+}
+
+// announceRevealedShoes posts the server seeds of any shoes retired since the last announcement
+func (b *Bot) announceRevealedShoes(s *discordgo.Session, channelID string) {
+	fair := b.fairShufflerForChannel(channelID)
+	if fair == nil {
+		return
+	}
+
+	for _, shoe := range fair.TakeRevealed() {
+		if _, err := s.ChannelMessageSendEmbed(channelID, createRevealedShoeEmbed(shoe)); err != nil {
+			log.Printf("Error announcing revealed shoe: %v", err)
+		}
+	}
+}
+
+// createRevealedShoeEmbed shows everything needed to verify a retired shoe
+func createRevealedShoeEmbed(shoe blackjack.FairShoe) *discordgo.MessageEmbed {
+	clientSeeds := strings.Join(shoe.ClientSeedValues(), ",")
+	clientSeedsValue := "None"
+	if clientSeeds != "" {
+		clientSeedsValue = fmt.Sprintf("`%s`", clientSeeds)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "🔓 Shoe Retired",
+		Description: "*Tuco opens the envelope* Check my shoe yourself with `/verify`, ¡no hay trampa!",
+		Color:       0xFFD700, // Gold color
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Commitment", Value: fmt.Sprintf("`%s`", shoe.Commitment)},
+			{Name: "Server Seed", Value: fmt.Sprintf("`%s`", shoe.ServerSeed)},
+			{Name: "Client Seeds", Value: clientSeedsValue},
+			{Name: "Decks", Value: fmt.Sprintf("%d", shoe.Decks), Inline: true},
+		},
+	}
+}
+
+// createFairField shows the commitments for the shoe in play and the next one
+func createFairField(fair *blackjack.FairShuffler) *discordgo.MessageEmbedField {
+	value := fmt.Sprintf("Next shoe: `%s`", fair.NextCommitment())
+	if current := fair.CurrentCommitment(); current != "" {
+		value = fmt.Sprintf("Current shoe: `%s`\n%s", current, value)
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "🔒 Provably Fair",
+		Value:  value,
+		Inline: false,
+	}
+}
+
+// createClientSeedButton creates the button players use to add a client seed
+func createClientSeedButton() discordgo.Button {
+	return discordgo.Button{
+		Label:    "🌱 Client Seed",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "client_seed",
+	}
+}
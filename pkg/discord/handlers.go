@@ -53,7 +53,26 @@ func (b *Bot) handleReady(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Successfully registered command: %v", walletCommand.Name)
 	}
 
+	// Register the verify command for provably fair shoes
+	_, err = s.ApplicationCommandCreate(s.State.User.ID, "", verifyCommand)
+	if err != nil {
+		log.Printf("Error creating command %v: %v", verifyCommand.Name, err)
+	} else {
+		log.Printf("Successfully registered command: %v", verifyCommand.Name)
+	}
+
+	// Register the train command for counting drills
+	_, err = s.ApplicationCommandCreate(s.State.User.ID, "", trainCommand)
+	if err != nil {
+		log.Printf("Error creating command %v: %v", trainCommand.Name, err)
+	} else {
+		log.Printf("Successfully registered command: %v", trainCommand.Name)
+	}
+
 	log.Printf("Finished registering slash commands")
+
+	// Bring back the tables that were live when the bot stopped
+	b.restoreTables(s)
 }
 
 func (b *Bot) handleInteractions(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -94,18 +113,63 @@ func (b *Bot) handleInteractions(s *discordgo.Session, i *discordgo.InteractionC
 		} else if i.ApplicationCommandData().Name == "wallet" {
 			log.Printf("Routing to wallet command handler")
 			b.handleWalletCommand(s, i)
+		} else if i.ApplicationCommandData().Name == "verify" {
+			log.Printf("Routing to verify command handler")
+			b.handleVerifyCommand(s, i)
+		} else if i.ApplicationCommandData().Name == "train" {
+			log.Printf("Routing to train command handler")
+			b.handleTrainCommand(s, i)
 		}
 
 	case discordgo.InteractionMessageComponent:
 		log.Printf("Received message component interaction: %s", i.MessageComponentData().CustomID)
 		b.handleMessageComponentInteraction(s, i)
+
+	case discordgo.InteractionModalSubmit:
+		log.Printf("Received modal submit: %s", i.ModalSubmitData().CustomID)
+		b.handleModalSubmit(s, i)
 	}
+
+	// Keep track of the table message and of who's still awake at the table
+	if i.Type == discordgo.InteractionMessageComponent && !strings.HasPrefix(i.MessageComponentData().CustomID, "wallet_") &&
+		!strings.HasPrefix(i.MessageComponentData().CustomID, "train_") && !strings.HasPrefix(i.MessageComponentData().CustomID, "back_bet_") {
+		if i.Message != nil {
+			b.setTableMessage(i.ChannelID, i.Message.ID)
+		}
+		if i.Member != nil && i.Member.User != nil {
+			b.clearStrikes(i.ChannelID, i.Member.User.ID)
+		}
+	}
+
+	// Save the channel's table after whatever the interaction changed, and
+	// show it to the spectators
+	channelID := b.tableChannel(i.ChannelID)
+	b.saveTable(channelID)
+	b.updateSpectators(s, channelID)
 }
 
 func (b *Bot) handleMessageComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	log.Printf("Handling component interaction: %s for user %s",
 		i.MessageComponentData().CustomID, i.Member.User.ID)
 
+	// Only players seated in the round can play it, everyone else is sent to watch
+	if b.redirectSpectator(s, i) {
+		return
+	}
+
+	// These buttons answer with a modal, which can't follow a deferred update
+	switch i.MessageComponentData().CustomID {
+	case "client_seed":
+		b.handleClientSeedButton(s, i)
+		return
+	case "custom_bet":
+		b.handleCustomBetButton(s, i)
+		return
+	case "train_answer":
+		b.handleTrainerAnswerButton(s, i)
+		return
+	}
+
 	// Acknowledge the interaction immediately
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredMessageUpdate,
@@ -124,6 +188,9 @@ func (b *Bot) handleMessageComponentInteraction(s *discordgo.Session, i *discord
 	case customID == "start_game":
 		b.handleStartGame(s, i)
 
+	case customID == "add_seat":
+		b.handleAddSeat(s, i)
+
 	case customID == "hit" || customID == "stand":
 		b.handleGameAction(s, i)
 
@@ -151,6 +218,33 @@ func (b *Bot) handleMessageComponentInteraction(s *discordgo.Session, i *discord
 	case customID == "insurance":
 		b.handleInsurance(s, i)
 
+	case customID == "surrender":
+		b.handleSurrender(s, i)
+
+	case customID == "hint":
+		b.handleHint(s, i)
+
+	case customID == "train_deal":
+		b.handleTrainerDeal(s, i)
+
+	case customID == "train_stop":
+		b.handleTrainerStop(s, i)
+
+	case customID == "rebet":
+		b.handleRebet(s, i, 1)
+
+	case customID == "double_last_bet":
+		b.handleRebet(s, i, 2)
+
+	case strings.HasPrefix(customID, "add_bot_"):
+		b.handleAddBot(s, i, strings.TrimPrefix(customID, "add_bot_"))
+
+	case strings.HasPrefix(customID, "back_bet_"):
+		b.handleBackBet(s, i, strings.TrimPrefix(customID, "back_bet_"))
+
+	case strings.HasPrefix(customID, "side_bet_"):
+		b.handleSideBet(s, i, strings.TrimPrefix(customID, "side_bet_"))
+
 	case strings.HasPrefix(customID, "bet_"):
 		betAmount, err := strconv.ParseInt(strings.TrimPrefix(customID, "bet_"), 10, 64)
 		if err != nil {
@@ -209,6 +303,18 @@ func (b *Bot) handleJoinGame(s *discordgo.Session, i *discordgo.InteractionCreat
 		}
 	}
 
+	// Players kicked for falling asleep at the table have to wait it out
+	if until, kicked := b.kickedUntilTime(i.ChannelID, i.Member.User.ID); kicked {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: fmt.Sprintf("*Tuco crosses his arms* You fell asleep at my table one time too many, amigo. Come back <t:%d:R>.", until.Unix()),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return
+	}
+
 	// Check if player is already in the lobby
 	if _, alreadyJoined := lobby.Players[i.Member.User.ID]; alreadyJoined {
 		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
@@ -260,15 +366,26 @@ func (b *Bot) handleStartGame(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Create a new game
-	game := blackjack.NewGame(i.ChannelID, b.repo)
+	game := blackjack.NewGame(i.ChannelID, b.repo, lobby.Rules, b.shufflerForChannel(i.ChannelID))
+	game.Timers = b.turnTimers
+	game.SetSideBetRegistry(b.sideBets)
 
-	// Add all players from the lobby
+	// Add all players from the lobby, with any extra seats they took
 	for playerID := range lobby.Players {
 		if err := game.AddPlayer(playerID); err != nil {
 			log.Printf("Error adding player %s to game: %v", playerID, err)
+			continue
+		}
+		for seat := 1; seat < lobby.seats(playerID); seat++ {
+			if _, err := game.AddSeat(playerID); err != nil {
+				log.Printf("Error adding seat for player %s to game: %v", playerID, err)
+			}
 		}
 	}
 
+	// Commit to the shoe before Start gets a chance to shuffle it
+	b.postCommitment(s, i.ChannelID)
+
 	// Start the game (this will transition to betting phase)
 	if err := game.Start(); err != nil {
 		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
@@ -289,27 +406,7 @@ func (b *Bot) handleStartGame(s *discordgo.Session, i *discordgo.InteractionCrea
 	b.mu.Unlock()
 
 	// Create betting buttons for all players
-	components := []discordgo.MessageComponent{
-		discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{
-				discordgo.Button{
-					Label:    "Bet $5",
-					Style:    discordgo.SuccessButton,
-					CustomID: "bet_5",
-				},
-				discordgo.Button{
-					Label:    "Bet $10",
-					Style:    discordgo.SuccessButton,
-					CustomID: "bet_10",
-				},
-				discordgo.Button{
-					Label:    "Bet $25",
-					Style:    discordgo.SuccessButton,
-					CustomID: "bet_25",
-				},
-			},
-		},
-	}
+	components := createGameButtons(game)
 
 	// Update the existing message with the betting UI
 	content := "¡Vamos a jugar! *Tuco shuffles the cards with flair* Place your bets to begin!"
@@ -413,7 +510,7 @@ func (b *Bot) handleGameAction(s *discordgo.Session, i *discordgo.InteractionCre
 	// Check the game state before calling CompleteGameIfDone
 	log.Printf("Game state before CompleteGameIfDone: %s", game.State)
 
-	gameComplete, err := game.CompleteGameIfDone(ctx, b.walletService)
+	gameComplete, err := game.CompleteGameIfDone(ctx, b.tableWallet)
 	if err != nil {
 		log.Printf("Error completing game: %v", err)
 	}
@@ -508,6 +605,7 @@ func (b *Bot) handlePlayAgain(s *discordgo.Session, i *discordgo.InteractionCrea
 	lobby := &GameLobby{
 		OwnerID: i.Member.User.ID,
 		Players: make(map[string]bool),
+		Rules:   b.rulesForChannel(i.ChannelID),
 	}
 
 	// Add the owner as the first player
@@ -644,6 +742,7 @@ func (b *Bot) handleBlackjackCommand(s *discordgo.Session, i *discordgo.Interact
 	lobby := &GameLobby{
 		OwnerID: i.Member.User.ID,
 		Players: make(map[string]bool),
+		Rules:   b.rulesForChannel(i.ChannelID),
 	}
 	lobby.Players[i.Member.User.ID] = true
 
@@ -784,9 +883,21 @@ func (b *Bot) handleBet(s *discordgo.Session, i *discordgo.InteractionCreate, be
 		return fmt.Errorf("bet validation failed: %w", err)
 	}
 
+	// Check the amount against the table's limits and what the player can cover
+	if err := b.checkBetAmount(game, i.Member.User.ID, betAmount); err != nil {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: betErrorMessage(game, err),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return fmt.Errorf("bet validation failed: %w", err)
+	}
+
 	// Use the service method to place bet and update wallet
 	ctx := context.Background()
-	loanGiven, err := game.PlaceBetWithWalletUpdate(ctx, i.Member.User.ID, betAmount, b.walletService)
+	loanGiven, err := game.PlaceBetWithWalletUpdate(ctx, i.Member.User.ID, betAmount, b.tableWallet)
 	if err != nil {
 		log.Printf("Error placing bet for player %s: %v", i.Member.User.ID, err)
 		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
@@ -799,6 +910,9 @@ func (b *Bot) handleBet(s *discordgo.Session, i *discordgo.InteractionCreate, be
 		return fmt.Errorf("error placing bet: %v", err)
 	}
 
+	// Remember the bet for the rebet buttons next round
+	b.setLastBet(i.ChannelID, i.Member.User.ID, betAmount)
+
 	// If a loan was given, notify the player
 	if loanGiven {
 		loanAmount := b.walletService.GetStandardLoanIncrement()
@@ -823,6 +937,7 @@ func (b *Bot) handleBet(s *discordgo.Session, i *discordgo.InteractionCreate, be
 	// Check if all players have placed bets and game has transitioned to a new state
 	if game.State == entities.StateDealing ||
 		game.State == entities.StatePlaying ||
+		game.State == blackjack.StateInsurance ||
 		game.State == blackjack.StateSplitting ||
 		game.State == blackjack.StateSpecialBets ||
 		game.State == entities.StateDealer ||
@@ -837,12 +952,59 @@ func (b *Bot) handleBet(s *discordgo.Session, i *discordgo.InteractionCreate, be
 	}
 }
 
+// RegisterSideBet offers a side bet at the bot's tables, replacing the one with
+// the same name, e.g. to change its pay table. Call it before the bot starts.
+func (b *Bot) RegisterSideBet(sideBet blackjack.SideBet) {
+	b.sideBets.Register(sideBet)
+}
+
+// handleSideBet places a side bet for a player who hasn't made their main bet yet
+func (b *Bot) handleSideBet(s *discordgo.Session, i *discordgo.InteractionCreate, name string) error {
+	b.mu.RLock()
+	game, exists := b.games[i.ChannelID]
+	b.mu.RUnlock()
+
+	if !exists {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: "¡Ay caramba! *looks around confused* No game found in this channel!",
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return fmt.Errorf("no game found in channel %s", i.ChannelID)
+	}
+
+	ctx := context.Background()
+	if err := game.PlaceSideBet(ctx, name, i.Member.User.ID, SideBetAmount, b.tableWallet); err != nil {
+		log.Printf("Error placing %s side bet for player %s: %v", name, i.Member.User.ID, err)
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: fmt.Sprintf("¡No es posible! *shakes head* %v", err),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return fmt.Errorf("error placing side bet: %w", err)
+	}
+
+	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("*Tuco grins* ¡Ay, a gambler! $%d on the side, we'll see how it does when the cards come out.", SideBetAmount),
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		log.Printf("Error sending side bet confirmation: %v", err)
+	}
+
+	return b.updateBettingUI(s, i, game)
+}
+
 func (b *Bot) updateBettingUI(s *discordgo.Session, i *discordgo.InteractionCreate, game *blackjack.Game) error {
 	log.Printf("Updating betting UI")
 
 	// Get player information from the service layer
 	ctx := context.Background()
-	playersInfo, err := game.GetAllPlayersInfo(ctx, b.walletService)
+	playersInfo, err := game.GetAllPlayersInfo(ctx, b.tableWallet)
 	if err != nil {
 		log.Printf("Error getting player information: %v", err)
 	}
@@ -851,14 +1013,15 @@ func (b *Bot) updateBettingUI(s *discordgo.Session, i *discordgo.InteractionCrea
 	var highestBalance int64
 	var richestPlayerID string
 	for _, playerInfo := range playersInfo {
-		if playerInfo.WalletBalance > highestBalance {
+		// Computer players' house money doesn't count
+		if !blackjack.IsBotPlayer(playerInfo.PlayerID) && playerInfo.WalletBalance > highestBalance {
 			highestBalance = playerInfo.WalletBalance
 			richestPlayerID = playerInfo.PlayerID
 		}
 	}
 
 	// Get current betting player info
-	currentPlayerInfo, err := game.GetCurrentBettingPlayerInfo(ctx, b.walletService)
+	currentPlayerInfo, err := game.GetCurrentBettingPlayerInfo(ctx, b.tableWallet)
 	if err != nil {
 		log.Printf("Error getting current betting player info: %v", err)
 		return err
@@ -872,26 +1035,44 @@ func (b *Bot) updateBettingUI(s *discordgo.Session, i *discordgo.InteractionCrea
 		Fields:      []*discordgo.MessageEmbedField{},
 	}
 
-	// Add fields for each player
-	for _, playerInfo := range playersInfo {
+	// Add a field for each player, their seats come one after another
+	for start := 0; start < len(playersInfo); {
+		playerInfo := playersInfo[start]
+		end := start + 1
+		for end < len(playersInfo) && playersInfo[end].PlayerID == playerInfo.PlayerID {
+			end++
+		}
+		seats := playersInfo[start:end]
+		start = end
+
 		// Get the Discord username
-		user, err := s.User(playerInfo.PlayerID)
+		username, err := playerUsername(s, playerInfo.PlayerID)
 		if err != nil {
 			log.Printf("Error getting user %s: %v", playerInfo.PlayerID, err)
 			continue
 		}
 
-		// Format the player's status
+		// Format the player's status, with a line for each seat
 		statusText := fmt.Sprintf("Balance: $%d", playerInfo.WalletBalance)
-		if playerInfo.HasBet {
-			statusText += fmt.Sprintf("\nBet: $%d", playerInfo.BetAmount)
-		} else {
-			statusText += "\nNo bet placed yet"
+		isCurrentTurn := false
+		for _, seat := range seats {
+			if len(seats) > 1 {
+				statusText += fmt.Sprintf("\n**Seat %d**", game.SeatNumber(seat.HandID))
+			}
+			if seat.HasBet {
+				statusText += fmt.Sprintf("\nBet: $%d", seat.BetAmount)
+			} else {
+				statusText += "\nNo bet placed yet"
+			}
+			for _, sideBet := range game.SideBets[seat.HandID] {
+				statusText += fmt.Sprintf("\nSide bet: $%d", sideBet.Amount)
+			}
+			isCurrentTurn = isCurrentTurn || seat.IsCurrentTurn
 		}
 
 		// Highlight the current player
-		fieldName := user.Username
-		if playerInfo.IsCurrentTurn {
+		fieldName := username
+		if isCurrentTurn {
 			fieldName += " 👈 YOUR TURN"
 		}
 
@@ -913,45 +1094,41 @@ func (b *Bot) updateBettingUI(s *discordgo.Session, i *discordgo.InteractionCrea
 	// Only show bet buttons if it's someone's turn
 	if currentPlayerInfo != nil {
 		// Get the current player's user object
-		currentUser, err := s.User(currentPlayerInfo.PlayerID)
+		currentUsername, err := playerUsername(s, currentPlayerInfo.PlayerID)
 		if err == nil {
-			// Add a message showing whose turn it is
-			embed.Description = fmt.Sprintf("It's %s's turn to bet!", currentUser.Username)
+			// Add a message showing whose turn it is, and for which seat if they play more than one
+			embed.Description = fmt.Sprintf("It's %s's turn to bet!", currentUsername)
+			if len(game.SeatsForPlayer(currentPlayerInfo.PlayerID)) > 1 {
+				embed.Description = fmt.Sprintf("It's %s's turn to bet on seat %d!", currentUsername, game.SeatNumber(currentPlayerInfo.HandID))
+			}
 		}
 
 		// Only show bet buttons to the current player
 		if i.Member != nil && i.Member.User != nil && i.Member.User.ID == currentPlayerInfo.PlayerID {
-			// Fixed bet options: $5, $10, and $25 only
-			betOptions := []int64{5, 10, 25}
-
-			// Create bet buttons
-			betButtons := []discordgo.MessageComponent{}
-			for _, amount := range betOptions {
-				// Always show all bet options since we have loan functionality
-				betButtons = append(betButtons, discordgo.Button{
-					Label:    fmt.Sprintf("$%d", amount),
-					Style:    discordgo.SuccessButton,
-					CustomID: fmt.Sprintf("bet_%d", amount),
-				})
-			}
+			// Bet options come from the table's limits, plus the last bet if there was one
+			components = append(components, createBetButtons(game.Rules, b.lastBet(i.ChannelID, currentPlayerInfo.PlayerID))...)
 
-			// Split buttons into rows of 5 max
-			for i := 0; i < len(betButtons); i += 5 {
-				end := i + 5
-				if end > len(betButtons) {
-					end = len(betButtons)
-				}
-
-				components = append(components, discordgo.ActionsRow{
-					Components: betButtons[i:end],
-				})
-			}
+			// Side bets go down before the main bet
+			components = append(components, createSideBetButtons(game)...)
 		}
 	} else {
 		// No current player - game might be waiting to start or already in progress
 		embed.Description = "Waiting for players to join..."
 	}
 
+	// Show how long the current player has left to bet
+	if timerField := createTurnTimerField(game, s, i.GuildID); timerField != nil {
+		embed.Fields = append(embed.Fields, timerField)
+	}
+
+	// On a provably fair table anyone can add a client seed while betting
+	if fair := b.fairShufflerForChannel(i.ChannelID); fair != nil {
+		embed.Fields = append(embed.Fields, createFairField(fair))
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{createClientSeedButton()},
+		})
+	}
+
 	// Edit the message with the updated UI
 	var msgErr error
 	_, msgErr = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
@@ -967,13 +1144,16 @@ func (b *Bot) updateBettingUI(s *discordgo.Session, i *discordgo.InteractionCrea
 func (b *Bot) updateGameUI(s *discordgo.Session, i *discordgo.InteractionCreate, game *blackjack.Game) error {
 	log.Printf("Updating game UI - Game state: %s, PayoutsProcessed: %v", game.State, game.PayoutsProcessed)
 
+	// Reveal any shoe that was retired when the cards were dealt
+	b.announceRevealedShoes(s, i.ChannelID)
+
 	// If the game is in DEALER state, play the dealer's turn and transition to COMPLETE
 	if game.State == entities.StateDealer && !game.PayoutsProcessed {
 		log.Printf("Game is in DEALER state, playing dealer's turn")
 		ctx := context.Background()
 		
 		// Call CompleteGameIfDone to handle dealer play and payouts
-		gameComplete, err := game.CompleteGameIfDone(ctx, b.walletService)
+		gameComplete, err := game.CompleteGameIfDone(ctx, b.tableWallet)
 		if err != nil {
 			log.Printf("Error during dealer play: %v", err)
 		}
@@ -996,12 +1176,27 @@ func (b *Bot) updateGameUI(s *discordgo.Session, i *discordgo.InteractionCreate,
 		if currentPlayerID == "" {
 			content = "¡Vamos a jugar! *Tuco examines the cards*"
 		} else {
-			currentPlayer, err := s.User(currentPlayerID)
+			currentPlayer, err := playerUsername(s, game.HandOwner(currentPlayerID))
 			if err != nil {
 				log.Printf("Error getting user %s: %v", currentPlayerID, err)
 				content = "¡Vamos a jugar! *Tuco examines the cards*"
 			} else {
-				content = fmt.Sprintf("*Tuco looks at %s* You have a pair! Would you like to split them?", currentPlayer.Username)
+				content = fmt.Sprintf("*Tuco looks at %s* You have a pair! Would you like to split them?", currentPlayer)
+			}
+		}
+	case blackjack.StateInsurance:
+		// Get the current player whose turn it is to decide before the peek
+		currentPlayerID, err := game.GetCurrentSpecialBetsPlayerID()
+		if err != nil {
+			log.Printf("Error getting current insurance player: %v", err)
+			content = "*Tuco eyes his hole card* Before I peek..."
+		} else {
+			currentPlayer, err := playerUsername(s, game.HandOwner(currentPlayerID))
+			if err != nil {
+				log.Printf("Error getting user %s: %v", currentPlayerID, err)
+				content = "*Tuco eyes his hole card* Before I peek..."
+			} else {
+				content = fmt.Sprintf("*Tuco taps his hole card and looks at %s* Before I peek, amigo... insurance?", currentPlayer)
 			}
 		}
 	case blackjack.StateSpecialBets:
@@ -1013,12 +1208,12 @@ func (b *Bot) updateGameUI(s *discordgo.Session, i *discordgo.InteractionCreate,
 		} else if currentPlayerID == "" {
 			content = "¡Vamos a jugar! *Tuco examines the cards*"
 		} else {
-			currentPlayer, err := s.User(currentPlayerID)
+			currentPlayer, err := playerUsername(s, game.HandOwner(currentPlayerID))
 			if err != nil {
 				log.Printf("Error getting user %s: %v", currentPlayerID, err)
 				content = "¡Vamos a jugar! *Tuco examines the cards*"
 			} else {
-				content = fmt.Sprintf("*Tuco looks at %s* Any special bets, amigo?", currentPlayer.Username)
+				content = fmt.Sprintf("*Tuco looks at %s* Any special bets, amigo?", currentPlayer)
 			}
 		}
 	case entities.StateDealing:
@@ -1027,19 +1222,19 @@ func (b *Bot) updateGameUI(s *discordgo.Session, i *discordgo.InteractionCreate,
 		// Get the current player's name whose turn it is to play
 		if len(game.PlayerOrder) > 0 && game.CurrentTurn < len(game.PlayerOrder) {
 			currentPlayerID := game.PlayerOrder[game.CurrentTurn]
-			currentPlayer, err := s.User(currentPlayerID)
+			currentPlayer, err := playerUsername(s, game.HandOwner(currentPlayerID))
 			if err != nil {
 				log.Printf("Error getting user %s: %v", currentPlayerID, err)
 				content = "¡Vamos a jugar! *Tuco waits for players to make their moves*"
 			} else {
 				// Get player's wallet
-				wallet, _, err := b.walletService.GetOrCreateWallet(context.Background(), currentPlayerID)
+				wallet, _, err := b.tableWallet.GetOrCreateWallet(context.Background(), game.HandOwner(currentPlayerID))
 				walletInfo := fmt.Sprintf(" ($%d)", wallet.Balance)
 				if err != nil {
 					log.Printf("Error getting wallet for player %s: %v", currentPlayerID, err)
 					walletInfo = ""
 				}
-				content = fmt.Sprintf("¡Vamos a jugar! *Tuco looks at %s%s* It's your turn to play!", currentPlayer.Username, walletInfo)
+				content = fmt.Sprintf("¡Vamos a jugar! *Tuco looks at %s%s* It's your turn to play!", currentPlayer, walletInfo)
 			}
 		} else {
 			content = "¡Vamos a jugar! *Tuco waits for players to make their moves*"
@@ -1048,12 +1243,15 @@ func (b *Bot) updateGameUI(s *discordgo.Session, i *discordgo.InteractionCreate,
 		content = "¡El dealer está jugando! *Tuco flips cards dramatically*"
 	case entities.StateComplete:
 		content = "¡El juego ha terminado! *Tuco counts the chips with a grin*"
+		if game.DealerPeeked && blackjack.IsBlackjack(game.Dealer.Cards) {
+			content = "*Tuco peeks at his hole card and flips it over* ¡BLACKJACK! The house takes the bets, amigos."
+		}
 
 		// Process payouts for all players if they haven't been processed yet
 		if !game.PayoutsProcessed {
 			log.Printf("Processing payouts for completed game in channel %s", i.ChannelID)
 			ctx := context.Background()
-			if err := game.FinishGame(ctx, b.walletService); err != nil {
+			if err := game.FinishGame(ctx, b.tableWallet); err != nil {
 				log.Printf("Error finishing game: %v", err)
 			}
 		}
@@ -1227,7 +1425,7 @@ func (b *Bot) createWalletEmbed(userWallet *entities.Wallet, userID string, user
 	if userWallet.LoanAmount > 0 {
 		fields = append(fields, &discordgo.MessageEmbedField{
 			Name:  "Loan Amount",
-			Value: fmt.Sprintf("$%d", userWallet.LoanAmount),
+			Value: fmt.Sprintf("$%d", userWallet.LoanAmount) + loanDueLine(b.walletService, userID),
 		})
 	}
 
@@ -1325,7 +1523,7 @@ func (b *Bot) handleDeclineSplit(s *discordgo.Session, i *discordgo.InteractionC
 
 	// Check if it's this player's turn
 	currentPlayerID := game.GetCurrentSplittingPlayerID()
-	if currentPlayerID != playerID {
+	if game.HandOwner(currentPlayerID) != playerID {
 		// Not this player's turn
 		return fmt.Errorf("not your turn to split")
 	}
@@ -1350,7 +1548,7 @@ func (b *Bot) handleDeclineSpecial(s *discordgo.Session, i *discordgo.Interactio
 	}
 
 	// Verify game state
-	if game.State != blackjack.StateSpecialBets {
+	if game.State != blackjack.StateSpecialBets && game.State != blackjack.StateInsurance {
 		return fmt.Errorf("game is not in special bets state")
 	}
 
@@ -1363,7 +1561,7 @@ func (b *Bot) handleDeclineSpecial(s *discordgo.Session, i *discordgo.Interactio
 		return fmt.Errorf("error getting current player: %v", err)
 	}
 
-	if currentPlayerID != playerID {
+	if game.HandOwner(currentPlayerID) != playerID {
 		// Not this player's turn
 		return fmt.Errorf("not your turn for special bets")
 	}
@@ -1397,14 +1595,14 @@ func (b *Bot) handleSplit(s *discordgo.Session, i *discordgo.InteractionCreate)
 
 	// Check if it's this player's turn
 	currentPlayerID := game.GetCurrentSplittingPlayerID()
-	if currentPlayerID != playerID {
+	if game.HandOwner(currentPlayerID) != playerID {
 		// Not this player's turn
 		return fmt.Errorf("not your turn to split")
 	}
 
 	// Perform the split action
 	ctx := context.Background()
-	if err := game.Split(ctx, playerID, b.walletService); err != nil {
+	if err := game.Split(ctx, playerID, b.tableWallet); err != nil {
 		return fmt.Errorf("error splitting hand: %v", err)
 	}
 
@@ -1436,19 +1634,19 @@ func (b *Bot) handleDoubleDown(s *discordgo.Session, i *discordgo.InteractionCre
 		return fmt.Errorf("error getting current player: %v", err)
 	}
 
-	if currentPlayerID != playerID {
+	if game.HandOwner(currentPlayerID) != playerID {
 		// Not this player's turn
 		return fmt.Errorf("not your turn for special bets")
 	}
 
 	// Check if player is eligible for double down
-	if !game.IsEligibleForDoubleDown(playerID) {
+	if !game.IsEligibleForDoubleDown(currentPlayerID) {
 		return fmt.Errorf("you are not eligible to double down")
 	}
 
 	// Perform the double down action
 	ctx := context.Background()
-	if err := game.DoubleDown(ctx, playerID, b.walletService); err != nil {
+	if err := game.DoubleDown(ctx, playerID, b.tableWallet); err != nil {
 		return fmt.Errorf("error doubling down: %v", err)
 	}
 
@@ -1475,7 +1673,7 @@ func (b *Bot) handleInsurance(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Verify game state
-	if game.State != blackjack.StateSpecialBets {
+	if game.State != blackjack.StateSpecialBets && game.State != blackjack.StateInsurance {
 		return fmt.Errorf("game is not in special bets state")
 	}
 
@@ -1488,7 +1686,7 @@ func (b *Bot) handleInsurance(s *discordgo.Session, i *discordgo.InteractionCrea
 		return fmt.Errorf("error getting current player: %v", err)
 	}
 
-	if currentPlayerID != playerID {
+	if game.HandOwner(currentPlayerID) != playerID {
 		// Not this player's turn
 		return fmt.Errorf("not your turn for special bets")
 	}
@@ -1500,10 +1698,62 @@ func (b *Bot) handleInsurance(s *discordgo.Session, i *discordgo.InteractionCrea
 
 	// Perform the insurance action
 	ctx := context.Background()
-	if err := game.PlaceInsurance(ctx, playerID, b.walletService); err != nil {
+	if err := game.PlaceInsurance(ctx, playerID, b.tableWallet); err != nil {
 		return fmt.Errorf("error placing insurance: %v", err)
 	}
 
 	// Update the game UI
 	return b.updateGameUI(s, i, game)
 }
+
+func (b *Bot) handleSurrender(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	// Get the game for this channel
+	b.mu.Lock()
+	game, exists := b.games[i.ChannelID]
+	b.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("no game found for channel %s", i.ChannelID)
+	}
+
+	// Verify game state
+	if game.State != blackjack.StateSpecialBets && game.State != blackjack.StateInsurance {
+		return fmt.Errorf("game is not in special bets state")
+	}
+
+	// Get the player ID
+	playerID := i.Member.User.ID
+
+	// Check if it's this player's turn
+	currentPlayerID, err := game.GetCurrentSpecialBetsPlayerID()
+	if err != nil {
+		return fmt.Errorf("error getting current player: %v", err)
+	}
+
+	if game.HandOwner(currentPlayerID) != playerID {
+		// Not this player's turn
+		return fmt.Errorf("not your turn for special bets")
+	}
+
+	// Check if surrender is available, only early surrender is offered before the peek
+	if !game.IsEligibleForSurrender(currentPlayerID) ||
+		(game.State == blackjack.StateInsurance && !game.IsEligibleForEarlySurrender(currentPlayerID)) {
+		return fmt.Errorf("you are not eligible to surrender")
+	}
+
+	// Perform the surrender action, half the bet is refunded at payout time
+	if err := game.Surrender(playerID); err != nil {
+		return fmt.Errorf("error surrendering: %v", err)
+	}
+
+	// Let the table know
+	message := getRandomSurrenderMessage(fmt.Sprintf("<@%s>", playerID))
+	_, err = s.ChannelMessageSend(i.ChannelID, message)
+	if err != nil {
+		log.Printf("Error sending surrender message: %v", err)
+		// Continue even if message fails to send
+	}
+
+	// Update the game UI
+	return b.updateGameUI(s, i, game)
+}
@@ -20,13 +20,13 @@ func (m *MockWalletService) GetOrCreateWallet(ctx context.Context, userID string
 	return args.Get(0).(*entities.Wallet), args.Bool(1), args.Error(2)
 }
 
-func (m *MockWalletService) AddFunds(ctx context.Context, userID string, amount int64, description string) error {
-	args := m.Called(ctx, userID, amount, description)
+func (m *MockWalletService) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	args := m.Called(ctx, userID, tableID, amount, transactionType, referenceID, description)
 	return args.Error(0)
 }
 
-func (m *MockWalletService) RemoveFunds(ctx context.Context, userID string, amount int64, description string) error {
-	args := m.Called(ctx, userID, amount, description)
+func (m *MockWalletService) RemoveFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	args := m.Called(ctx, userID, tableID, amount, transactionType, referenceID, description)
 	return args.Error(0)
 }
 
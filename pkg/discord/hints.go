@@ -0,0 +1,55 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+	"github.com/fadedpez/tucoramirez/pkg/services/strategy"
+)
+
+// handleHint tells the player what basic strategy says for the hand they're
+// deciding on. Only they see it, and it doesn't change the table.
+func (b *Bot) handleHint(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b.mu.RLock()
+	game, exists := b.games[i.ChannelID]
+	b.mu.RUnlock()
+
+	var content string
+	if !exists {
+		content = "¡Ay caramba! *looks around confused* No game found in this channel!"
+	} else if hint, err := game.Hint(i.Member.User.ID); err != nil {
+		switch {
+		case errors.Is(err, blackjack.ErrNotPlayerTurn):
+			content = "*Tuco wags a finger* ¡Paciencia! I only whisper to the player whose turn it is."
+		default:
+			content = fmt.Sprintf("¡No es posible! *shakes head* %v", err)
+		}
+	} else {
+		content = hintMessage(game, hint)
+	}
+
+	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		log.Printf("Error sending hint: %v", err)
+	}
+}
+
+// hintMessage is how Tuco passes on a hint. Before the playing phase the hint
+// may be for a later decision, so it says to pass on what's offered now first.
+func hintMessage(game *blackjack.Game, hint strategy.Action) string {
+	play := fmt.Sprintf("**%s**", hint)
+	switch {
+	case game.State == blackjack.StateSplitting && hint != strategy.ActionSplit:
+		play = fmt.Sprintf("keep the pair together, then **%s**", hint)
+	case (game.State == blackjack.StateInsurance || game.State == blackjack.StateSpecialBets) &&
+		(hint == strategy.ActionHit || hint == strategy.ActionStand):
+		play = fmt.Sprintf("pass on the special bets, then **%s**", hint)
+	}
+	return fmt.Sprintf("🧠 *Tuco leans in and whispers* The book says %s, amigo. Don't tell the others.", play)
+}
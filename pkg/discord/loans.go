@@ -0,0 +1,133 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+	"github.com/fadedpez/tucoramirez/pkg/services/wallet"
+)
+
+// LoanSchedulerInterval is how often the bot charges interest on loans and
+// chases the borrowers
+const LoanSchedulerInterval = time.Minute
+
+// runLoanScheduler charges interest on open loans and sends the reminders
+// borrowers are owed, until the bot stops
+func (b *Bot) runLoanScheduler() {
+	ticker := time.NewTicker(LoanSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopChan:
+			return
+		case now := <-ticker.C:
+			b.checkLoans(now)
+		}
+	}
+}
+
+// checkLoans charges the interest that's come due on open loans and reminds
+// the borrowers who should hear from Tuco
+func (b *Bot) checkLoans(now time.Time) {
+	ctx := context.Background()
+
+	charged, err := b.walletService.AccrueInterest(ctx, now)
+	if err != nil {
+		log.Printf("Error charging interest on loans: %v", err)
+	}
+	if len(charged) > 0 {
+		log.Printf("Charged interest on %d loans", len(charged))
+	}
+
+	reminders, err := b.walletService.DueReminders(ctx, now)
+	if err != nil {
+		log.Printf("Error getting loan reminders: %v", err)
+	}
+	for _, reminder := range reminders {
+		b.sendLoanReminder(reminder)
+	}
+}
+
+// sendLoanReminder sends a borrower a direct message about their loan
+func (b *Bot) sendLoanReminder(reminder wallet.LoanReminder) {
+	channel, err := b.session.UserChannelCreate(reminder.Loan.UserID)
+	if err != nil {
+		log.Printf("Error opening a direct message to user %s: %v", reminder.Loan.UserID, err)
+		return
+	}
+	if _, err := b.session.ChannelMessageSend(channel.ID, loanReminderMessage(reminder, b.walletService.LoanTerms())); err != nil {
+		log.Printf("Error sending loan reminder to user %s: %v", reminder.Loan.UserID, err)
+	}
+}
+
+// loanReminderMessage is what Tuco has to say about a loan, harsher the
+// longer it goes unpaid
+func loanReminderMessage(reminder wallet.LoanReminder, terms wallet.LoanTerms) string {
+	loan := reminder.Loan
+	owed := loan.Outstanding()
+	due := fmt.Sprintf("<t:%d:R>", loan.DueAt.Unix())
+
+	switch {
+	case reminder.Level <= wallet.ReminderComingDue:
+		return fmt.Sprintf("¡Oye, amigo! Just a friendly word from your old friend Tuco. The $%d you owe me is due %s. "+
+			"Repay it from `/wallet` before then and we stay friends, sí?", owed, due)
+	case reminder.Level == wallet.ReminderDue:
+		return fmt.Sprintf("Today is the day, amigo. You owe Tuco $%d and it is due **now**. "+
+			"Pay me, or I start taking %d%% of everything you win at my tables.", owed, terms.CollectionCut)
+	case reminder.Level == wallet.ReminderOverdue:
+		return fmt.Sprintf("You are late, amigo. $%d, and the interest keeps growing while you hide. "+
+			"From now on, every time you win, Tuco takes his %d%% first.", owed, terms.CollectionCut)
+	case reminder.Level == wallet.ReminderOverdue+1:
+		return fmt.Sprintf("I am losing my patience with you. **$%d**. My cousins are asking about you, "+
+			"and they are not as nice as me.", owed)
+	default:
+		return fmt.Sprintf("There are two kinds of people in this world, my friend: those who pay Tuco, and those who dig. "+
+			"**$%d**. You are holding a shovel.", owed)
+	}
+}
+
+// getCollectionResults describes what Tuco took from the round's winnings
+// for overdue loans, or returns an empty string if he took nothing
+func getCollectionResults(game *blackjack.Game, s SessionInterface, guildID string) string {
+	if len(game.Collections) == 0 {
+		return ""
+	}
+
+	playerIDs := make([]string, 0, len(game.Collections))
+	for playerID := range game.Collections {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	results := "\n💰 **Tuco's cut**\n"
+	for _, playerID := range playerIDs {
+		results += fmt.Sprintf("**%s** paid **$%d** on an overdue loan\n", getPlayerDisplayName(s, guildID, playerID), game.Collections[playerID])
+	}
+	return results
+}
+
+// loanDueLine tells a borrower when their oldest open loan is due, or returns
+// an empty string if there's none
+func loanDueLine(walletService *wallet.Service, userID string) string {
+	loans, err := walletService.GetLoans(context.Background(), userID)
+	if err != nil {
+		log.Printf("Error getting loans for user %s: %v", userID, err)
+		return ""
+	}
+	for _, loan := range loans {
+		if loan.Status != entities.LoanStatusOpen {
+			continue
+		}
+		if loan.Overdue(time.Now()) {
+			return fmt.Sprintf("\n⚠️ Overdue since <t:%d:R>, Tuco takes his cut of your winnings", loan.DueAt.Unix())
+		}
+		return fmt.Sprintf("\nDue <t:%d:R>", loan.DueAt.Unix())
+	}
+	return ""
+}
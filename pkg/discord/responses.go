@@ -26,113 +26,46 @@ func createGameEmbed(game *blackjack.Game, s SessionInterface, guildID string) *
 	dealerField := createDealerField(game)
 	embed.Fields = append(embed.Fields, dealerField)
 
-	// Get current player's turn if game is in playing state
-	var currentPlayerID string
-	if game.State == entities.StatePlaying {
-		currentPlayer, err := game.GetCurrentTurnPlayerID()
-		if err == nil {
-			currentPlayerID = currentPlayer
-		}
-	}
+	// Get the hand whose turn it is in the current phase
+	currentHandID := game.CurrentTurnHandID()
 
 	// Add all players' hands
-	// Iterate through PlayerOrder to maintain consistent display order
-	for _, playerID := range game.PlayerOrder {
-		hand := game.Players[playerID]
-
-		// Don't skip parent hands of splits anymore - we want to show them
-
-		playerScore := blackjack.GetBestScore(hand.Cards)
-		playerStatus := getStatusMessage(hand.Status)
-
-		// Get member info for display name
-		member, err := s.GuildMember(guildID, playerID)
-		playerName := "Unknown Player"
-		if err == nil && member.Nick != "" {
-			playerName = member.Nick
-		} else if err == nil && member.User != nil {
-			playerName = member.User.Username
-		}
-
-		// Add bet amount if available
-		if bet, hasBet := game.Bets[playerID]; hasBet {
-			playerName = fmt.Sprintf("%s (Bet: $%d)", playerName, bet)
-		}
-
-		// Add turn indicator if it's this player's turn
-		var namePrefix string
-		if (game.State == entities.StatePlaying && playerID == currentPlayerID) ||
-			(game.State == entities.StateBetting && len(game.PlayerOrder) > 0 &&
-				game.CurrentBettingPlayer < len(game.PlayerOrder) &&
-				game.PlayerOrder[game.CurrentBettingPlayer] == playerID) ||
-			(game.State == "SPECIAL_BETS" && len(game.PlayerOrder) > 0 &&
-				game.CurrentSpecialBetsTurn < len(game.PlayerOrder) &&
-				game.PlayerOrder[game.CurrentSpecialBetsTurn] == playerID) {
-			namePrefix = "👉 " // Pointing finger emoji to indicate current turn
-		} else {
-			namePrefix = "" // No emoji for other players
-		}
-
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:   fmt.Sprintf("%s%s", namePrefix, playerName),
-			Value:  fmt.Sprintf("%s\nScore: %d%s", FormatCards(hand.Cards), playerScore, playerStatus),
-			Inline: true,
-		})
-	}
-
-	// Add split hands
-	for handID, hand := range game.Players {
-		// Skip non-split hands (they're already displayed above)
-		if !hand.IsSplit() {
+	// Iterate in turn order so split hands show up right after the hand they came from
+	for _, handID := range game.HandIDsInOrder() {
+		hand, exists := game.Players[handID]
+		if !exists {
 			continue
 		}
 
-		// Get the parent player ID
-		parentID := hand.GetParentHandID()
-		if parentID == "" {
-			continue // Skip if no parent ID (shouldn't happen for valid split hands)
-		}
+		playerScore := blackjack.GetBestScore(hand.Cards)
+		playerStatus := getStatusMessage(hand.Status)
 
-		// Skip the "Unknown Player" split hand
-		// The split hand ID is in the format "playerID_split"
-		if handID != parentID+"_split" {
-			continue
-		}
-
-		// Get member info for display name
-		member, err := s.GuildMember(guildID, parentID)
-		playerName := "Unknown Player"
-		if err == nil && member.Nick != "" {
-			playerName = member.Nick
-		} else if err == nil && member.User != nil {
-			playerName = member.User.Username
-		}
-		playerName = fmt.Sprintf("%s (Split)", playerName)
+		// Seats and split hands are shown under the name of the player who owns them
+		playerName := handDisplayName(game, s, guildID, handID)
 
 		// Add bet amount if available
 		if bet, hasBet := game.Bets[handID]; hasBet {
 			playerName = fmt.Sprintf("%s (Bet: $%d)", playerName, bet)
 		}
 
-		// Calculate score and status
-		playerScore := blackjack.GetBestScore(hand.Cards)
-		playerStatus := getStatusMessage(hand.Status)
-
 		// Add turn indicator if it's this hand's turn
 		var namePrefix string
-		if game.State == entities.StatePlaying && handID == currentPlayerID {
+		if handID == currentHandID {
 			namePrefix = "👉 " // Pointing finger emoji to indicate current turn
-		} else {
-			namePrefix = "" // No emoji for other hands
 		}
 
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   fmt.Sprintf("%s%s", namePrefix, playerName),
-			Value:  fmt.Sprintf("%s\nScore: %d%s", FormatCards(hand.Cards), playerScore, playerStatus),
+			Value:  fmt.Sprintf("%s\nScore: %d%s%s", FormatCards(hand.Cards), playerScore, playerStatus, getSideBetLines(game, handID)),
 			Inline: true,
 		})
 	}
 
+	// Show how long the current player has left
+	if timerField := createTurnTimerField(game, s, guildID); timerField != nil {
+		embed.Fields = append(embed.Fields, timerField)
+	}
+
 	// Add game result message if game is complete
 	if game.State == entities.StateComplete {
 		embed.Description = getGameResultsDescription(game, s, guildID)
@@ -151,6 +84,36 @@ func createGameEmbed(game *blackjack.Game, s SessionInterface, guildID string) *
 	return embed
 }
 
+// createTurnTimerField creates a countdown for the current turn, or returns nil
+// if the turn has no time limit. The clock starts the first time a turn is shown.
+func createTurnTimerField(game *blackjack.Game, s SessionInterface, guildID string) *discordgo.MessageEmbedField {
+	game.RefreshTurnDeadline(time.Now())
+	if game.TurnDeadline.IsZero() {
+		return nil
+	}
+
+	playerName := getPlayerDisplayName(s, guildID, game.HandOwner(game.CurrentTurnHandID()))
+	return &discordgo.MessageEmbedField{
+		Name:  "⏳ Turn Timer",
+		Value: fmt.Sprintf("%s has to act <t:%d:R>, or Tuco plays for them", playerName, game.TurnDeadline.Unix()),
+	}
+}
+
+// getPlayerDisplayName returns the nickname or username of a guild member, or
+// a computer player's name
+func getPlayerDisplayName(s SessionInterface, guildID, playerID string) string {
+	if blackjack.IsBotPlayer(playerID) {
+		return blackjack.BotName(playerID)
+	}
+	member, err := s.GuildMember(guildID, playerID)
+	if err == nil && member.Nick != "" {
+		return member.Nick
+	} else if err == nil && member.User != nil {
+		return member.User.Username
+	}
+	return "Unknown Player"
+}
+
 // getGameResultsDescription returns a summary of all players' results
 func getGameResultsDescription(game *blackjack.Game, s SessionInterface, guildID string) string {
 	dealerScore := blackjack.GetBestScore(game.Dealer.Cards)
@@ -162,20 +125,38 @@ func getGameResultsDescription(game *blackjack.Game, s SessionInterface, guildID
 		results = fmt.Sprintf("¡El Dealer tiene %d! Let's see who won...\n\n", dealerScore)
 	}
 
-	// Calculate payouts for each player
-	payouts := game.CalculatePayouts()
+	// Look up the payout for each hand, split hands are paid separately and
+	// insurance is listed with the side bets
+	handResults := make(map[string]blackjack.HandResult)
+	if results, err := game.GetResults(); err == nil {
+		for _, result := range results {
+			handResults[result.HandID] = result
+		}
+	}
+
+	for _, handID := range game.HandIDsInOrder() {
+		hand, exists := game.Players[handID]
+		if !exists {
+			continue
+		}
 
-	for playerID, hand := range game.Players {
 		playerScore := blackjack.GetBestScore(hand.Cards)
 		playerResult := ""
-		bet := game.Bets[playerID]
-		payout := payouts[playerID]
+		bet := game.Bets[handID]
+		payout := handResults[handID].Payout
 
 		// For blackjack, the payout already includes the correct amount (bet + winnings)
 		// For other results, we need to calculate the net result differently
 		var netResult int64
 
 		switch {
+		case hand.IsSurrendered() && handResults[handID].Result == entities.StringResultSurrender:
+			playerResult = " 🏳️ ¡RENDIDO!"
+			netResult = payout - bet // Half the bet comes back
+		case hand.IsSurrendered():
+			// Late surrender doesn't save you from Tuco's blackjack
+			playerResult = " 🏳️ ¡RENDIDO! ...but Tuco had blackjack"
+			netResult = -bet
 		case hand.Status == blackjack.StatusBust:
 			playerResult = " 💥 ¡BUST!"
 			netResult = -bet // Loss equal to bet amount
@@ -206,14 +187,8 @@ func getGameResultsDescription(game *blackjack.Game, s SessionInterface, guildID
 			netResult = 0 // No gain or loss on a push (original bet is returned)
 		}
 
-		// Get member info for display name
-		member, err := s.GuildMember(guildID, playerID)
-		playerName := "Unknown Player"
-		if err == nil && member.Nick != "" {
-			playerName = member.Nick
-		} else if err == nil && member.User != nil {
-			playerName = member.User.Username
-		}
+		// Get member info for display name, seats and split hands belong to their owner
+		playerName := handDisplayName(game, s, guildID, handID)
 
 		// Format the net result with color
 		var netResultStr string
@@ -246,12 +221,134 @@ func getGameResultsDescription(game *blackjack.Game, s SessionInterface, guildID
 		results += fmt.Sprintf("**%s**: %s (%d)%s\n", playerName, playerResult, playerScore, netResultStr)
 	}
 
+	results += getSideBetResults(game, s, guildID)
+	results += getBackBetResults(game, s, guildID)
+	results += getCollectionResults(game, s, guildID)
+
 	return results
 }
 
+// SideBetAmount is the size of the side bet each side bet button places
+const SideBetAmount int64 = 5
+
+// getSideBetLines describes how the side bets on a hand did, or returns an
+// empty string if there are none judged yet
+func getSideBetLines(game *blackjack.Game, handID string) string {
+	var lines string
+	for _, sideBet := range game.SideBets[handID] {
+		if !sideBet.Settled() {
+			continue
+		}
+		if sideBet.Payout > 0 {
+			lines += fmt.Sprintf("\n%s! Won $%d", game.DescribeSideBet(sideBet), sideBet.Payout-sideBet.Amount)
+		} else {
+			lines += fmt.Sprintf("\n%s, lost $%d", game.DescribeSideBet(sideBet), sideBet.Amount)
+		}
+	}
+	return lines
+}
+
+// getSideBetResults describes every side bet in the round for the results,
+// or returns an empty string if nobody made one
+func getSideBetResults(game *blackjack.Game, s SessionInterface, guildID string) string {
+	var results string
+	for _, handID := range game.HandIDsInOrder() {
+		for _, sideBet := range game.SideBets[handID] {
+			if !sideBet.Settled() {
+				continue
+			}
+			netResult := fmt.Sprintf("**-$%d**", sideBet.Amount)
+			if sideBet.Payout > 0 {
+				netResult = fmt.Sprintf("**+$%d**", sideBet.Payout-sideBet.Amount)
+			}
+			results += fmt.Sprintf("**%s**: %s %s\n", handDisplayName(game, s, guildID, handID), game.DescribeSideBet(sideBet), netResult)
+		}
+	}
+	if results == "" {
+		return ""
+	}
+	return "\n🎲 **Side bets**\n" + results
+}
+
+// createSideBetButtons creates a button for each side bet taken while betting
+// that the current bettor hasn't placed yet, or returns nil if there are none left
+func createSideBetButtons(game *blackjack.Game) []discordgo.MessageComponent {
+	playerID := game.CurrentTurnHandID()
+	buttons := []discordgo.MessageComponent{}
+	for _, sideBet := range game.SideBetRegistry().InWindow(blackjack.SideBetWindowBetting) {
+		if playerID != "" && game.GetSideBet(playerID, sideBet.Name()) != nil {
+			continue
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("Side bet: %s $%d", sideBet.Label(), SideBetAmount),
+			Style:    discordgo.SecondaryButton,
+			CustomID: "side_bet_" + sideBet.Name(),
+		})
+	}
+	if len(buttons) == 0 {
+		return nil
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// createBetButtons creates the buttons players bet with when betting opens, sized
+// to the table's limits. The rebet buttons show the amounts when the last bet is known.
+func createBetButtons(rules blackjack.RuleSet, lastBet int64) []discordgo.MessageComponent {
+	betButtons := []discordgo.MessageComponent{}
+	for _, amount := range betOptions(rules) {
+		betButtons = append(betButtons, discordgo.Button{
+			Label:    fmt.Sprintf("Bet $%d", amount),
+			Style:    discordgo.SuccessButton,
+			CustomID: fmt.Sprintf("bet_%d", amount),
+		})
+	}
+	betButtons = append(betButtons, discordgo.Button{
+		Label:    "Custom Bet",
+		Style:    discordgo.PrimaryButton,
+		CustomID: "custom_bet",
+	})
+
+	rebetLabel, doubleLabel := "Rebet", "Double Last Bet"
+	if lastBet > 0 {
+		rebetLabel = fmt.Sprintf("Rebet $%d", lastBet)
+		doubleLabel = fmt.Sprintf("Double Last Bet $%d", lastBet*2)
+	}
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: betButtons},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    rebetLabel,
+					Style:    discordgo.SecondaryButton,
+					CustomID: "rebet",
+				},
+				discordgo.Button{
+					Label:    doubleLabel,
+					Style:    discordgo.SecondaryButton,
+					CustomID: "double_last_bet",
+				},
+			},
+		},
+	}
+}
+
+// createHintButton creates the button that tells the player, and only them, what basic strategy says
+func createHintButton() discordgo.Button {
+	return discordgo.Button{
+		Label:    "🧠 Hint",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "hint",
+	}
+}
+
 // createGameButtons creates the action buttons if the game is in progress
 func createGameButtons(game *blackjack.Game) []discordgo.MessageComponent {
 	switch game.State {
+	case entities.StateBetting:
+		// Side bets go down next to the main bet
+		return append(createBetButtons(game.Rules, 0), createSideBetButtons(game)...)
+
 	case blackjack.StateSplitting:
 		// Get the current player whose turn it is to split
 		currentPlayerID := game.GetCurrentSplittingPlayerID()
@@ -284,10 +381,50 @@ func createGameButtons(game *blackjack.Game) []discordgo.MessageComponent {
 						Style:    discordgo.SecondaryButton,
 						CustomID: "decline_split",
 					},
+					createHintButton(),
 				},
 			},
 		}
 
+	case blackjack.StateInsurance:
+		// Before the peek the only options are insurance and early surrender
+		currentPlayerID, err := game.GetCurrentSpecialBetsPlayerID()
+		if err != nil || currentPlayerID == "" {
+			return []discordgo.MessageComponent{}
+		}
+
+		actionRow := discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{},
+		}
+
+		// Add Insurance button if the dealer shows an Ace
+		if game.IsEligibleForInsurance() {
+			actionRow.Components = append(actionRow.Components, discordgo.Button{
+				Label:    "Insurance",
+				Style:    discordgo.PrimaryButton,
+				CustomID: "insurance",
+			})
+		}
+
+		// Add Surrender button if the table allows early surrender
+		if game.IsEligibleForEarlySurrender(currentPlayerID) {
+			actionRow.Components = append(actionRow.Components, discordgo.Button{
+				Label:    "Surrender",
+				Style:    discordgo.DangerButton,
+				CustomID: "surrender",
+			})
+		}
+
+		// Always let the player pass so the dealer can peek
+		actionRow.Components = append(actionRow.Components, discordgo.Button{
+			Label:    "No Thanks",
+			Style:    discordgo.SecondaryButton,
+			CustomID: "decline_special",
+		})
+		actionRow.Components = append(actionRow.Components, createHintButton())
+
+		return []discordgo.MessageComponent{actionRow}
+
 	case blackjack.StateSpecialBets:
 		// Get the current player whose turn it is for special bets
 		currentPlayerID, err := game.GetCurrentSpecialBetsPlayerID()
@@ -330,12 +467,22 @@ func createGameButtons(game *blackjack.Game) []discordgo.MessageComponent {
 			})
 		}
 
+		// Add Surrender button if the table allows it
+		if game.IsEligibleForSurrender(currentPlayerID) {
+			actionRow.Components = append(actionRow.Components, discordgo.Button{
+				Label:    "Surrender",
+				Style:    discordgo.DangerButton,
+				CustomID: "surrender",
+			})
+		}
+
 		// Always add Skip button
 		actionRow.Components = append(actionRow.Components, discordgo.Button{
 			Label:    "Skip Special Bets",
 			Style:    discordgo.SecondaryButton,
 			CustomID: "decline_special",
 		})
+		actionRow.Components = append(actionRow.Components, createHintButton())
 
 		// Only add the action row if it has components
 		if len(actionRow.Components) > 0 {
@@ -382,6 +529,7 @@ func createGameButtons(game *blackjack.Game) []discordgo.MessageComponent {
 			Style:    discordgo.SecondaryButton,
 			CustomID: "stand",
 		})
+		actionsRow.Components = append(actionsRow.Components, createHintButton())
 
 		components = append(components, actionsRow)
 		return components
@@ -419,9 +567,21 @@ func createDealerField(game *blackjack.Game) *discordgo.MessageEmbedField {
 	if game.State == entities.StateComplete || game.State == entities.StateDealer {
 		// Show all cards at the end of the game or during dealer's turn
 		dealerValue = fmt.Sprintf("%s\nScore: %d%s", FormatCards(game.Dealer.Cards), dealerScore, dealerStatus)
+		if game.DealerPeeked && blackjack.IsBlackjack(game.Dealer.Cards) {
+			dealerValue += "\n*Tuco peeked: ¡Blackjack!*"
+		}
 	} else if game.State == entities.StateDealing {
 		// During dealing, show cards being dealt with animation
 		dealerValue = fmt.Sprintf("%s\n*Tuco deals the cards with a flourish*", FormatCards(game.Dealer.Cards))
+	} else if game.Rules.NoHoleCard {
+		// No hole card at this table, the dealer only has the up-card
+		dealerValue = fmt.Sprintf("%s\n*No hole card until the players are done*", FormatCard(game.Dealer.Cards[0]))
+	} else if game.State == blackjack.StateInsurance {
+		// The dealer peeks once everyone has decided on insurance
+		dealerValue = fmt.Sprintf("%s 🎴\nScore: ?\n*Tuco will peek for blackjack*", FormatCard(game.Dealer.Cards[0]))
+	} else if game.DealerPeeked {
+		// The dealer already checked the hole card and play went on, so no blackjack
+		dealerValue = fmt.Sprintf("%s 🎴\nScore: ?\n*Tuco peeked: no blackjack*", FormatCard(game.Dealer.Cards[0]))
 	} else {
 		// During play, only show first card and hide the rest
 		dealerValue = fmt.Sprintf("%s 🎴\nScore: ?", FormatCard(game.Dealer.Cards[0]))
@@ -460,10 +620,14 @@ func createLobbyEmbed(lobby *GameLobby) *discordgo.MessageEmbed {
 		if !joined {
 			continue
 		}
+		seats := ""
+		if lobby.seats(playerID) > 1 {
+			seats = fmt.Sprintf(" ×%d seats", lobby.seats(playerID))
+		}
 		if playerID == lobby.OwnerID {
-			playerList += fmt.Sprintf("<@%s> (El Jefe)\n", playerID)
+			playerList += fmt.Sprintf("<@%s> (El Jefe)%s\n", playerID, seats)
 		} else {
-			playerList += fmt.Sprintf("<@%s>\n", playerID)
+			playerList += fmt.Sprintf("%s%s\n", playerMention(playerID), seats)
 		}
 	}
 
@@ -477,11 +641,27 @@ func createLobbyEmbed(lobby *GameLobby) *discordgo.MessageEmbed {
 		Inline: false,
 	})
 
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "📜 House Rules",
+		Value:  lobby.Rules.Summary(),
+		Inline: false,
+	})
+
 	return embed
 }
 
-// createLobbyButtons creates the join and start buttons for the lobby
+// createLobbyButtons creates the join and start buttons for the lobby, and
+// the buttons the owner fills empty seats with computer players from
 func createLobbyButtons(ownerID string) []discordgo.MessageComponent {
+	botButtons := make([]discordgo.MessageComponent, 0, len(blackjack.BotPersonalities))
+	for _, personality := range blackjack.BotPersonalities {
+		botButtons = append(botButtons, discordgo.Button{
+			CustomID: "add_bot_" + string(personality),
+			Label:    "🤖 Add " + personality.Name(),
+			Style:    discordgo.SecondaryButton,
+		})
+	}
+
 	return []discordgo.MessageComponent{
 		discordgo.ActionsRow{
 			Components: []discordgo.MessageComponent{
@@ -490,6 +670,11 @@ func createLobbyButtons(ownerID string) []discordgo.MessageComponent {
 					Label:    "Join",
 					Style:    discordgo.PrimaryButton,
 				},
+				discordgo.Button{
+					CustomID: "add_seat",
+					Label:    "➕ Add Seat",
+					Style:    discordgo.SecondaryButton,
+				},
 				discordgo.Button{
 					CustomID: "start_game",
 					Label:    "Start",
@@ -497,6 +682,9 @@ func createLobbyButtons(ownerID string) []discordgo.MessageComponent {
 				},
 			},
 		},
+		discordgo.ActionsRow{
+			Components: botButtons,
+		},
 	}
 }
 
@@ -519,3 +707,19 @@ func getRandomDoubleDownMessage(playerName string) string {
 
 	return fmt.Sprintf(selectedMessage, playerName)
 }
+
+// getRandomSurrenderMessage returns a random Tuco-style message for a surrender
+func getRandomSurrenderMessage(playerName string) string {
+	messages := []string{
+		"*Tuco laughs* %s waves the white flag! Half your money stays with Tuco, cobarde!",
+		"¡Ay, qué lástima! %s surrenders! Tuco keeps half, you keep your dignity... maybe.",
+		"%s runs away like a rabbit from the coyote! Tuco gives you back half, because Tuco is generous, sí?",
+		"¡Rendición! %s gives up! Go on, take your half and hide behind the cactus, amigo.",
+	}
+
+	// Pick a random message
+	rand.Seed(time.Now().UnixNano())
+	selectedMessage := messages[rand.Intn(len(messages))]
+
+	return fmt.Sprintf(selectedMessage, playerName)
+}
@@ -0,0 +1,81 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// seats returns how many seats a player in the lobby will play
+func (l *GameLobby) seats(playerID string) int {
+	if seats := l.Seats[playerID]; seats > 1 {
+		return seats
+	}
+	return 1
+}
+
+// seatsTaken returns how many of the table's seats the lobby fills
+func (l *GameLobby) seatsTaken() int {
+	taken := 0
+	for playerID, joined := range l.Players {
+		if joined {
+			taken += l.seats(playerID)
+		}
+	}
+	return taken
+}
+
+// handleAddSeat gives a player in the lobby another seat, up to blackjack.MaxSeatsPerPlayer.
+// Each seat takes its own bet and is played as its own hand.
+func (b *Bot) handleAddSeat(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	playerID := i.Member.User.ID
+
+	b.mu.Lock()
+	lobby, exists := b.lobbies[i.ChannelID]
+	var content string
+	switch {
+	case !exists:
+		content = "¡Ay caramba! *frantically searches the casino* No lobby found in this channel! Start a new game with /blackjack"
+	case !lobby.Players[playerID]:
+		content = "*Tuco raises an eyebrow* Join the table first, amigo, then we talk about more seats."
+	case lobby.seats(playerID) >= blackjack.MaxSeatsPerPlayer:
+		content = fmt.Sprintf("¡Tranquilo! *holds up %d fingers* %d seats is the most Tuco lets one hombre play.", blackjack.MaxSeatsPerPlayer, blackjack.MaxSeatsPerPlayer)
+	case lobby.seatsTaken() >= lobby.Rules.MaxPlayers:
+		content = "*Tuco counts the chairs* The table is full, amigo. No room for anyone else."
+	default:
+		if lobby.Seats == nil {
+			lobby.Seats = make(map[string]int)
+		}
+		lobby.Seats[playerID] = lobby.seats(playerID) + 1
+	}
+	b.mu.Unlock()
+
+	if content != "" {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return
+	}
+
+	b.updateLobbyDisplay(s, i, lobby)
+}
+
+// handDisplayName returns the name a hand is shown under: its owner's, with
+// the seat it's played from if they play more than one, and whether it was split
+func handDisplayName(game *blackjack.Game, s SessionInterface, guildID, handID string) string {
+	playerID := game.HandOwner(handID)
+	playerName := getPlayerDisplayName(s, guildID, playerID)
+	if len(game.SeatsForPlayer(playerID)) > 1 {
+		playerName = fmt.Sprintf("%s (Seat %d)", playerName, game.SeatNumber(handID))
+	}
+	if !game.IsSeat(handID) {
+		playerName = fmt.Sprintf("%s (Split)", playerName)
+	}
+	return playerName
+}
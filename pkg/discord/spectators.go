@@ -0,0 +1,271 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// SpectatorThreadArchiveMinutes is how long a quiet spectator thread stays open
+const SpectatorThreadArchiveMinutes = 60
+
+// spectatorThread is the thread a channel's table is watched from. It's kept
+// across rounds, each round gets its own message in it.
+type spectatorThread struct {
+	ThreadID  string
+	MessageID string          // Message showing the round in the thread
+	RoundID   string          // Round the message shows
+	game      *blackjack.Game // Round being watched, kept until its results are shown
+}
+
+// tableActions are the buttons only players seated at the table can use
+var tableActions = map[string]bool{
+	"hit": true, "stand": true, "split": true, "decline_split": true, "double_down": true,
+	"insurance": true, "surrender": true, "decline_special": true, "hint": true,
+	"rebet": true, "double_last_bet": true, "custom_bet": true,
+}
+
+// isTableAction returns true for the buttons only players seated at the table can use
+func isTableAction(customID string) bool {
+	return tableActions[customID] || strings.HasPrefix(customID, "bet_") || strings.HasPrefix(customID, "side_bet_")
+}
+
+// tableChannel returns the channel whose table is played, the parent channel
+// for a spectator thread and the channel itself otherwise
+func (b *Bot) tableChannel(channelID string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for tableChannelID, thread := range b.spectatorThreads {
+		if thread.ThreadID == channelID {
+			return tableChannelID
+		}
+	}
+	return channelID
+}
+
+// redirectSpectator answers a table button clicked by someone who isn't
+// seated in the round, pointing them to the spectator thread. It returns
+// true if the click was answered. It runs before the interaction is acknowledged.
+func (b *Bot) redirectSpectator(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if !isTableAction(i.MessageComponentData().CustomID) {
+		return false
+	}
+
+	b.mu.RLock()
+	game, exists := b.games[i.ChannelID]
+	seated := exists && len(game.HandsForPlayer(i.Member.User.ID)) > 0
+	threadID := ""
+	if thread, hasThread := b.spectatorThreads[i.ChannelID]; hasThread {
+		threadID = thread.ThreadID
+	}
+	b.mu.RUnlock()
+	if !exists || seated {
+		return false
+	}
+
+	content := "*Tuco waves you off* You're not at this table, amigo. Wait for the next round and join the lobby."
+	if threadID != "" {
+		content = fmt.Sprintf("*Tuco waves you off* You're not at this table, amigo. Watch from <#%s>, and if you feel lucky, back one of the players there.", threadID)
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error redirecting spectator: %v", err)
+	}
+	return true
+}
+
+// handleBackBet places a spectator's back bet on a seat, clicked from the spectator thread
+func (b *Bot) handleBackBet(s *discordgo.Session, i *discordgo.InteractionCreate, handID string) error {
+	channelID := b.tableChannel(i.ChannelID)
+	playerID := i.Member.User.ID
+
+	b.mu.Lock()
+	game, exists := b.games[channelID]
+	var err error
+	var amount int64
+	if exists {
+		amount = game.Rules.MinBet
+		err = game.PlaceBackBet(context.Background(), playerID, handID, amount, b.tableWallet)
+	}
+	b.mu.Unlock()
+
+	var content string
+	switch {
+	case !exists:
+		content = "¡Ay caramba! *looks around confused* There's no round to bet on right now!"
+	case err != nil:
+		log.Printf("Error placing back bet for %s on seat %s: %v", playerID, handID, err)
+		content = fmt.Sprintf("¡No es posible! *shakes head* %v", err)
+	default:
+		content = fmt.Sprintf("*Tuco pockets your $%d* Riding with %s, eh? Let's hope they know what they're doing.",
+			amount, handDisplayName(game, s, i.GuildID, handID))
+	}
+
+	_, followupErr := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if followupErr != nil {
+		log.Printf("Error sending followup message: %v", followupErr)
+	}
+	return err
+}
+
+// updateSpectators shows the channel's round in its spectator thread, starting
+// the thread for the channel's first round. A round that just ended is shown
+// one last time with its results.
+func (b *Bot) updateSpectators(s *discordgo.Session, channelID string) {
+	b.mu.Lock()
+	game, live := b.games[channelID]
+	thread := b.spectatorThreads[channelID]
+	switch {
+	case live && thread != nil:
+		thread.game = game
+	case !live && thread != nil && thread.game != nil:
+		// The round is over and off the table, show how it ended
+		game = thread.game
+		thread.game = nil
+	case !live:
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Unlock()
+
+	if thread == nil {
+		thread = b.startSpectatorThread(s, channelID, game)
+		if thread == nil {
+			return
+		}
+	}
+
+	guildID := ""
+	if channel, err := s.Channel(channelID); err == nil {
+		guildID = channel.GuildID
+	}
+	content := "*Tuco nods at the crowd* Watch all you like, and back a player while they're betting."
+	embeds := []*discordgo.MessageEmbed{createGameEmbed(game, s, guildID)}
+	components := createBackBetButtons(game, s, guildID)
+
+	b.mu.RLock()
+	threadID, messageID, roundID := thread.ThreadID, thread.MessageID, thread.RoundID
+	b.mu.RUnlock()
+
+	var err error
+	if messageID != "" && roundID == game.ID {
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         messageID,
+			Channel:    threadID,
+			Content:    &content,
+			Embeds:     &embeds,
+			Components: &components,
+		})
+	}
+	if messageID == "" || roundID != game.ID || err != nil {
+		// A new round, or the old message is gone
+		var message *discordgo.Message
+		message, err = s.ChannelMessageSendComplex(threadID, &discordgo.MessageSend{
+			Content:    content,
+			Embeds:     embeds,
+			Components: components,
+		})
+		if err == nil {
+			b.mu.Lock()
+			thread.MessageID = message.ID
+			thread.RoundID = game.ID
+			b.mu.Unlock()
+		}
+	}
+	if err != nil {
+		log.Printf("Error showing table in spectator thread %s: %v", threadID, err)
+		// The thread may have been deleted, start a new one next time
+		b.mu.Lock()
+		if b.spectatorThreads[channelID] == thread {
+			delete(b.spectatorThreads, channelID)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// startSpectatorThread opens the spectator thread for a channel's table, or
+// returns nil if the channel can't have one
+func (b *Bot) startSpectatorThread(s *discordgo.Session, channelID string, game *blackjack.Game) *spectatorThread {
+	channel, err := s.ThreadStart(channelID, "🎟️ Tuco's table - spectators", discordgo.ChannelTypeGuildPublicThread, SpectatorThreadArchiveMinutes)
+	if err != nil {
+		log.Printf("Error starting spectator thread for channel %s: %v", channelID, err)
+		return nil
+	}
+
+	thread := &spectatorThread{ThreadID: channel.ID, game: game}
+	b.mu.Lock()
+	b.spectatorThreads[channelID] = thread
+	b.mu.Unlock()
+	return thread
+}
+
+// createBackBetButtons creates a button to back each seat while the table is
+// betting, or returns nil once back bets are closed
+func createBackBetButtons(game *blackjack.Game, s SessionInterface, guildID string) []discordgo.MessageComponent {
+	if game.State != entities.StateBetting {
+		return nil
+	}
+
+	rows := []discordgo.MessageComponent{}
+	buttons := []discordgo.MessageComponent{}
+	for _, handID := range game.HandIDsInOrder() {
+		if !game.IsSeat(handID) {
+			continue
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("🎟️ Back %s $%d", handDisplayName(game, s, guildID, handID), game.Rules.MinBet),
+			Style:    discordgo.SecondaryButton,
+			CustomID: "back_bet_" + handID,
+		})
+		// Discord fits five buttons in a row
+		if len(buttons) == 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: buttons})
+			buttons = []discordgo.MessageComponent{}
+		}
+	}
+	if len(buttons) > 0 {
+		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows
+}
+
+// getBackBetResults describes every back bet in the round for the results,
+// or returns an empty string if nobody made one
+func getBackBetResults(game *blackjack.Game, s SessionInterface, guildID string) string {
+	var results string
+	for _, backBet := range game.BackBets {
+		if !backBet.Settled {
+			continue
+		}
+		netResult := "**±$0**"
+		switch {
+		case backBet.Payout > backBet.Amount:
+			netResult = fmt.Sprintf("**+$%d**", backBet.Payout-backBet.Amount)
+		case backBet.Payout < backBet.Amount:
+			netResult = fmt.Sprintf("**-$%d**", backBet.Amount-backBet.Payout)
+		}
+		results += fmt.Sprintf("**%s** behind %s: %s\n", getPlayerDisplayName(s, guildID, backBet.PlayerID),
+			handDisplayName(game, s, guildID, backBet.HandID), netResult)
+	}
+	if results == "" {
+		return ""
+	}
+	return "\n🎟️ **Back bets**\n" + results
+}
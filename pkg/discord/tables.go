@@ -0,0 +1,357 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// tableSnapshot encodes the channel's live lobby or game, along with its
+// provably fair shoes, or returns nil if the channel has neither. The caller
+// must hold b.mu.
+func (b *Bot) tableSnapshot(channelID string) (*game.TableSnapshot, error) {
+	snapshot, err := b.liveTableSnapshot(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A provably fair channel's shoes are kept whether or not anyone's playing
+	fair, err := b.fairSnapshot(channelID)
+	if err != nil || fair == nil {
+		return snapshot, err
+	}
+	if snapshot == nil {
+		snapshot = &game.TableSnapshot{
+			ChannelID: channelID,
+			Kind:      game.SnapshotKindFair,
+			Data:      []byte("{}"),
+			UpdatedAt: time.Now(),
+		}
+	}
+	snapshot.Fair = fair
+	return snapshot, nil
+}
+
+// fairSnapshot encodes the channel's provably fair shoes, or returns nil if
+// the channel hasn't had any
+func (b *Bot) fairSnapshot(channelID string) ([]byte, error) {
+	b.fairMu.Lock()
+	fair, exists := b.fairShufflers[channelID]
+	b.fairMu.Unlock()
+	if !exists {
+		return nil, nil
+	}
+	return json.Marshal(fair)
+}
+
+// liveTableSnapshot encodes the channel's live lobby or game, or returns nil
+// if the channel has no table
+func (b *Bot) liveTableSnapshot(channelID string) (*game.TableSnapshot, error) {
+	if g, exists := b.games[channelID]; exists && !g.PayoutsProcessed {
+		data, err := g.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		return &game.TableSnapshot{
+			ChannelID: channelID,
+			Kind:      game.SnapshotKindGame,
+			RoundID:   g.ID,
+			Data:      data,
+			UpdatedAt: time.Now(),
+		}, nil
+	}
+
+	if lobby, exists := b.lobbies[channelID]; exists {
+		data, err := json.Marshal(lobby)
+		if err != nil {
+			return nil, err
+		}
+		return &game.TableSnapshot{
+			ChannelID: channelID,
+			Kind:      game.SnapshotKindLobby,
+			Data:      data,
+			UpdatedAt: time.Now(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// saveTable saves the channel's live table, or removes the saved one once the
+// table is gone, so a restart can pick up where the channel left off
+func (b *Bot) saveTable(channelID string) {
+	if channelID == "" {
+		return
+	}
+
+	b.mu.RLock()
+	snapshot, err := b.tableSnapshot(channelID)
+	b.mu.RUnlock()
+	if err != nil {
+		log.Printf("Error encoding table for channel %s: %v", channelID, err)
+		return
+	}
+
+	ctx := context.Background()
+	if snapshot == nil {
+		err = b.repo.DeleteTableSnapshot(ctx, channelID)
+	} else {
+		err = b.repo.SaveTableSnapshot(ctx, snapshot)
+	}
+	if err != nil {
+		log.Printf("Error saving table for channel %s: %v", channelID, err)
+	}
+}
+
+// restoreTables brings back the lobbies and games that were live when the bot
+// stopped and posts their tables again. Rounds that can't be resumed are
+// refunded, and so are the stakes held for rounds that weren't saved at all.
+func (b *Bot) restoreTables(s *discordgo.Session) {
+	snapshots, err := b.repo.GetTableSnapshots(context.Background())
+	if err != nil {
+		log.Printf("Error loading saved tables: %v", err)
+		return
+	}
+	log.Printf("Restoring %d saved tables", len(snapshots))
+
+	for _, snapshot := range snapshots {
+		// A reconnect can fire the ready event again, tables still live are left alone
+		b.mu.RLock()
+		_, hasGame := b.games[snapshot.ChannelID]
+		_, hasLobby := b.lobbies[snapshot.ChannelID]
+		b.mu.RUnlock()
+		if hasGame || hasLobby {
+			continue
+		}
+
+		// The shoes go back first, a restored round is still dealt from its committed shoe
+		if len(snapshot.Fair) > 0 {
+			b.restoreFairShuffler(s, snapshot)
+		}
+
+		switch snapshot.Kind {
+		case game.SnapshotKindLobby:
+			b.restoreLobby(s, snapshot)
+		case game.SnapshotKindGame:
+			b.restoreGame(s, snapshot)
+		case game.SnapshotKindFair:
+			// Nobody was playing, the shoes are all there was to bring back
+		default:
+			log.Printf("Unknown table kind %q saved for channel %s", snapshot.Kind, snapshot.ChannelID)
+		}
+	}
+
+	b.releaseAbandonedHolds(s, snapshots)
+}
+
+// restoreFairShuffler brings back a provably fair channel's committed shoes,
+// so the shoe being dealt keeps its commitment and is revealed once it's
+// retired. Server seeds revealed before the bot stopped are announced now.
+func (b *Bot) restoreFairShuffler(s *discordgo.Session, snapshot *game.TableSnapshot) {
+	fair, err := blackjack.RestoreFairShuffler(snapshot.Fair)
+	if err != nil {
+		log.Printf("Error restoring provably fair shoes for channel %s: %v", snapshot.ChannelID, err)
+		return
+	}
+
+	b.fairMu.Lock()
+	_, exists := b.fairShufflers[snapshot.ChannelID]
+	restored := b.provablyFair && !exists
+	if restored {
+		b.fairShufflers[snapshot.ChannelID] = fair
+	}
+	b.fairMu.Unlock()
+	if !restored {
+		return
+	}
+
+	log.Printf("Restored provably fair shoes for channel %s", snapshot.ChannelID)
+	b.announceRevealedShoes(s, snapshot.ChannelID)
+}
+
+// restoreLobby reopens a saved lobby. There's no money in a lobby, so one that
+// can't be reopened is simply dropped.
+func (b *Bot) restoreLobby(s *discordgo.Session, snapshot *game.TableSnapshot) {
+	// Saves the lobby again if it's reopened and removes it otherwise
+	defer b.saveTable(snapshot.ChannelID)
+
+	var lobby GameLobby
+	if err := json.Unmarshal(snapshot.Data, &lobby); err != nil {
+		log.Printf("Error decoding lobby for channel %s: %v", snapshot.ChannelID, err)
+		return
+	}
+	if err := lobby.Rules.Validate(); err != nil {
+		log.Printf("Dropping lobby for channel %s with invalid rules: %v", snapshot.ChannelID, err)
+		return
+	}
+	if lobby.Players == nil {
+		lobby.Players = make(map[string]bool)
+	}
+
+	_, err := s.ChannelMessageSendComplex(snapshot.ChannelID, &discordgo.MessageSend{
+		Content:    "*Tuco dusts off the table* ¡Estoy de vuelta! The lobby is still open, amigos.",
+		Embeds:     []*discordgo.MessageEmbed{createLobbyEmbed(&lobby)},
+		Components: createLobbyButtons(lobby.OwnerID),
+	})
+	if err != nil {
+		log.Printf("Error posting restored lobby for channel %s: %v", snapshot.ChannelID, err)
+		return
+	}
+
+	b.mu.Lock()
+	b.lobbies[snapshot.ChannelID] = &lobby
+	b.mu.Unlock()
+	log.Printf("Restored lobby for channel %s", snapshot.ChannelID)
+}
+
+// restoreGame resumes a saved round and posts the table again. A round that
+// can't be resumed is refunded.
+func (b *Bot) restoreGame(s *discordgo.Session, snapshot *game.TableSnapshot) {
+	g, err := blackjack.RestoreGame(snapshot.Data, b.repo, b.shufflerForChannel(snapshot.ChannelID))
+	if err != nil {
+		log.Printf("Error restoring round %s for channel %s: %v", snapshot.RoundID, snapshot.ChannelID, err)
+		b.refundTable(s, snapshot)
+		return
+	}
+	// Pay tables aren't saved with the round
+	g.SetSideBetRegistry(b.sideBets)
+
+	channel, err := s.Channel(snapshot.ChannelID)
+	if err != nil {
+		log.Printf("Channel %s is gone, round %s can't be resumed: %v", snapshot.ChannelID, g.ID, err)
+		b.refundTable(s, snapshot)
+		return
+	}
+
+	// Finish what the dealer was doing when the bot stopped
+	ctx := context.Background()
+	if g.State == entities.StateDealer {
+		if _, err := g.CompleteGameIfDone(ctx, b.tableWallet); err != nil {
+			log.Printf("Error playing the dealer for restored round %s: %v", g.ID, err)
+		}
+	}
+	if g.State == entities.StateComplete && !g.PayoutsProcessed {
+		if err := g.FinishGame(ctx, b.tableWallet); err != nil {
+			log.Printf("Error paying out restored round %s: %v", g.ID, err)
+		}
+	}
+
+	content := "*Tuco dusts off the table* ¡Estoy de vuelta, amigos! Where were we..."
+	components := createGameButtons(g)
+	if g.State == entities.StateComplete {
+		content = "*Tuco dusts off the table* ¡Estoy de vuelta! I finished the round while you were waiting, amigos."
+	}
+
+	message, err := s.ChannelMessageSendComplex(snapshot.ChannelID, &discordgo.MessageSend{
+		Content:    content,
+		Embeds:     []*discordgo.MessageEmbed{createGameEmbed(g, s, channel.GuildID)},
+		Components: components,
+	})
+	if err != nil {
+		log.Printf("Error posting restored round %s for channel %s: %v", g.ID, snapshot.ChannelID, err)
+		if !g.PayoutsProcessed {
+			b.refundTable(s, snapshot)
+		} else {
+			b.saveTable(snapshot.ChannelID)
+		}
+		return
+	}
+
+	if g.State != entities.StateComplete {
+		b.mu.Lock()
+		b.games[snapshot.ChannelID] = g
+		b.tableMessages[snapshot.ChannelID] = message.ID
+		b.mu.Unlock()
+	}
+	b.saveTable(snapshot.ChannelID)
+	log.Printf("Restored round %s for channel %s in state %s", g.ID, snapshot.ChannelID, g.State)
+}
+
+// refundTable gives the players of a round that can't be resumed their stakes
+// back and lets the channel know
+func (b *Bot) refundTable(s *discordgo.Session, snapshot *game.TableSnapshot) {
+	refunded, err := blackjack.RefundRound(context.Background(), b.repo, b.tableWallet, snapshot.ChannelID, snapshot.RoundID)
+	if err != nil {
+		// Keep the saved table, the next start tries the players it missed again
+		log.Printf("Error refunding round %s for channel %s: %v", snapshot.RoundID, snapshot.ChannelID, err)
+		return
+	}
+	// The round's gone, only the channel's provably fair shoes stay saved
+	b.saveTable(snapshot.ChannelID)
+	b.announceRefunds(s, snapshot.ChannelID, refunded)
+}
+
+// announceRefunds lets a channel know its last round was lost and what each
+// player got back
+func (b *Bot) announceRefunds(s *discordgo.Session, channelID string, refunded map[string]int64) {
+	if len(refunded) == 0 {
+		return
+	}
+
+	playerIDs := make([]string, 0, len(refunded))
+	for playerID := range refunded {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	lines := make([]string, 0, len(playerIDs))
+	for _, playerID := range playerIDs {
+		lines = append(lines, fmt.Sprintf("%s: $%d", playerMention(playerID), refunded[playerID]))
+	}
+	content := fmt.Sprintf("*Tuco hangs his head* Lo siento, amigos, the last round was lost while I was away. Your bets are back in your wallets:\n%s",
+		strings.Join(lines, "\n"))
+
+	// The channel may be gone, the refunds stand either way
+	if _, err := s.ChannelMessageSend(channelID, content); err != nil {
+		log.Printf("Error announcing refunds in channel %s: %v", channelID, err)
+	}
+}
+
+// releaseAbandonedHolds gives back the stakes still held for rounds that are
+// neither being played nor saved to be picked up again. Those rounds are gone
+// for good, and nothing else would ever release them.
+func (b *Bot) releaseAbandonedHolds(s *discordgo.Session, snapshots []*game.TableSnapshot) {
+	ctx := context.Background()
+	holds, err := b.walletService.GetOpenHolds(ctx)
+	if err != nil {
+		log.Printf("Error loading held stakes: %v", err)
+		return
+	}
+
+	known := make(map[string]bool)
+	for _, snapshot := range snapshots {
+		known[snapshot.RoundID] = true
+	}
+	b.mu.RLock()
+	for _, g := range b.games {
+		known[g.ID] = true
+	}
+	b.mu.RUnlock()
+
+	tables := make(map[string]string) // Round ID -> table it was played at
+	for _, hold := range holds {
+		if !known[hold.RoundID] {
+			tables[hold.RoundID] = hold.TableID
+		}
+	}
+	for roundID, channelID := range tables {
+		released, err := b.walletService.ReleaseRoundHolds(ctx, roundID)
+		if err != nil {
+			log.Printf("Error releasing the stakes held for abandoned round %s: %v", roundID, err)
+			continue
+		}
+		log.Printf("Released the stakes held for abandoned round %s: %v", roundID, released)
+		if channelID != "" {
+			b.announceRefunds(s, channelID, released)
+		}
+	}
+}
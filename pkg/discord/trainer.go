@@ -0,0 +1,345 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// TrainerDealInterval is how long each round of the counting drill stays up before the next
+const TrainerDealInterval = 2 * time.Second
+
+// trainCommand starts a card counting drill that only the player sees
+var trainCommand = &discordgo.ApplicationCommand{
+	Name:        "train",
+	Description: "Practice counting cards with Tuco, no money on the table",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "system",
+			Description: "The count system to practice",
+			Required:    false,
+			Choices:     countSystemChoices(),
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "decks",
+			Description: "Number of decks in the shoe, the channel's table by default",
+			Required:    false,
+			MinValue:    &[]float64{1}[0],
+			MaxValue:    8,
+		},
+	},
+}
+
+// countSystemChoices offers every count system in the train command
+func countSystemChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(blackjack.CountSystems))
+	for _, system := range blackjack.CountSystems {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: system.Title, Value: system.Name})
+	}
+	return choices
+}
+
+// handleTrainCommand starts a counting drill on a fresh shoe of the channel's
+// rules. It replaces any drill the player already had going.
+func (b *Bot) handleTrainCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	system := blackjack.HiLo
+	rules := b.rulesForChannel(i.ChannelID)
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "system":
+			chosen, err := blackjack.CountSystemByName(option.StringValue())
+			if err != nil {
+				log.Printf("Error starting counting drill: %v", err)
+				continue
+			}
+			system = chosen
+		case "decks":
+			rules.Decks = int(option.IntValue())
+		}
+	}
+
+	trainer := blackjack.NewCountTrainer(i.Member.User.ID, system, rules, b.repo, b.shuffler)
+	b.trainersMu.Lock()
+	b.trainers[i.Member.User.ID] = trainer
+	b.trainersMu.Unlock()
+
+	content := fmt.Sprintf("🧮 **Counting drill · %s** · %d decks\n*Tuco cracks his knuckles* I deal, you count. Every now and then I ask for the count, so keep up, amigo. No money on the table, this is just for the brain.\n\n%s",
+		system.Title, rules.Decks, countTagsLine(system))
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: createTrainerButtons(trainer, false),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error starting counting drill: %v", err)
+	}
+}
+
+// countTagsLine reminds the player what each card counts in the system
+func countTagsLine(system blackjack.CountSystem) string {
+	labels := []string{"", "", "2", "3", "4", "5", "6", "7", "8", "9", "10", "A"}
+	tags := make([]string, 0, len(labels)-2)
+	for value := 2; value <= 11; value++ {
+		tags = append(tags, fmt.Sprintf("%s: %+d", labels[value], system.Tags[value]))
+	}
+	line := "**Tags** · " + strings.Join(tags, " · ")
+	if !system.Balanced() {
+		line += fmt.Sprintf("\nThe shoe starts at a running count of %+d.", system.InitialCount(1))
+	}
+	return line
+}
+
+// trainerFor returns the player's counting drill, if they have one going
+func (b *Bot) trainerFor(playerID string) (*blackjack.CountTrainer, bool) {
+	b.trainersMu.Lock()
+	defer b.trainersMu.Unlock()
+	trainer, exists := b.trainers[playerID]
+	return trainer, exists
+}
+
+// handleTrainerDeal deals rounds of the drill one after another until Tuco asks for the count
+func (b *Bot) handleTrainerDeal(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	trainer, exists := b.trainerFor(i.Member.User.ID)
+	if !exists {
+		b.endTrainerMessage(s, i, "*Tuco shrugs* This drill is over, amigo. Start a new one with /train")
+		return
+	}
+
+	for {
+		b.trainersMu.Lock()
+		if b.trainers[i.Member.User.ID] != trainer {
+			// The player stopped or started a new drill
+			b.trainersMu.Unlock()
+			return
+		}
+		reshuffled, err := trainer.Deal()
+		content := trainerRoundMessage(trainer, reshuffled)
+		asking := trainer.Question != ""
+		components := createTrainerButtons(trainer, true)
+		b.trainersMu.Unlock()
+		if err != nil && !errors.Is(err, blackjack.ErrQuestionPending) {
+			log.Printf("Error dealing counting drill for player %s: %v", i.Member.User.ID, err)
+			return
+		}
+
+		// Only stopping is offered while the cards are flying
+		_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &content,
+			Components: &components,
+		})
+		if err != nil {
+			log.Printf("Error showing counting drill: %v", err)
+			return
+		}
+		if asking {
+			return
+		}
+		time.Sleep(TrainerDealInterval)
+	}
+}
+
+// trainerRoundMessage shows the last round dealt, and the question if Tuco is asking one
+func trainerRoundMessage(trainer *blackjack.CountTrainer, reshuffled bool) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🧮 **Counting drill · %s** · Round %d · %.1f decks left\n", trainer.System.Title, trainer.Rounds, trainer.DecksLeft())
+	if reshuffled {
+		fmt.Fprintf(&sb, "*Tuco brings out a fresh shoe, the count starts over at %+d*\n", trainer.System.InitialCount(trainer.Rules.Decks))
+	}
+	sb.WriteString("\n")
+	for n, hand := range trainer.Round {
+		if n == len(trainer.Round)-1 {
+			fmt.Fprintf(&sb, "**Dealer:** %s\n", FormatCards(hand))
+			continue
+		}
+		fmt.Fprintf(&sb, "**Hand %d:** %s\n", n+1, FormatCards(hand))
+	}
+
+	switch trainer.Question {
+	case blackjack.QuestionRunningCount:
+		sb.WriteString("\n🤔 *Tuco slaps the table* ¡Alto! What's the **running count**, amigo?")
+	case blackjack.QuestionTrueCount:
+		fmt.Fprintf(&sb, "\n🤔 *Tuco slaps the table* ¡Alto! What's the **true count**, amigo? Within %.1f is close enough.", blackjack.TrueCountTolerance)
+	}
+	return sb.String()
+}
+
+// handleTrainerAnswerButton opens the modal where the player gives the count.
+func (b *Bot) handleTrainerAnswerButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	label := "The running count"
+	b.trainersMu.Lock()
+	if trainer, exists := b.trainers[i.Member.User.ID]; exists && trainer.Question == blackjack.QuestionTrueCount {
+		label = "The true count"
+	}
+	b.trainersMu.Unlock()
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: "train_answer_modal",
+			Title:    "¿Cuánto?",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "count",
+							Label:       label,
+							Style:       discordgo.TextInputShort,
+							Placeholder: "e.g. +4 or -1.5",
+							Required:    true,
+							MinLength:   1,
+							MaxLength:   8,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error opening count modal: %v", err)
+	}
+}
+
+// handleTrainerAnswerSubmit scores the count the player gave
+func (b *Bot) handleTrainerAnswerSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		log.Printf("Error acknowledging count answer: %v", err)
+		return
+	}
+
+	value := strings.TrimPrefix(strings.TrimSpace(modalTextValue(i.ModalSubmitData(), "count")), "+")
+	given, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: fmt.Sprintf("*Tuco squints* ¿Qué? `%s` isn't a count, amigo. Try again.", value),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		if err != nil {
+			log.Printf("Error sending followup message: %v", err)
+		}
+		return
+	}
+
+	trainer, exists := b.trainerFor(i.Member.User.ID)
+	if !exists {
+		b.endTrainerMessage(s, i, "*Tuco shrugs* This drill is over, amigo. Start a new one with /train")
+		return
+	}
+
+	b.trainersMu.Lock()
+	answer, err := trainer.Answer(context.Background(), given)
+	content := trainerRoundMessage(trainer, false)
+	components := createTrainerButtons(trainer, false)
+	b.trainersMu.Unlock()
+	if err != nil {
+		log.Printf("Error answering counting drill for player %s: %v", i.Member.User.ID, err)
+		return
+	}
+
+	content += "\n\n" + answerMessage(answer) + "\n" + b.trainerScoreLine(trainer)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error showing count answer: %v", err)
+	}
+}
+
+// answerMessage is what Tuco says about an answer
+func answerMessage(answer *blackjack.CountAnswer) string {
+	count := fmt.Sprintf("running count is **%+.0f**", answer.Count)
+	if answer.Question == blackjack.QuestionTrueCount {
+		count = fmt.Sprintf("true count is **%+.1f**", answer.Count)
+	}
+
+	if answer.Correct {
+		return fmt.Sprintf("✅ ¡Exacto! The %s. Tuco is impressed.", count)
+	}
+	return fmt.Sprintf("❌ ¡No, no, no! You said %+g, the %s.", answer.Answer, count)
+}
+
+// trainerScoreLine sums up how the player's counting has gone, this drill and over time
+func (b *Bot) trainerScoreLine(trainer *blackjack.CountTrainer) string {
+	line := fmt.Sprintf("**This drill:** %d/%d right", trainer.Correct, trainer.Asked)
+
+	records, err := b.repo.GetCountDrills(context.Background(), trainer.PlayerID)
+	if err != nil {
+		log.Printf("Error loading count drills for player %s: %v", trainer.PlayerID, err)
+		return line
+	}
+	all, recent := blackjack.CountDrillAccuracy(records, trainer.System.Name)
+	if all.Answered > 0 {
+		line += fmt.Sprintf(" · **%s all time:** %.0f%% of %d · **last %d:** %.0f%%",
+			trainer.System.Title, all.Rate()*100, all.Answered, recent.Answered, recent.Rate()*100)
+	}
+	return line
+}
+
+// handleTrainerStop ends the player's counting drill
+func (b *Bot) handleTrainerStop(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b.trainersMu.Lock()
+	trainer, exists := b.trainers[i.Member.User.ID]
+	delete(b.trainers, i.Member.User.ID)
+	b.trainersMu.Unlock()
+
+	content := "*Tuco sweeps the cards away* Drill over, amigo."
+	if exists {
+		content += "\n" + b.trainerScoreLine(trainer)
+	}
+	b.endTrainerMessage(s, i, content)
+}
+
+// endTrainerMessage replaces the drill with a last word and no buttons
+func (b *Bot) endTrainerMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &[]discordgo.MessageComponent{},
+	})
+	if err != nil {
+		log.Printf("Error ending counting drill: %v", err)
+	}
+}
+
+// createTrainerButtons creates the drill's buttons: answering when Tuco asked
+// for the count, dealing between questions, and stopping whenever
+func createTrainerButtons(trainer *blackjack.CountTrainer, dealing bool) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	switch {
+	case trainer.Question != "":
+		buttons = append(buttons, discordgo.Button{
+			Label:    "🧮 Answer",
+			Style:    discordgo.SuccessButton,
+			CustomID: "train_answer",
+		})
+	case !dealing:
+		buttons = append(buttons, discordgo.Button{
+			Label:    "🃏 Deal",
+			Style:    discordgo.PrimaryButton,
+			CustomID: "train_deal",
+		})
+	}
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Stop",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "train_stop",
+	})
+
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: buttons},
+	}
+}
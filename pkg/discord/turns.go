@@ -0,0 +1,229 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+const (
+	TurnTimerInterval   = 2 * time.Second  // How often the bot looks for turns that ran out
+	DefaultAFKKickAfter = 3                // Turns a player can time out in a row before they're kicked
+	AFKKickDuration     = 15 * time.Minute // How long a kicked player has to wait to join again
+)
+
+// SetTurnTimers sets how long players get for each decision in new games.
+// Call it before the bot starts.
+func (b *Bot) SetTurnTimers(timers blackjack.TurnTimers) {
+	b.turnTimers = timers
+}
+
+// SetAFKKickAfter sets how many turns in a row a player can time out before
+// they're kicked from the channel's lobby, 0 never kicks. Call it before the bot starts.
+func (b *Bot) SetAFKKickAfter(timeouts int) {
+	b.afkKickAfter = timeouts
+}
+
+// setTableMessage remembers the message a channel's table is shown in
+func (b *Bot) setTableMessage(channelID, messageID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tableMessages[channelID] = messageID
+}
+
+// runTurnTimers plays the default for every player who runs out of time, and
+// the turns of computer players, until the bot stops
+func (b *Bot) runTurnTimers() {
+	ticker := time.NewTicker(TurnTimerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopChan:
+			return
+		case now := <-ticker.C:
+			b.checkTurnTimers(now)
+			b.checkBotTurns(now)
+		}
+	}
+}
+
+// checkTurnTimers times out every turn that ran out of time
+func (b *Bot) checkTurnTimers(now time.Time) {
+	var expired []string
+	b.mu.RLock()
+	for channelID, game := range b.games {
+		if game.TurnExpired(now) {
+			expired = append(expired, channelID)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, channelID := range expired {
+		b.timeOutTurn(b.session, channelID)
+	}
+}
+
+// timeOutTurn plays the default for the channel's current player and shows
+// the table again
+func (b *Bot) timeOutTurn(s *discordgo.Session, channelID string) {
+	b.mu.Lock()
+	game, exists := b.games[channelID]
+	if !exists || !game.TurnExpired(time.Now()) {
+		// The player acted just in time
+		b.mu.Unlock()
+		return
+	}
+	state := game.State
+	playerID, err := game.TimeOutTurn(context.Background(), b.tableWallet)
+	b.mu.Unlock()
+	if err != nil {
+		log.Printf("Error timing out turn in channel %s: %v", channelID, err)
+		return
+	}
+
+	content := fmt.Sprintf("*Tuco drums his fingers on the table* <@%s> took too long, %s", playerID, timeoutDefault(state))
+	b.refreshTable(s, channelID, game, content)
+	b.addStrike(s, channelID, playerID)
+	b.saveTable(channelID)
+	b.updateSpectators(s, channelID)
+}
+
+// timeoutDefault describes what Tuco did for a player who ran out of time
+func timeoutDefault(state entities.GameState) string {
+	switch state {
+	case entities.StateBetting:
+		return "so they're sitting this round out."
+	case blackjack.StateSplitting:
+		return "so the pair stays together."
+	case blackjack.StateInsurance, blackjack.StateSpecialBets:
+		return "so no special bets for them."
+	default:
+		return "so they stand."
+	}
+}
+
+// refreshTable shows the table in the channel's table message after a change
+// that didn't come from an interaction, finishing the round if it's over
+func (b *Bot) refreshTable(s *discordgo.Session, channelID string, game *blackjack.Game, content string) {
+	ctx := context.Background()
+	if game.State == entities.StateDealer && !game.PayoutsProcessed {
+		if _, err := game.CompleteGameIfDone(ctx, b.tableWallet); err != nil {
+			log.Printf("Error during dealer play: %v", err)
+		}
+	}
+	if game.State == entities.StateComplete && !game.PayoutsProcessed {
+		if err := game.FinishGame(ctx, b.tableWallet); err != nil {
+			log.Printf("Error finishing game: %v", err)
+		}
+	}
+
+	guildID := ""
+	if channel, err := s.Channel(channelID); err == nil {
+		guildID = channel.GuildID
+	}
+	embeds := []*discordgo.MessageEmbed{createGameEmbed(game, s, guildID)}
+	components := createGameButtons(game)
+
+	b.mu.RLock()
+	messageID := b.tableMessages[channelID]
+	b.mu.RUnlock()
+
+	var err error
+	if messageID != "" {
+		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         messageID,
+			Channel:    channelID,
+			Content:    &content,
+			Embeds:     &embeds,
+			Components: &components,
+		})
+	}
+	if messageID == "" || err != nil {
+		// The table message is gone, show the table in a new one
+		var message *discordgo.Message
+		message, err = s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:    content,
+			Embeds:     embeds,
+			Components: components,
+		})
+		if err == nil {
+			b.setTableMessage(channelID, message.ID)
+		}
+	}
+	if err != nil {
+		log.Printf("Error showing table for channel %s: %v", channelID, err)
+	}
+
+	if game.State == entities.StateComplete {
+		b.mu.Lock()
+		delete(b.games, channelID)
+		b.mu.Unlock()
+	}
+}
+
+// afkKey identifies a player at a channel's table
+func afkKey(channelID, playerID string) string {
+	return channelID + "/" + playerID
+}
+
+// addStrike counts a timed out turn against a player, and kicks them from the
+// channel's lobby once they've timed out too many turns in a row
+func (b *Bot) addStrike(s *discordgo.Session, channelID, playerID string) {
+	if b.afkKickAfter <= 0 {
+		return
+	}
+
+	key := afkKey(channelID, playerID)
+	b.afkMu.Lock()
+	b.afkStrikes[key]++
+	kicked := b.afkStrikes[key] >= b.afkKickAfter
+	if kicked {
+		delete(b.afkStrikes, key)
+		b.kickedUntil[key] = time.Now().Add(AFKKickDuration)
+	}
+	b.afkMu.Unlock()
+	if !kicked {
+		return
+	}
+
+	b.mu.Lock()
+	if lobby, exists := b.lobbies[channelID]; exists {
+		delete(lobby.Players, playerID)
+		delete(lobby.Seats, playerID)
+	}
+	b.mu.Unlock()
+
+	log.Printf("Kicked player %s from channel %s after %d timeouts in a row", playerID, channelID, b.afkKickAfter)
+	content := fmt.Sprintf("*Tuco snaps his fingers* <@%s> fell asleep at the table %d times in a row. ¡Fuera! Come back in %d minutes, amigo.",
+		playerID, b.afkKickAfter, int(AFKKickDuration.Minutes()))
+	if _, err := s.ChannelMessageSend(channelID, content); err != nil {
+		log.Printf("Error announcing kick in channel %s: %v", channelID, err)
+	}
+}
+
+// clearStrikes forgets a player's timed out turns once they act again
+func (b *Bot) clearStrikes(channelID, playerID string) {
+	b.afkMu.Lock()
+	defer b.afkMu.Unlock()
+	delete(b.afkStrikes, afkKey(channelID, playerID))
+}
+
+// kickedUntilTime returns when a kicked player may join the channel's lobby again
+func (b *Bot) kickedUntilTime(channelID, playerID string) (time.Time, bool) {
+	b.afkMu.Lock()
+	defer b.afkMu.Unlock()
+
+	key := afkKey(channelID, playerID)
+	until, kicked := b.kickedUntil[key]
+	if kicked && time.Now().After(until) {
+		delete(b.kickedUntil, key)
+		return time.Time{}, false
+	}
+	return until, kicked
+}
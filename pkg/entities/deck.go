@@ -1,12 +1,10 @@
 package entities
 
-import (
-	"math/rand"
-	"time"
-)
-
 type Deck struct {
-	Cards []*Card
+	Cards   []*Card
+	Seed    Seed // Seed of the last shuffle, used to deal the shoe again
+	Dealt   int  // Number of cards drawn since the last shuffle
+	CutCard int  // Cards dealt before the cut card comes out, 0 if there is none
 }
 
 // NewDeck creates a new deck of 52 cards, one of each rank and suit
@@ -24,14 +22,16 @@ func NewDeck() *Deck {
 	return &Deck{Cards: cards}
 }
 
+// Shuffle shuffles the deck with a cryptographically secure seed
 func (d *Deck) Shuffle() {
-	// Create a new random source using current time as seed
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	d.ShuffleWith(NewCryptoShuffler())
+}
 
-	// Use Go's built-in shuffle algorithm
-	r.Shuffle(len(d.Cards), func(i, j int) {
-		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
-	})
+// ShuffleWith shuffles the deck with the given shuffler and records the seed
+func (d *Deck) ShuffleWith(shuffler Shuffler) {
+	d.Seed = shuffler.Shuffle(d.Cards)
+	d.Dealt = 0
+	d.CutCard = 0
 }
 
 // Draw removes and returns the top card from the deck
@@ -41,5 +41,6 @@ func (d *Deck) Draw() *Card {
 	}
 	card := d.Cards[0]
 	d.Cards = d.Cards[1:]
+	d.Dealt++
 	return card
 }
@@ -30,6 +30,7 @@ const (
 	StringResultLose      StringResult = "LOSE"
 	StringResultPush      StringResult = "PUSH"
 	StringResultBlackjack StringResult = "BLACKJACK"
+	StringResultSurrender StringResult = "SURRENDER"
 )
 
 // Game state types
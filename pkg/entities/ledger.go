@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"strings"
+)
+
+// LedgerAccount names an account in the chip ledger. Every chip in the
+// economy sits in exactly one account, and chips only move between accounts
+// in balanced transfers.
+type LedgerAccount string
+
+const (
+	LedgerAccountMint      LedgerAccount = "system:mint"     // Where chips are issued from and burned back to
+	LedgerAccountHouseBank LedgerAccount = "house:bank"      // The house's winnings, and what it pays out of
+	LedgerAccountLoanDesk  LedgerAccount = "house:loan_desk" // Lends chips out, its balance is minus the loans outstanding
+)
+
+const (
+	userAccountPrefix   = "user:"
+	tableAccountPrefix  = "table:"
+	escrowAccountSuffix = ":escrow"
+	roundAccountPrefix  = "round:"
+	holdsAccountSuffix  = ":holds"
+)
+
+// UserAccount returns the ledger account holding a user's wallet balance
+func UserAccount(userID string) LedgerAccount {
+	return LedgerAccount(userAccountPrefix + userID)
+}
+
+// TableEscrowAccount returns the ledger account holding the stakes on a table
+// until its round is settled
+func TableEscrowAccount(tableID string) LedgerAccount {
+	return LedgerAccount(tableAccountPrefix + tableID + escrowAccountSuffix)
+}
+
+// HoldAccount returns the ledger account holding the chips held for a round
+// until they're captured or given back
+func HoldAccount(roundID string) LedgerAccount {
+	return LedgerAccount(roundAccountPrefix + roundID + holdsAccountSuffix)
+}
+
+// UserID returns the user whose wallet the account holds, or false if it isn't a user's account
+func (a LedgerAccount) UserID() (string, bool) {
+	if !strings.HasPrefix(string(a), userAccountPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(a), userAccountPrefix), true
+}
+
+// HoldRoundID returns the round whose held chips the account holds, or false if it isn't a hold account
+func (a LedgerAccount) HoldRoundID() (string, bool) {
+	if !strings.HasPrefix(string(a), roundAccountPrefix) || !strings.HasSuffix(string(a), holdsAccountSuffix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(string(a), roundAccountPrefix), holdsAccountSuffix), true
+}
+
+// LedgerEntry is one side of a transfer between ledger accounts
+type LedgerEntry struct {
+	Account LedgerAccount // Account the entry posts to
+	Amount  int64         // Positive credits the account, negative debits it
+}
+
+// Transfer returns the entries that move amount from one account to another
+func Transfer(from, to LedgerAccount, amount int64) []LedgerEntry {
+	return []LedgerEntry{
+		{Account: from, Amount: -amount},
+		{Account: to, Amount: amount},
+	}
+}
+
+// EntriesBalance returns true if the entries add up to zero, so no chips are
+// created or lost by posting them
+func EntriesBalance(entries []LedgerEntry) bool {
+	var total int64
+	for _, entry := range entries {
+		total += entry.Amount
+	}
+	return total == 0
+}
+
+// LedgerTotals sums up the whole ledger
+type LedgerTotals struct {
+	Balances map[LedgerAccount]int64 // Balance of every account with entries
+	Issued   int64                   // Chips the mint has put into the economy
+	Burned   int64                   // Chips returned to the mint and taken out of the economy
+}
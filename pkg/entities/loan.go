@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+)
+
+// LoanStatus is where a loan stands
+type LoanStatus string
+
+const (
+	LoanStatusOpen   LoanStatus = "OPEN"
+	LoanStatusRepaid LoanStatus = "REPAID"
+)
+
+// Loan is one loan from Tuco. A wallet's LoanAmount is what's still owed on
+// its open loans, interest included. Loans are kept once they're repaid, so
+// every loan a player ever took can be looked back on.
+type Loan struct {
+	ID            string     // Unique identifier
+	UserID        string     // Player the chips were lent to
+	Principal     int64      // Chips lent
+	Interest      int64      // Interest charged so far
+	Repaid        int64      // Repaid so far, collections included
+	Status        LoanStatus // Open until it's repaid in full
+	IssuedAt      time.Time  // When the chips were lent
+	DueAt         time.Time  // When it has to be repaid by
+	AccruedAt     time.Time  // When interest was last charged, or when it was issued
+	RemindersSent int        // Reminders sent so far, each sterner than the last
+	RepaidAt      time.Time  // When it was repaid in full, zero while it's open
+}
+
+// Outstanding returns what's still owed on the loan
+func (l *Loan) Outstanding() int64 {
+	return l.Principal + l.Interest - l.Repaid
+}
+
+// Overdue returns true if the loan is still open after it was due
+func (l *Loan) Overdue(now time.Time) bool {
+	return l.Status == LoanStatusOpen && now.After(l.DueAt)
+}
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+)
+
+var ErrInvalidSeed = errors.New("invalid shuffle seed")
+
+// Seed identifies a shuffle. Shuffling the same cards with the same seed always
+// gives the same order, which is what lets a shoe be dealt again.
+type Seed [32]byte
+
+// ParseSeed parses a seed from its hex representation
+func ParseSeed(s string) (Seed, error) {
+	var seed Seed
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(seed) {
+		return seed, fmt.Errorf("%w: %q", ErrInvalidSeed, s)
+	}
+	copy(seed[:], b)
+	return seed, nil
+}
+
+// String returns the seed as hex
+func (s Seed) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsZero returns true if the seed was never set
+func (s Seed) IsZero() bool {
+	return s == Seed{}
+}
+
+// MarshalText encodes the seed as hex, so it reads well in JSON
+func (s Seed) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a hex seed
+func (s *Seed) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = Seed{}
+		return nil
+	}
+	seed, err := ParseSeed(string(text))
+	if err != nil {
+		return err
+	}
+	*s = seed
+	return nil
+}
+
+// Shuffler shuffles cards and reports the seed that reproduces the shuffle
+type Shuffler interface {
+	Shuffle(cards []*Card) Seed
+}
+
+// ShuffleWithSeed shuffles cards in place in the order determined by the seed
+func ShuffleWithSeed(cards []*Card, seed Seed) {
+	r := rand.New(rand.NewChaCha8(seed))
+	r.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+}
+
+// CryptoShuffler draws a fresh seed from crypto/rand for every shuffle
+type CryptoShuffler struct{}
+
+// NewCryptoShuffler creates the default shuffler used at the tables
+func NewCryptoShuffler() *CryptoShuffler {
+	return &CryptoShuffler{}
+}
+
+// Shuffle shuffles the cards with a new cryptographically secure seed
+func (s *CryptoShuffler) Shuffle(cards []*Card) Seed {
+	var seed Seed
+	if _, err := crand.Read(seed[:]); err != nil {
+		// The OS random source failing is not something a card game can recover from
+		panic(fmt.Sprintf("entities: crypto/rand unavailable: %v", err))
+	}
+	ShuffleWithSeed(cards, seed)
+	return seed
+}
+
+// SeededShuffler derives the seed of every shuffle from a starting seed, so a
+// whole run of shuffles can be repeated. Meant for tests and replays.
+type SeededShuffler struct {
+	mu  sync.Mutex
+	rng *rand.ChaCha8
+}
+
+// NewSeededShuffler creates a shuffler whose shuffles all follow from the seed
+func NewSeededShuffler(seed Seed) *SeededShuffler {
+	return &SeededShuffler{rng: rand.NewChaCha8(seed)}
+}
+
+// Shuffle shuffles the cards with the next seed in the sequence
+func (s *SeededShuffler) Shuffle(cards []*Card) Seed {
+	s.mu.Lock()
+	var seed Seed
+	for i := 0; i < len(seed); i += 8 {
+		binary.LittleEndian.PutUint64(seed[i:], s.rng.Uint64())
+	}
+	s.mu.Unlock()
+
+	ShuffleWithSeed(cards, seed)
+	return seed
+}
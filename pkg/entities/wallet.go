@@ -16,20 +16,47 @@ type Wallet struct {
 type TransactionType string
 
 const (
-	TransactionTypeBet       TransactionType = "BET"
-	TransactionTypePayout    TransactionType = "PAYOUT"
-	TransactionTypeLoan      TransactionType = "LOAN"
-	TransactionTypeRepayment TransactionType = "REPAYMENT"
+	TransactionTypeBet        TransactionType = "BET"         // Stake on a hand, doubles and splits included
+	TransactionTypePayout     TransactionType = "PAYOUT"      // Winnings, stake included
+	TransactionTypePushRefund TransactionType = "PUSH_REFUND" // Stake given back from a push, a surrender or an abandoned round
+	TransactionTypeInsurance  TransactionType = "INSURANCE"   // Insurance stake
+	TransactionTypeSideBet    TransactionType = "SIDE_BET"    // Side bet stake
+	TransactionTypeLoan       TransactionType = "LOAN"
+	TransactionTypeRepayment  TransactionType = "REPAYMENT" // Loan repaid, collections included
+	TransactionTypeInterest   TransactionType = "INTEREST"  // Interest added to a loan, the balance doesn't change
+	TransactionTypeGrant      TransactionType = "GRANT"     // Chips a new wallet starts with
 )
 
+// IsGame reports whether the type is chips moving between a player and a
+// game, rather than a loan, a repayment or a grant
+func (t TransactionType) IsGame() bool {
+	switch t {
+	case TransactionTypeBet, TransactionTypePayout, TransactionTypePushRefund, TransactionTypeInsurance, TransactionTypeSideBet:
+		return true
+	}
+	return false
+}
+
 // Transaction represents a single wallet transaction
 type Transaction struct {
 	ID           string          // Unique identifier
 	UserID       string          // User associated with the transaction
 	Amount       int64           // Amount (positive for additions, negative for subtractions)
 	Type         TransactionType // Type of transaction
-	ReferenceID  string          // Optional reference (e.g., game ID for bets)
+	ReferenceID  string          // Round or hand the transaction belongs to, empty for loans and grants
 	Description  string          // Human-readable description
 	Timestamp    time.Time       // When the transaction occurred
 	BalanceAfter int64           // Balance after this transaction
+	Entries      []LedgerEntry   // Balanced ledger entries the transaction posted, they add up to zero
+}
+
+// Hold is chips taken out of a player's wallet for a round that isn't over
+// yet. The round captures them once it's paid, or gives them back if it's
+// abandoned.
+type Hold struct {
+	UserID    string    // Player the chips were taken from
+	RoundID   string    // Round the chips are staked in
+	TableID   string    // Table the round is played at, empty away from a table
+	Amount    int64     // Chips still held
+	CreatedAt time.Time // When the first chips were held
 }
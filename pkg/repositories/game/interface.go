@@ -2,23 +2,40 @@ package game
 
 import (
 	"context"
+	"errors"
 
 	"github.com/fadedpez/tucoramirez/pkg/entities"
 )
 
+// ErrEventOutOfOrder is returned when an event doesn't come right after the last one in its round
+var ErrEventOutOfOrder = errors.New("event out of order")
+
 //go:generate mockgen -source=$GOFILE -destination=mock/mock.go -package=mock_game
 
 // Repository defines storage operations for deck state and game results
 type Repository interface {
 	// Deck operations
-	SaveDeck(ctx context.Context, channelID string, deck []*entities.Card) error
-	GetDeck(ctx context.Context, channelID string) ([]*entities.Card, error)
+	SaveDeck(ctx context.Context, channelID string, deck *entities.Deck) error
+	GetDeck(ctx context.Context, channelID string) (*entities.Deck, error)
 
 	// Game results
 	SaveGameResult(ctx context.Context, result *entities.GameResult) error
 	GetPlayerResults(ctx context.Context, playerID string) ([]*entities.GameResult, error)
 	GetChannelResults(ctx context.Context, channelID string, limit int) ([]*entities.GameResult, error)
 
+	// Round event log, append-only and kept in sequence order
+	AppendEvent(ctx context.Context, event *EventRecord) error
+	GetRoundEvents(ctx context.Context, roundID string) ([]*EventRecord, error)
+
+	// Live tables, at most one snapshot per channel
+	SaveTableSnapshot(ctx context.Context, snapshot *TableSnapshot) error
+	GetTableSnapshots(ctx context.Context) ([]*TableSnapshot, error)
+	DeleteTableSnapshot(ctx context.Context, channelID string) error
+
+	// Card counting trainer answers, oldest first
+	SaveCountDrill(ctx context.Context, record *CountDrillRecord) error
+	GetCountDrills(ctx context.Context, playerID string) ([]*CountDrillRecord, error)
+
 	// Close closes any resources used by the repository
 	Close() error
 }
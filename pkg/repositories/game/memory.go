@@ -2,6 +2,7 @@ package game
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/fadedpez/tucoramirez/pkg/entities"
@@ -11,33 +12,48 @@ import (
 type MemoryRepository struct {
 	mu sync.RWMutex
 	// Map of channelID to deck
-	decks map[string][]*entities.Card
+	decks map[string]*entities.Deck
 	// Map of channelID to game results
 	channelResults map[string][]*entities.GameResult
 	// Map of playerID to game results
 	playerResults map[string][]*entities.GameResult
+	// Map of roundID to the round's event log
+	roundEvents map[string][]*EventRecord
+	// Map of channelID to the channel's live table
+	snapshots map[string]*TableSnapshot
+	// Map of playerID to the player's counting trainer answers
+	countDrills map[string][]*CountDrillRecord
 }
 
 // NewMemoryRepository creates a new in-memory repository
 func NewMemoryRepository() *MemoryRepository {
 	return &MemoryRepository{
-		decks:          make(map[string][]*entities.Card),
+		decks:          make(map[string]*entities.Deck),
 		channelResults: make(map[string][]*entities.GameResult),
 		playerResults:  make(map[string][]*entities.GameResult),
+		roundEvents:    make(map[string][]*EventRecord),
+		snapshots:      make(map[string]*TableSnapshot),
+		countDrills:    make(map[string][]*CountDrillRecord),
 	}
 }
 
 // SaveDeck stores a deck for a channel
-func (r *MemoryRepository) SaveDeck(ctx context.Context, channelID string, deck []*entities.Card) error {
+func (r *MemoryRepository) SaveDeck(ctx context.Context, channelID string, deck *entities.Deck) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.decks[channelID] = deck
+	// Store a copy so later draws from the caller's deck don't change what was saved
+	r.decks[channelID] = &entities.Deck{
+		Cards:   append([]*entities.Card(nil), deck.Cards...),
+		Seed:    deck.Seed,
+		Dealt:   deck.Dealt,
+		CutCard: deck.CutCard,
+	}
 	return nil
 }
 
 // GetDeck retrieves a deck for a channel
-func (r *MemoryRepository) GetDeck(ctx context.Context, channelID string) ([]*entities.Card, error) {
+func (r *MemoryRepository) GetDeck(ctx context.Context, channelID string) (*entities.Deck, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -45,7 +61,12 @@ func (r *MemoryRepository) GetDeck(ctx context.Context, channelID string) ([]*en
 	if !exists {
 		return nil, nil // Return empty deck if none exists
 	}
-	return deck, nil
+	return &entities.Deck{
+		Cards:   append([]*entities.Card(nil), deck.Cards...),
+		Seed:    deck.Seed,
+		Dealt:   deck.Dealt,
+		CutCard: deck.CutCard,
+	}, nil
 }
 
 // SaveGameResult stores a game result and updates both channel and player histories
@@ -99,6 +120,92 @@ func (r *MemoryRepository) GetChannelResults(ctx context.Context, channelID stri
 	return results, nil
 }
 
+// AppendEvent adds an event to the end of its round's log
+func (r *MemoryRepository) AppendEvent(ctx context.Context, event *EventRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := r.roundEvents[event.RoundID]
+	if event.Sequence != len(events) {
+		return fmt.Errorf("%w: round %s expected sequence %d, got %d", ErrEventOutOfOrder, event.RoundID, len(events), event.Sequence)
+	}
+
+	stored := *event
+	stored.Data = append([]byte(nil), event.Data...)
+	r.roundEvents[event.RoundID] = append(events, &stored)
+	return nil
+}
+
+// GetRoundEvents retrieves a round's event log in sequence order
+func (r *MemoryRepository) GetRoundEvents(ctx context.Context, roundID string) ([]*EventRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]*EventRecord, 0, len(r.roundEvents[roundID]))
+	for _, event := range r.roundEvents[roundID] {
+		stored := *event
+		events = append(events, &stored)
+	}
+	return events, nil
+}
+
+// SaveTableSnapshot stores the live table for a channel, replacing any earlier snapshot
+func (r *MemoryRepository) SaveTableSnapshot(ctx context.Context, snapshot *TableSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *snapshot
+	stored.Data = append([]byte(nil), snapshot.Data...)
+	stored.Fair = append([]byte(nil), snapshot.Fair...)
+	r.snapshots[snapshot.ChannelID] = &stored
+	return nil
+}
+
+// GetTableSnapshots retrieves the live tables of every channel
+func (r *MemoryRepository) GetTableSnapshots(ctx context.Context) ([]*TableSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshots := make([]*TableSnapshot, 0, len(r.snapshots))
+	for _, snapshot := range r.snapshots {
+		stored := *snapshot
+		snapshots = append(snapshots, &stored)
+	}
+	return snapshots, nil
+}
+
+// DeleteTableSnapshot removes a channel's live table
+func (r *MemoryRepository) DeleteTableSnapshot(ctx context.Context, channelID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.snapshots, channelID)
+	return nil
+}
+
+// SaveCountDrill stores a counting trainer answer
+func (r *MemoryRepository) SaveCountDrill(ctx context.Context, record *CountDrillRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *record
+	r.countDrills[record.PlayerID] = append(r.countDrills[record.PlayerID], &stored)
+	return nil
+}
+
+// GetCountDrills retrieves a player's counting trainer answers, oldest first
+func (r *MemoryRepository) GetCountDrills(ctx context.Context, playerID string) ([]*CountDrillRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]*CountDrillRecord, 0, len(r.countDrills[playerID]))
+	for _, record := range r.countDrills[playerID] {
+		stored := *record
+		records = append(records, &stored)
+	}
+	return records, nil
+}
+
 // Close is a no-op for memory repository since there are no resources to close
 func (r *MemoryRepository) Close() error {
 	return nil
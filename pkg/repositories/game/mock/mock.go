@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	entities "github.com/fadedpez/tucoramirez/pkg/entities"
+	game "github.com/fadedpez/tucoramirez/pkg/repositories/game"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,6 +40,20 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AppendEvent mocks base method.
+func (m *MockRepository) AppendEvent(ctx context.Context, event *game.EventRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendEvent", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendEvent indicates an expected call of AppendEvent.
+func (mr *MockRepositoryMockRecorder) AppendEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvent", reflect.TypeOf((*MockRepository)(nil).AppendEvent), ctx, event)
+}
+
 // Close mocks base method.
 func (m *MockRepository) Close() error {
 	m.ctrl.T.Helper()
@@ -53,6 +68,20 @@ func (mr *MockRepositoryMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepository)(nil).Close))
 }
 
+// DeleteTableSnapshot mocks base method.
+func (m *MockRepository) DeleteTableSnapshot(ctx context.Context, channelID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTableSnapshot", ctx, channelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTableSnapshot indicates an expected call of DeleteTableSnapshot.
+func (mr *MockRepositoryMockRecorder) DeleteTableSnapshot(ctx, channelID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTableSnapshot", reflect.TypeOf((*MockRepository)(nil).DeleteTableSnapshot), ctx, channelID)
+}
+
 // GetChannelResults mocks base method.
 func (m *MockRepository) GetChannelResults(ctx context.Context, channelID string, limit int) ([]*entities.GameResult, error) {
 	m.ctrl.T.Helper()
@@ -68,11 +97,26 @@ func (mr *MockRepositoryMockRecorder) GetChannelResults(ctx, channelID, limit an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelResults", reflect.TypeOf((*MockRepository)(nil).GetChannelResults), ctx, channelID, limit)
 }
 
+// GetCountDrills mocks base method.
+func (m *MockRepository) GetCountDrills(ctx context.Context, playerID string) ([]*game.CountDrillRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCountDrills", ctx, playerID)
+	ret0, _ := ret[0].([]*game.CountDrillRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCountDrills indicates an expected call of GetCountDrills.
+func (mr *MockRepositoryMockRecorder) GetCountDrills(ctx, playerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountDrills", reflect.TypeOf((*MockRepository)(nil).GetCountDrills), ctx, playerID)
+}
+
 // GetDeck mocks base method.
-func (m *MockRepository) GetDeck(ctx context.Context, channelID string) ([]*entities.Card, error) {
+func (m *MockRepository) GetDeck(ctx context.Context, channelID string) (*entities.Deck, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetDeck", ctx, channelID)
-	ret0, _ := ret[0].([]*entities.Card)
+	ret0, _ := ret[0].(*entities.Deck)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -98,8 +142,52 @@ func (mr *MockRepositoryMockRecorder) GetPlayerResults(ctx, playerID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlayerResults", reflect.TypeOf((*MockRepository)(nil).GetPlayerResults), ctx, playerID)
 }
 
+// GetRoundEvents mocks base method.
+func (m *MockRepository) GetRoundEvents(ctx context.Context, roundID string) ([]*game.EventRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoundEvents", ctx, roundID)
+	ret0, _ := ret[0].([]*game.EventRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoundEvents indicates an expected call of GetRoundEvents.
+func (mr *MockRepositoryMockRecorder) GetRoundEvents(ctx, roundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoundEvents", reflect.TypeOf((*MockRepository)(nil).GetRoundEvents), ctx, roundID)
+}
+
+// GetTableSnapshots mocks base method.
+func (m *MockRepository) GetTableSnapshots(ctx context.Context) ([]*game.TableSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTableSnapshots", ctx)
+	ret0, _ := ret[0].([]*game.TableSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTableSnapshots indicates an expected call of GetTableSnapshots.
+func (mr *MockRepositoryMockRecorder) GetTableSnapshots(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTableSnapshots", reflect.TypeOf((*MockRepository)(nil).GetTableSnapshots), ctx)
+}
+
+// SaveCountDrill mocks base method.
+func (m *MockRepository) SaveCountDrill(ctx context.Context, record *game.CountDrillRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCountDrill", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCountDrill indicates an expected call of SaveCountDrill.
+func (mr *MockRepositoryMockRecorder) SaveCountDrill(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCountDrill", reflect.TypeOf((*MockRepository)(nil).SaveCountDrill), ctx, record)
+}
+
 // SaveDeck mocks base method.
-func (m *MockRepository) SaveDeck(ctx context.Context, channelID string, deck []*entities.Card) error {
+func (m *MockRepository) SaveDeck(ctx context.Context, channelID string, deck *entities.Deck) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SaveDeck", ctx, channelID, deck)
 	ret0, _ := ret[0].(error)
@@ -125,3 +213,17 @@ func (mr *MockRepositoryMockRecorder) SaveGameResult(ctx, result any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGameResult", reflect.TypeOf((*MockRepository)(nil).SaveGameResult), ctx, result)
 }
+
+// SaveTableSnapshot mocks base method.
+func (m *MockRepository) SaveTableSnapshot(ctx context.Context, snapshot *game.TableSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTableSnapshot", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTableSnapshot indicates an expected call of SaveTableSnapshot.
+func (mr *MockRepositoryMockRecorder) SaveTableSnapshot(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTableSnapshot", reflect.TypeOf((*MockRepository)(nil).SaveTableSnapshot), ctx, snapshot)
+}
@@ -8,14 +8,20 @@ import (
 
 // GameRecord represents a record of a completed blackjack game
 type GameRecord struct {
-	ID            string       `json:"id" bson:"_id"`
-	GameType      string       `json:"game_type" bson:"game_type"`
-	ChannelID     string       `json:"channel_id" bson:"channel_id"`
-	StartTime     time.Time    `json:"start_time" bson:"start_time"`
-	EndTime       time.Time    `json:"end_time" bson:"end_time"`
-	PlayerRecords []HandRecord `json:"player_records" bson:"player_records"`
-	DealerCards   []string     `json:"dealer_cards" bson:"dealer_cards"`
-	DealerScore   int          `json:"dealer_score" bson:"dealer_score"`
+	ID              string           `json:"id" bson:"_id"`
+	GameType        string           `json:"game_type" bson:"game_type"`
+	ChannelID       string           `json:"channel_id" bson:"channel_id"`
+	StartTime       time.Time        `json:"start_time" bson:"start_time"`
+	EndTime         time.Time        `json:"end_time" bson:"end_time"`
+	PlayerRecords   []HandRecord     `json:"player_records" bson:"player_records"`
+	StrategyRecords []StrategyRecord `json:"strategy_records,omitempty" bson:"strategy_records,omitempty"`
+	DealerCards     []string         `json:"dealer_cards" bson:"dealer_cards"`
+	DealerScore     int              `json:"dealer_score" bson:"dealer_score"`
+	Decks           int              `json:"decks" bson:"decks"`
+	ShoeSeed        string           `json:"shoe_seed,omitempty" bson:"shoe_seed,omitempty"`
+	ShoePosition    int              `json:"shoe_position" bson:"shoe_position"`
+	NewShoeSeed     string           `json:"new_shoe_seed,omitempty" bson:"new_shoe_seed,omitempty"` // Shoe brought out when the round ran the first one out
+	DealOrder       []string         `json:"deal_order,omitempty" bson:"deal_order,omitempty"`
 }
 
 // HandRecord represents a record of a player's hand in a blackjack game
@@ -37,3 +43,52 @@ type HandRecord struct {
 	Actions       []string               `json:"actions" bson:"actions"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty" bson:"metadata,omitempty"`
 }
+
+// StrategyRecord is how closely a player followed basic strategy in a game
+type StrategyRecord struct {
+	PlayerID  string  `json:"player_id" bson:"player_id"`
+	Decisions int     `json:"decisions" bson:"decisions"` // Decisions basic strategy has an answer for
+	Correct   int     `json:"correct" bson:"correct"`     // Decisions that matched it
+	Accuracy  float64 `json:"accuracy" bson:"accuracy"`   // Correct over decisions, from 0 to 1
+}
+
+// EventRecord is one entry in a round's append-only event log
+type EventRecord struct {
+	RoundID   string    `json:"round_id" bson:"round_id"`
+	ChannelID string    `json:"channel_id" bson:"channel_id"`
+	Sequence  int       `json:"sequence" bson:"sequence"` // Position in the round's log, starting at 0
+	Type      string    `json:"type" bson:"type"`
+	PlayerID  string    `json:"player_id,omitempty" bson:"player_id,omitempty"`
+	HandID    string    `json:"hand_id,omitempty" bson:"hand_id,omitempty"`
+	Data      []byte    `json:"data" bson:"data"` // The full event as JSON
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Kinds of table a snapshot can hold
+const (
+	SnapshotKindLobby = "lobby"
+	SnapshotKindGame  = "game"
+	SnapshotKindFair  = "fair" // No table, just a provably fair channel's shoes
+)
+
+// TableSnapshot is the saved state of a channel's live table, so it can be
+// picked back up after a restart
+type TableSnapshot struct {
+	ChannelID string    `json:"channel_id" bson:"channel_id"`
+	Kind      string    `json:"kind" bson:"kind"`                             // SnapshotKindLobby, SnapshotKindGame or SnapshotKindFair
+	RoundID   string    `json:"round_id,omitempty" bson:"round_id,omitempty"` // Round being played, empty for a lobby
+	Data      []byte    `json:"data" bson:"data"`                             // The lobby or game as JSON
+	Fair      []byte    `json:"fair,omitempty" bson:"fair,omitempty"`         // The channel's provably fair shoes as JSON, if it has any
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// CountDrillRecord is one answer a player gave in the card counting trainer
+type CountDrillRecord struct {
+	PlayerID   string    `json:"player_id" bson:"player_id"`
+	System     string    `json:"system" bson:"system"`     // Count system trained, e.g. hi_lo
+	Question   string    `json:"question" bson:"question"` // Running or true count
+	Answer     float64   `json:"answer" bson:"answer"`     // What the player said
+	Count      float64   `json:"count" bson:"count"`       // What the count was
+	Correct    bool      `json:"correct" bson:"correct"`
+	AnsweredAt time.Time `json:"answered_at" bson:"answered_at"`
+}
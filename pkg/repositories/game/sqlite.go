@@ -1,6 +1,7 @@
 package game
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -20,6 +21,9 @@ const (
 	CREATE TABLE IF NOT EXISTS decks (
 		channel_id TEXT PRIMARY KEY,
 		cards TEXT NOT NULL,  -- JSON array of cards
+		seed TEXT,  -- Hex seed of the shuffle that produced the shoe
+		dealt INTEGER DEFAULT 0,  -- Cards drawn since the shuffle
+		cut_card INTEGER DEFAULT 0,  -- Cards dealt before the cut card comes out
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	)`
@@ -42,6 +46,7 @@ const (
 		player_id TEXT NOT NULL,
 		result TEXT NOT NULL,
 		score INTEGER NOT NULL,
+		metadata TEXT,  -- JSON object for side bets and other hand details
 		FOREIGN KEY (game_result_id) REFERENCES game_results(id) ON DELETE CASCADE
 	);
 	CREATE INDEX IF NOT EXISTS idx_player ON player_results(player_id)`
@@ -83,6 +88,44 @@ const (
 	);
 	CREATE INDEX IF NOT EXISTS idx_hand_records_player ON hand_records(player_id);
 	CREATE INDEX IF NOT EXISTS idx_hand_records_game ON hand_records(game_record_id)`
+
+	createRoundEventsTableSQL = `
+	CREATE TABLE IF NOT EXISTS round_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		round_id TEXT NOT NULL,
+		channel_id TEXT NOT NULL,
+		sequence INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		player_id TEXT,
+		hand_id TEXT,
+		data TEXT NOT NULL,  -- JSON of the full event
+		created_at TIMESTAMP NOT NULL,
+		UNIQUE (round_id, sequence)
+	);
+	CREATE INDEX IF NOT EXISTS idx_round_events_channel ON round_events(channel_id)`
+
+	createTableSnapshotsTableSQL = `
+	CREATE TABLE IF NOT EXISTS table_snapshots (
+		channel_id TEXT PRIMARY KEY,
+		kind TEXT NOT NULL,    -- lobby or game
+		round_id TEXT,
+		data TEXT NOT NULL,    -- JSON of the lobby or game
+		fair TEXT,             -- JSON of the channel's provably fair shoes
+		updated_at TIMESTAMP NOT NULL
+	)`
+
+	createCountDrillsTableSQL = `
+	CREATE TABLE IF NOT EXISTS count_drills (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		player_id TEXT NOT NULL,
+		system TEXT NOT NULL,    -- hi_lo, ko or omega_ii
+		question TEXT NOT NULL,  -- running or true
+		answer REAL NOT NULL,
+		count REAL NOT NULL,
+		correct BOOLEAN NOT NULL,
+		answered_at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_count_drills_player ON count_drills(player_id)`
 )
 
 // SQLiteRepository implements the Repository interface using SQLite
@@ -115,30 +158,37 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 }
 
 // SaveDeck stores a deck for a channel
-func (r *SQLiteRepository) SaveDeck(ctx context.Context, channelID string, deck []*entities.Card) error {
+func (r *SQLiteRepository) SaveDeck(ctx context.Context, channelID string, deck *entities.Deck) error {
 	// Convert deck to JSON
-	cardsJSON, err := json.Marshal(deck)
+	cardsJSON, err := json.Marshal(deck.Cards)
 	if err != nil {
 		return err
 	}
 
 	// Use UPSERT syntax for SQLite
 	query := `
-		INSERT INTO decks (channel_id, cards, updated_at)
-		VALUES (?, ?, CURRENT_TIMESTAMP)
+		INSERT INTO decks (channel_id, cards, seed, dealt, cut_card, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(channel_id) 
-		DO UPDATE SET cards = ?, updated_at = CURRENT_TIMESTAMP`
+		DO UPDATE SET cards = ?, seed = ?, dealt = ?, cut_card = ?, updated_at = CURRENT_TIMESTAMP`
 
-	_, err = r.db.ExecContext(ctx, query, channelID, cardsJSON, cardsJSON)
+	seed := deck.Seed.String()
+	_, err = r.db.ExecContext(ctx, query, channelID, cardsJSON, seed, deck.Dealt, deck.CutCard,
+		cardsJSON, seed, deck.Dealt, deck.CutCard)
 	return err
 }
 
 // GetDeck retrieves a deck for a channel
-func (r *SQLiteRepository) GetDeck(ctx context.Context, channelID string) ([]*entities.Card, error) {
-	var cardsJSON []byte
-	query := `SELECT cards FROM decks WHERE channel_id = ?`
-
-	err := r.db.QueryRowContext(ctx, query, channelID).Scan(&cardsJSON)
+func (r *SQLiteRepository) GetDeck(ctx context.Context, channelID string) (*entities.Deck, error) {
+	var (
+		cardsJSON []byte
+		seed      sql.NullString
+		dealt     sql.NullInt64
+		cutCard   sql.NullInt64
+	)
+	query := `SELECT cards, seed, dealt, cut_card FROM decks WHERE channel_id = ?`
+
+	err := r.db.QueryRowContext(ctx, query, channelID).Scan(&cardsJSON, &seed, &dealt, &cutCard)
 	if err == sql.ErrNoRows {
 		return nil, nil // Return empty deck if none exists
 	}
@@ -146,11 +196,18 @@ func (r *SQLiteRepository) GetDeck(ctx context.Context, channelID string) ([]*en
 		return nil, err
 	}
 
-	var deck []*entities.Card
-	if err := json.Unmarshal(cardsJSON, &deck); err != nil {
+	deck := &entities.Deck{Dealt: int(dealt.Int64), CutCard: int(cutCard.Int64)}
+	if err := json.Unmarshal(cardsJSON, &deck.Cards); err != nil {
 		return nil, err
 	}
 
+	// Decks saved before seeds were tracked can't be dealt again, but are still playable
+	if seed.Valid && seed.String != "" {
+		if deck.Seed, err = entities.ParseSeed(seed.String); err != nil {
+			return nil, err
+		}
+	}
+
 	return deck, nil
 }
 
@@ -192,13 +249,21 @@ func (r *SQLiteRepository) SaveGameResult(ctx context.Context, result *entities.
 
 	// Insert player results
 	for _, pr := range result.PlayerResults {
+		var metadataJSON []byte
+		if len(pr.Metadata) > 0 {
+			metadataJSON, err = json.Marshal(pr.Metadata)
+			if err != nil {
+				return err
+			}
+		}
+
 		query := `
 			INSERT INTO player_results (
-				game_result_id, player_id, result, score
-			) VALUES (?, ?, ?, ?)`
+				game_result_id, player_id, result, score, metadata
+			) VALUES (?, ?, ?, ?, ?)`
 
 		_, err = tx.ExecContext(ctx, query,
-			gameResultID, pr.PlayerID, pr.Result, pr.Score)
+			gameResultID, pr.PlayerID, pr.Result, pr.Score, metadataJSON)
 		if err != nil {
 			return err
 		}
@@ -212,7 +277,7 @@ func (r *SQLiteRepository) SaveGameResult(ctx context.Context, result *entities.
 func (r *SQLiteRepository) GetPlayerResults(ctx context.Context, playerID string) ([]*entities.GameResult, error) {
 	query := `
 		SELECT gr.id, gr.channel_id, gr.game_type, gr.completed_at, gr.details,
-			   pr.player_id, pr.result, pr.score
+			   pr.player_id, pr.result, pr.score, pr.metadata
 		FROM game_results gr
 		JOIN player_results pr ON gr.id = pr.game_result_id
 		WHERE pr.player_id = ?
@@ -229,19 +294,20 @@ func (r *SQLiteRepository) GetPlayerResults(ctx context.Context, playerID string
 
 	for rows.Next() {
 		var (
-			gameID      int64
-			channelID   string
-			gameType    string
-			completedAt time.Time
-			detailsJSON []byte
-			playerID    string
-			resultStr   string
-			score       int
+			gameID       int64
+			channelID    string
+			gameType     string
+			completedAt  time.Time
+			detailsJSON  []byte
+			playerID     string
+			resultStr    string
+			score        int
+			metadataJSON []byte
 		)
 
 		err := rows.Scan(
 			&gameID, &channelID, &gameType, &completedAt, &detailsJSON,
-			&playerID, &resultStr, &score,
+			&playerID, &resultStr, &score, &metadataJSON,
 		)
 		if err != nil {
 			return nil, err
@@ -275,6 +341,9 @@ func (r *SQLiteRepository) GetPlayerResults(ctx context.Context, playerID string
 			Result:   entities.StringResult(resultStr),
 			Score:    score,
 		}
+		if playerResult.Metadata, err = decodeResultMetadata(metadataJSON); err != nil {
+			return nil, err
+		}
 		result.PlayerResults = append(result.PlayerResults, playerResult)
 	}
 
@@ -350,7 +419,7 @@ func (r *SQLiteRepository) GetChannelResults(ctx context.Context, channelID stri
 	}
 
 	query = `
-		SELECT game_result_id, player_id, result, score
+		SELECT game_result_id, player_id, result, score, metadata
 		FROM player_results
 		WHERE game_result_id IN (` +
 		// Join placeholders with commas
@@ -374,13 +443,14 @@ func (r *SQLiteRepository) GetChannelResults(ctx context.Context, channelID stri
 	// Add player results to their respective games
 	for rows.Next() {
 		var (
-			gameID    int64
-			playerID  string
-			resultStr string
-			score     int
+			gameID       int64
+			playerID     string
+			resultStr    string
+			score        int
+			metadataJSON []byte
 		)
 
-		err := rows.Scan(&gameID, &playerID, &resultStr, &score)
+		err := rows.Scan(&gameID, &playerID, &resultStr, &score, &metadataJSON)
 		if err != nil {
 			return nil, err
 		}
@@ -392,6 +462,9 @@ func (r *SQLiteRepository) GetChannelResults(ctx context.Context, channelID stri
 				Result:   entities.StringResult(resultStr),
 				Score:    score,
 			}
+			if playerResult.Metadata, err = decodeResultMetadata(metadataJSON); err != nil {
+				return nil, err
+			}
 			result.PlayerResults = append(result.PlayerResults, playerResult)
 		}
 	}
@@ -399,7 +472,197 @@ func (r *SQLiteRepository) GetChannelResults(ctx context.Context, channelID stri
 	return results, nil
 }
 
+// decodeResultMetadata decodes a player result's saved metadata, keeping whole
+// numbers as int64 the way the game wrote them
+func decodeResultMetadata(data []byte) (map[string]interface{}, error) {
+	metadata := make(map[string]interface{})
+	if len(data) == 0 {
+		return metadata, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, err
+	}
+	for key, value := range metadata {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if intVal, err := number.Int64(); err == nil {
+			metadata[key] = intVal
+		} else if floatVal, err := number.Float64(); err == nil {
+			metadata[key] = floatVal
+		}
+	}
+	return metadata, nil
+}
+
 // Close closes the database connection
 func (r *SQLiteRepository) Close() error {
 	return r.db.Close()
 }
+
+// AppendEvent adds an event to the end of its round's log
+func (r *SQLiteRepository) AppendEvent(ctx context.Context, event *EventRecord) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The log is append-only, so the event has to come right after the last one
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM round_events WHERE round_id = ?`, event.RoundID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if event.Sequence != count {
+		return fmt.Errorf("%w: round %s expected sequence %d, got %d", ErrEventOutOfOrder, event.RoundID, count, event.Sequence)
+	}
+
+	query := `
+		INSERT INTO round_events (
+			round_id, channel_id, sequence, type, player_id, hand_id, data, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.ExecContext(ctx, query,
+		event.RoundID, event.ChannelID, event.Sequence, event.Type,
+		event.PlayerID, event.HandID, string(event.Data), event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetRoundEvents retrieves a round's event log in sequence order
+func (r *SQLiteRepository) GetRoundEvents(ctx context.Context, roundID string) ([]*EventRecord, error) {
+	query := `
+		SELECT round_id, channel_id, sequence, type, player_id, hand_id, data, created_at
+		FROM round_events
+		WHERE round_id = ?
+		ORDER BY sequence`
+
+	rows, err := r.db.QueryContext(ctx, query, roundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*EventRecord
+	for rows.Next() {
+		var (
+			event    EventRecord
+			playerID sql.NullString
+			handID   sql.NullString
+			data     string
+		)
+		err := rows.Scan(&event.RoundID, &event.ChannelID, &event.Sequence, &event.Type,
+			&playerID, &handID, &data, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		event.PlayerID = playerID.String
+		event.HandID = handID.String
+		event.Data = []byte(data)
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// SaveTableSnapshot stores the live table for a channel, replacing any earlier snapshot
+func (r *SQLiteRepository) SaveTableSnapshot(ctx context.Context, snapshot *TableSnapshot) error {
+	query := `
+		INSERT INTO table_snapshots (channel_id, kind, round_id, data, fair, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(channel_id)
+		DO UPDATE SET kind = excluded.kind, round_id = excluded.round_id, data = excluded.data, fair = excluded.fair, updated_at = excluded.updated_at`
+
+	var fair sql.NullString
+	if len(snapshot.Fair) > 0 {
+		fair = sql.NullString{String: string(snapshot.Fair), Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		snapshot.ChannelID, snapshot.Kind, snapshot.RoundID, string(snapshot.Data), fair, snapshot.UpdatedAt)
+	return err
+}
+
+// GetTableSnapshots retrieves the live tables of every channel
+func (r *SQLiteRepository) GetTableSnapshots(ctx context.Context) ([]*TableSnapshot, error) {
+	query := `SELECT channel_id, kind, round_id, data, fair, updated_at FROM table_snapshots ORDER BY updated_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []*TableSnapshot
+	for rows.Next() {
+		var (
+			snapshot TableSnapshot
+			roundID  sql.NullString
+			data     string
+			fair     sql.NullString
+		)
+		if err := rows.Scan(&snapshot.ChannelID, &snapshot.Kind, &roundID, &data, &fair, &snapshot.UpdatedAt); err != nil {
+			return nil, err
+		}
+		snapshot.RoundID = roundID.String
+		snapshot.Data = []byte(data)
+		if fair.Valid {
+			snapshot.Fair = []byte(fair.String)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// DeleteTableSnapshot removes a channel's live table
+func (r *SQLiteRepository) DeleteTableSnapshot(ctx context.Context, channelID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM table_snapshots WHERE channel_id = ?`, channelID)
+	return err
+}
+
+// SaveCountDrill stores a counting trainer answer
+func (r *SQLiteRepository) SaveCountDrill(ctx context.Context, record *CountDrillRecord) error {
+	query := `
+		INSERT INTO count_drills (player_id, system, question, answer, count, correct, answered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		record.PlayerID, record.System, record.Question, record.Answer, record.Count, record.Correct, record.AnsweredAt)
+	return err
+}
+
+// GetCountDrills retrieves a player's counting trainer answers, oldest first
+func (r *SQLiteRepository) GetCountDrills(ctx context.Context, playerID string) ([]*CountDrillRecord, error) {
+	query := `
+		SELECT player_id, system, question, answer, count, correct, answered_at
+		FROM count_drills
+		WHERE player_id = ?
+		ORDER BY answered_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*CountDrillRecord
+	for rows.Next() {
+		var record CountDrillRecord
+		err := rows.Scan(&record.PlayerID, &record.System, &record.Question,
+			&record.Answer, &record.Count, &record.Correct, &record.AnsweredAt)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+
+	return records, rows.Err()
+}
@@ -27,4 +27,65 @@ type Repository interface {
 
 	// GetTransactionsByType retrieves transactions of a specific type
 	GetTransactionsByType(ctx context.Context, userID string, transactionType entities.TransactionType, limit int) ([]*entities.Transaction, error)
+
+	// GetAllWallets retrieves every wallet
+	GetAllWallets(ctx context.Context) ([]*entities.Wallet, error)
+
+	// GetLedgerTotals sums up every account in the chip ledger
+	GetLedgerTotals(ctx context.Context) (*entities.LedgerTotals, error)
+
+	// GetOpenHolds retrieves every hold that's still open, ordered by round and user
+	GetOpenHolds(ctx context.Context) ([]*entities.Hold, error)
+
+	// GetLoans retrieves every loan made to a user, repaid or not, oldest first
+	GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error)
+
+	// GetOpenLoans retrieves every loan that's still open, oldest first
+	GetOpenLoans(ctx context.Context) ([]*entities.Loan, error)
+
+	// RunInTx runs fn as one unit of work: everything it changes is saved
+	// together if it returns nil and nothing is saved if it returns an error.
+	// Units of work don't interleave, fn must only use the UnitOfWork it's given.
+	RunInTx(ctx context.Context, fn func(uow UnitOfWork) error) error
+}
+
+// UnitOfWork is the set of wallet operations that can be run together in RunInTx
+type UnitOfWork interface {
+	// GetWallet retrieves a wallet by user ID
+	GetWallet(ctx context.Context, userID string) (*entities.Wallet, error)
+
+	// CreateWallet creates a wallet that doesn't exist yet
+	CreateWallet(ctx context.Context, wallet *entities.Wallet) error
+
+	// AdjustBalance adds amount to a wallet's balance and loanAmount to its
+	// loan, and returns the wallet after the change. Neither may go below
+	// zero, ErrInsufficientFunds is returned instead.
+	AdjustBalance(ctx context.Context, userID string, amount, loanAmount int64) (*entities.Wallet, error)
+
+	// AddTransaction records a new transaction and posts its ledger entries.
+	// The entries must balance, ErrUnbalancedEntries is returned otherwise.
+	AddTransaction(ctx context.Context, transaction *entities.Transaction) error
+
+	// PostEntries posts ledger entries that move chips between accounts
+	// without changing a wallet, such as settling a table's escrow. The
+	// entries must balance, ErrUnbalancedEntries is returned otherwise.
+	PostEntries(ctx context.Context, transferID, description string, entries []entities.LedgerEntry) error
+
+	// GetAccountBalance returns the balance of a ledger account, 0 if it has no entries
+	GetAccountBalance(ctx context.Context, account entities.LedgerAccount) (int64, error)
+
+	// GetHold retrieves a user's open hold for a round, ErrHoldNotFound if nothing is held
+	GetHold(ctx context.Context, userID, roundID string) (*entities.Hold, error)
+
+	// GetRoundHolds retrieves the open holds for a round, ordered by user
+	GetRoundHolds(ctx context.Context, roundID string) ([]*entities.Hold, error)
+
+	// SaveHold creates or updates a hold, and closes it once nothing is held any more
+	SaveHold(ctx context.Context, hold *entities.Hold) error
+
+	// GetUserOpenLoans retrieves a user's open loans, oldest first
+	GetUserOpenLoans(ctx context.Context, userID string) ([]*entities.Loan, error)
+
+	// SaveLoan creates or updates a loan
+	SaveLoan(ctx context.Context, loan *entities.Loan) error
 }
@@ -3,6 +3,7 @@ package wallet
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,21 +12,36 @@ import (
 )
 
 var (
-	ErrWalletNotFound = errors.New("wallet not found")
+	ErrWalletNotFound    = errors.New("wallet not found")
+	ErrWalletExists      = errors.New("wallet already exists")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrUnbalancedEntries = errors.New("ledger entries don't balance")
+	ErrHoldNotFound      = errors.New("hold not found")
 )
 
 // MemoryRepository implements Repository using in-memory storage
 type MemoryRepository struct {
 	wallets      map[string]*entities.Wallet
 	transactions map[string][]*entities.Transaction
+	ledger       []entities.LedgerEntry // Every ledger entry posted, in order
+	holds        map[holdKey]*entities.Hold
+	loans        map[string]*entities.Loan // Every loan, by ID
 	mu           sync.RWMutex
 }
 
+// holdKey identifies a user's hold for a round
+type holdKey struct {
+	userID  string
+	roundID string
+}
+
 // NewMemoryRepository creates a new in-memory wallet repository
 func NewMemoryRepository() *MemoryRepository {
 	return &MemoryRepository{
 		wallets:      make(map[string]*entities.Wallet),
 		transactions: make(map[string][]*entities.Transaction),
+		holds:        make(map[holdKey]*entities.Hold),
+		loans:        make(map[string]*entities.Loan),
 	}
 }
 
@@ -75,11 +91,22 @@ func (r *MemoryRepository) UpdateBalance(ctx context.Context, userID string, amo
 	return nil
 }
 
-// AddTransaction records a new transaction
+// AddTransaction records a new transaction and posts its ledger entries
 func (r *MemoryRepository) AddTransaction(ctx context.Context, transaction *entities.Transaction) error {
+	if !entities.EntriesBalance(transaction.Entries) {
+		return ErrUnbalancedEntries
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	r.addTransaction(transaction)
+	r.ledger = append(r.ledger, transaction.Entries...)
+	return nil
+}
+
+// addTransaction records a new transaction, the caller must hold r.mu
+func (r *MemoryRepository) addTransaction(transaction *entities.Transaction) {
 	// Generate a UUID if not provided
 	if transaction.ID == "" {
 		transaction.ID = uuid.New().String()
@@ -99,8 +126,6 @@ func (r *MemoryRepository) AddTransaction(ctx context.Context, transaction *enti
 	}
 
 	r.transactions[transaction.UserID] = append(r.transactions[transaction.UserID], &txCopy)
-
-	return nil
 }
 
 // GetTransactions retrieves recent transactions for a user
@@ -151,3 +176,306 @@ func (r *MemoryRepository) GetTransactionsByType(ctx context.Context, userID str
 
 	return filtered, nil
 }
+
+// GetAllWallets retrieves every wallet
+func (r *MemoryRepository) GetAllWallets(ctx context.Context) ([]*entities.Wallet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wallets := make([]*entities.Wallet, 0, len(r.wallets))
+	for _, wallet := range r.wallets {
+		walletCopy := *wallet
+		wallets = append(wallets, &walletCopy)
+	}
+	return wallets, nil
+}
+
+// GetLedgerTotals sums up every account in the chip ledger
+func (r *MemoryRepository) GetLedgerTotals(ctx context.Context) (*entities.LedgerTotals, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := &entities.LedgerTotals{Balances: make(map[entities.LedgerAccount]int64)}
+	for _, entry := range r.ledger {
+		totals.Balances[entry.Account] += entry.Amount
+		if entry.Account == entities.LedgerAccountMint {
+			if entry.Amount < 0 {
+				totals.Issued -= entry.Amount
+			} else {
+				totals.Burned += entry.Amount
+			}
+		}
+	}
+	return totals, nil
+}
+
+// GetOpenHolds retrieves every hold that's still open, ordered by round and user
+func (r *MemoryRepository) GetOpenHolds(ctx context.Context) ([]*entities.Hold, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	holds := make([]*entities.Hold, 0, len(r.holds))
+	for _, hold := range r.holds {
+		holdCopy := *hold
+		holds = append(holds, &holdCopy)
+	}
+	sortHolds(holds)
+	return holds, nil
+}
+
+// sortHolds orders holds by round and user
+func sortHolds(holds []*entities.Hold) {
+	sort.Slice(holds, func(i, j int) bool {
+		if holds[i].RoundID != holds[j].RoundID {
+			return holds[i].RoundID < holds[j].RoundID
+		}
+		return holds[i].UserID < holds[j].UserID
+	})
+}
+
+// GetLoans retrieves every loan made to a user, repaid or not, oldest first
+func (r *MemoryRepository) GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findLoans(func(loan *entities.Loan) bool { return loan.UserID == userID }), nil
+}
+
+// GetOpenLoans retrieves every loan that's still open, oldest first
+func (r *MemoryRepository) GetOpenLoans(ctx context.Context) ([]*entities.Loan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findLoans(func(loan *entities.Loan) bool { return loan.Status == entities.LoanStatusOpen }), nil
+}
+
+// findLoans returns copies of the loans that match, oldest first, the caller must hold r.mu
+func (r *MemoryRepository) findLoans(match func(loan *entities.Loan) bool) []*entities.Loan {
+	var loans []*entities.Loan
+	for _, loan := range r.loans {
+		if match(loan) {
+			loanCopy := *loan
+			loans = append(loans, &loanCopy)
+		}
+	}
+	sortLoans(loans)
+	return loans
+}
+
+// sortLoans orders loans oldest first
+func sortLoans(loans []*entities.Loan) {
+	sort.Slice(loans, func(i, j int) bool {
+		if !loans[i].IssuedAt.Equal(loans[j].IssuedAt) {
+			return loans[i].IssuedAt.Before(loans[j].IssuedAt)
+		}
+		return loans[i].ID < loans[j].ID
+	})
+}
+
+// RunInTx runs fn as one unit of work. The repository is locked for the whole
+// unit of work, and its changes are only applied once fn returns nil.
+func (r *MemoryRepository) RunInTx(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uow := &memoryUnitOfWork{
+		repo:    r,
+		wallets: make(map[string]*entities.Wallet),
+		holds:   make(map[holdKey]*entities.Hold),
+		loans:   make(map[string]*entities.Loan),
+	}
+	if err := fn(uow); err != nil {
+		return err
+	}
+
+	for userID, wallet := range uow.wallets {
+		r.wallets[userID] = wallet
+	}
+	for _, transaction := range uow.transactions {
+		r.addTransaction(transaction)
+	}
+	r.ledger = append(r.ledger, uow.ledger...)
+	for key, hold := range uow.holds {
+		if hold.Amount == 0 {
+			delete(r.holds, key)
+		} else {
+			r.holds[key] = hold
+		}
+	}
+	for id, loan := range uow.loans {
+		r.loans[id] = loan
+	}
+	return nil
+}
+
+// memoryUnitOfWork keeps a unit of work's changes aside until it's committed
+type memoryUnitOfWork struct {
+	repo         *MemoryRepository
+	wallets      map[string]*entities.Wallet // Wallets created or changed in the unit of work
+	transactions []*entities.Transaction     // Transactions recorded in the unit of work
+	ledger       []entities.LedgerEntry      // Ledger entries posted in the unit of work
+	holds        map[holdKey]*entities.Hold  // Holds made, changed or closed in the unit of work
+	loans        map[string]*entities.Loan   // Loans made or changed in the unit of work
+}
+
+// wallet returns the wallet as the unit of work sees it, or nil if it doesn't exist
+func (u *memoryUnitOfWork) wallet(userID string) *entities.Wallet {
+	if wallet, exists := u.wallets[userID]; exists {
+		return wallet
+	}
+	return u.repo.wallets[userID]
+}
+
+// GetWallet retrieves a wallet by user ID
+func (u *memoryUnitOfWork) GetWallet(ctx context.Context, userID string) (*entities.Wallet, error) {
+	wallet := u.wallet(userID)
+	if wallet == nil {
+		return nil, ErrWalletNotFound
+	}
+
+	walletCopy := *wallet
+	return &walletCopy, nil
+}
+
+// CreateWallet creates a wallet that doesn't exist yet
+func (u *memoryUnitOfWork) CreateWallet(ctx context.Context, wallet *entities.Wallet) error {
+	if u.wallet(wallet.UserID) != nil {
+		return ErrWalletExists
+	}
+
+	wallet.LastUpdated = time.Now()
+	walletCopy := *wallet
+	u.wallets[wallet.UserID] = &walletCopy
+	return nil
+}
+
+// AdjustBalance changes a wallet's balance and loan, as long as neither goes below zero
+func (u *memoryUnitOfWork) AdjustBalance(ctx context.Context, userID string, amount, loanAmount int64) (*entities.Wallet, error) {
+	wallet := u.wallet(userID)
+	if wallet == nil {
+		return nil, ErrWalletNotFound
+	}
+	if wallet.Balance+amount < 0 || wallet.LoanAmount+loanAmount < 0 {
+		return nil, ErrInsufficientFunds
+	}
+
+	walletCopy := *wallet
+	walletCopy.Balance += amount
+	walletCopy.LoanAmount += loanAmount
+	walletCopy.LastUpdated = time.Now()
+	u.wallets[userID] = &walletCopy
+
+	result := walletCopy
+	return &result, nil
+}
+
+// AddTransaction records a new transaction and posts its ledger entries when
+// the unit of work is committed
+func (u *memoryUnitOfWork) AddTransaction(ctx context.Context, transaction *entities.Transaction) error {
+	if !entities.EntriesBalance(transaction.Entries) {
+		return ErrUnbalancedEntries
+	}
+
+	txCopy := *transaction
+	u.transactions = append(u.transactions, &txCopy)
+	u.ledger = append(u.ledger, transaction.Entries...)
+	return nil
+}
+
+// PostEntries posts ledger entries when the unit of work is committed
+func (u *memoryUnitOfWork) PostEntries(ctx context.Context, transferID, description string, entries []entities.LedgerEntry) error {
+	if !entities.EntriesBalance(entries) {
+		return ErrUnbalancedEntries
+	}
+
+	u.ledger = append(u.ledger, entries...)
+	return nil
+}
+
+// GetAccountBalance returns the balance of a ledger account as the unit of work sees it
+func (u *memoryUnitOfWork) GetAccountBalance(ctx context.Context, account entities.LedgerAccount) (int64, error) {
+	var balance int64
+	for _, entries := range [][]entities.LedgerEntry{u.repo.ledger, u.ledger} {
+		for _, entry := range entries {
+			if entry.Account == account {
+				balance += entry.Amount
+			}
+		}
+	}
+	return balance, nil
+}
+
+// hold returns the hold as the unit of work sees it, or nil if it isn't open
+func (u *memoryUnitOfWork) hold(key holdKey) *entities.Hold {
+	hold, exists := u.holds[key]
+	if !exists {
+		hold = u.repo.holds[key]
+	}
+	if hold == nil || hold.Amount == 0 {
+		return nil
+	}
+	return hold
+}
+
+// GetHold retrieves a user's open hold for a round
+func (u *memoryUnitOfWork) GetHold(ctx context.Context, userID, roundID string) (*entities.Hold, error) {
+	hold := u.hold(holdKey{userID: userID, roundID: roundID})
+	if hold == nil {
+		return nil, ErrHoldNotFound
+	}
+
+	holdCopy := *hold
+	return &holdCopy, nil
+}
+
+// GetRoundHolds retrieves the open holds for a round as the unit of work sees them
+func (u *memoryUnitOfWork) GetRoundHolds(ctx context.Context, roundID string) ([]*entities.Hold, error) {
+	var holds []*entities.Hold
+	seen := make(map[holdKey]bool)
+	for _, source := range []map[holdKey]*entities.Hold{u.holds, u.repo.holds} {
+		for key := range source {
+			if key.roundID != roundID || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if hold := u.hold(key); hold != nil {
+				holdCopy := *hold
+				holds = append(holds, &holdCopy)
+			}
+		}
+	}
+	sortHolds(holds)
+	return holds, nil
+}
+
+// SaveHold creates, updates or closes a hold when the unit of work is committed
+func (u *memoryUnitOfWork) SaveHold(ctx context.Context, hold *entities.Hold) error {
+	holdCopy := *hold
+	u.holds[holdKey{userID: hold.UserID, roundID: hold.RoundID}] = &holdCopy
+	return nil
+}
+
+// GetUserOpenLoans retrieves a user's open loans as the unit of work sees them, oldest first
+func (u *memoryUnitOfWork) GetUserOpenLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	var loans []*entities.Loan
+	for i, source := range []map[string]*entities.Loan{u.loans, u.repo.loans} {
+		for id, loan := range source {
+			if _, changed := u.loans[id]; i > 0 && changed {
+				continue
+			}
+			if loan.UserID == userID && loan.Status == entities.LoanStatusOpen {
+				loanCopy := *loan
+				loans = append(loans, &loanCopy)
+			}
+		}
+	}
+	sortLoans(loans)
+	return loans, nil
+}
+
+// SaveLoan creates or updates a loan when the unit of work is committed
+func (u *memoryUnitOfWork) SaveLoan(ctx context.Context, loan *entities.Loan) error {
+	loanCopy := *loan
+	u.loans[loan.ID] = &loanCopy
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	entities "github.com/fadedpez/tucoramirez/pkg/entities"
+	wallet "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -53,6 +54,81 @@ func (mr *MockRepositoryMockRecorder) AddTransaction(ctx, transaction any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockRepository)(nil).AddTransaction), ctx, transaction)
 }
 
+// GetAllWallets mocks base method.
+func (m *MockRepository) GetAllWallets(ctx context.Context) ([]*entities.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWallets", ctx)
+	ret0, _ := ret[0].([]*entities.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllWallets indicates an expected call of GetAllWallets.
+func (mr *MockRepositoryMockRecorder) GetAllWallets(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWallets", reflect.TypeOf((*MockRepository)(nil).GetAllWallets), ctx)
+}
+
+// GetLedgerTotals mocks base method.
+func (m *MockRepository) GetLedgerTotals(ctx context.Context) (*entities.LedgerTotals, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerTotals", ctx)
+	ret0, _ := ret[0].(*entities.LedgerTotals)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerTotals indicates an expected call of GetLedgerTotals.
+func (mr *MockRepositoryMockRecorder) GetLedgerTotals(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerTotals", reflect.TypeOf((*MockRepository)(nil).GetLedgerTotals), ctx)
+}
+
+// GetLoans mocks base method.
+func (m *MockRepository) GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoans", ctx, userID)
+	ret0, _ := ret[0].([]*entities.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoans indicates an expected call of GetLoans.
+func (mr *MockRepositoryMockRecorder) GetLoans(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoans", reflect.TypeOf((*MockRepository)(nil).GetLoans), ctx, userID)
+}
+
+// GetOpenHolds mocks base method.
+func (m *MockRepository) GetOpenHolds(ctx context.Context) ([]*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenHolds", ctx)
+	ret0, _ := ret[0].([]*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenHolds indicates an expected call of GetOpenHolds.
+func (mr *MockRepositoryMockRecorder) GetOpenHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenHolds", reflect.TypeOf((*MockRepository)(nil).GetOpenHolds), ctx)
+}
+
+// GetOpenLoans mocks base method.
+func (m *MockRepository) GetOpenLoans(ctx context.Context) ([]*entities.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenLoans", ctx)
+	ret0, _ := ret[0].([]*entities.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenLoans indicates an expected call of GetOpenLoans.
+func (mr *MockRepositoryMockRecorder) GetOpenLoans(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenLoans", reflect.TypeOf((*MockRepository)(nil).GetOpenLoans), ctx)
+}
+
 // GetTransactions mocks base method.
 func (m *MockRepository) GetTransactions(ctx context.Context, userID string, limit int) ([]*entities.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +174,20 @@ func (mr *MockRepositoryMockRecorder) GetWallet(ctx, userID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallet", reflect.TypeOf((*MockRepository)(nil).GetWallet), ctx, userID)
 }
 
+// RunInTx mocks base method.
+func (m *MockRepository) RunInTx(ctx context.Context, fn func(wallet.UnitOfWork) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunInTx indicates an expected call of RunInTx.
+func (mr *MockRepositoryMockRecorder) RunInTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInTx", reflect.TypeOf((*MockRepository)(nil).RunInTx), ctx, fn)
+}
+
 // SaveWallet mocks base method.
 func (m *MockRepository) SaveWallet(ctx context.Context, wallet *entities.Wallet) error {
 	m.ctrl.T.Helper()
@@ -125,3 +215,186 @@ func (mr *MockRepositoryMockRecorder) UpdateBalance(ctx, userID, amount any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockRepository)(nil).UpdateBalance), ctx, userID, amount)
 }
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// AddTransaction mocks base method.
+func (m *MockUnitOfWork) AddTransaction(ctx context.Context, transaction *entities.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransaction", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTransaction indicates an expected call of AddTransaction.
+func (mr *MockUnitOfWorkMockRecorder) AddTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockUnitOfWork)(nil).AddTransaction), ctx, transaction)
+}
+
+// AdjustBalance mocks base method.
+func (m *MockUnitOfWork) AdjustBalance(ctx context.Context, userID string, amount, loanAmount int64) (*entities.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalance", ctx, userID, amount, loanAmount)
+	ret0, _ := ret[0].(*entities.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalance indicates an expected call of AdjustBalance.
+func (mr *MockUnitOfWorkMockRecorder) AdjustBalance(ctx, userID, amount, loanAmount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalance", reflect.TypeOf((*MockUnitOfWork)(nil).AdjustBalance), ctx, userID, amount, loanAmount)
+}
+
+// CreateWallet mocks base method.
+func (m *MockUnitOfWork) CreateWallet(ctx context.Context, wallet *entities.Wallet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWallet", ctx, wallet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWallet indicates an expected call of CreateWallet.
+func (mr *MockUnitOfWorkMockRecorder) CreateWallet(ctx, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockUnitOfWork)(nil).CreateWallet), ctx, wallet)
+}
+
+// GetAccountBalance mocks base method.
+func (m *MockUnitOfWork) GetAccountBalance(ctx context.Context, account entities.LedgerAccount) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountBalance", ctx, account)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountBalance indicates an expected call of GetAccountBalance.
+func (mr *MockUnitOfWorkMockRecorder) GetAccountBalance(ctx, account any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalance", reflect.TypeOf((*MockUnitOfWork)(nil).GetAccountBalance), ctx, account)
+}
+
+// GetHold mocks base method.
+func (m *MockUnitOfWork) GetHold(ctx context.Context, userID, roundID string) (*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", ctx, userID, roundID)
+	ret0, _ := ret[0].(*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockUnitOfWorkMockRecorder) GetHold(ctx, userID, roundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockUnitOfWork)(nil).GetHold), ctx, userID, roundID)
+}
+
+// GetRoundHolds mocks base method.
+func (m *MockUnitOfWork) GetRoundHolds(ctx context.Context, roundID string) ([]*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoundHolds", ctx, roundID)
+	ret0, _ := ret[0].([]*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoundHolds indicates an expected call of GetRoundHolds.
+func (mr *MockUnitOfWorkMockRecorder) GetRoundHolds(ctx, roundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoundHolds", reflect.TypeOf((*MockUnitOfWork)(nil).GetRoundHolds), ctx, roundID)
+}
+
+// GetUserOpenLoans mocks base method.
+func (m *MockUnitOfWork) GetUserOpenLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOpenLoans", ctx, userID)
+	ret0, _ := ret[0].([]*entities.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOpenLoans indicates an expected call of GetUserOpenLoans.
+func (mr *MockUnitOfWorkMockRecorder) GetUserOpenLoans(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOpenLoans", reflect.TypeOf((*MockUnitOfWork)(nil).GetUserOpenLoans), ctx, userID)
+}
+
+// GetWallet mocks base method.
+func (m *MockUnitOfWork) GetWallet(ctx context.Context, userID string) (*entities.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWallet", ctx, userID)
+	ret0, _ := ret[0].(*entities.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWallet indicates an expected call of GetWallet.
+func (mr *MockUnitOfWorkMockRecorder) GetWallet(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallet", reflect.TypeOf((*MockUnitOfWork)(nil).GetWallet), ctx, userID)
+}
+
+// PostEntries mocks base method.
+func (m *MockUnitOfWork) PostEntries(ctx context.Context, transferID, description string, entries []entities.LedgerEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostEntries", ctx, transferID, description, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostEntries indicates an expected call of PostEntries.
+func (mr *MockUnitOfWorkMockRecorder) PostEntries(ctx, transferID, description, entries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostEntries", reflect.TypeOf((*MockUnitOfWork)(nil).PostEntries), ctx, transferID, description, entries)
+}
+
+// SaveHold mocks base method.
+func (m *MockUnitOfWork) SaveHold(ctx context.Context, hold *entities.Hold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveHold", ctx, hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveHold indicates an expected call of SaveHold.
+func (mr *MockUnitOfWorkMockRecorder) SaveHold(ctx, hold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHold", reflect.TypeOf((*MockUnitOfWork)(nil).SaveHold), ctx, hold)
+}
+
+// SaveLoan mocks base method.
+func (m *MockUnitOfWork) SaveLoan(ctx context.Context, loan *entities.Loan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLoan", ctx, loan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLoan indicates an expected call of SaveLoan.
+func (mr *MockUnitOfWorkMockRecorder) SaveLoan(ctx, loan any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLoan", reflect.TypeOf((*MockUnitOfWork)(nil).SaveLoan), ctx, loan)
+}
@@ -0,0 +1,246 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// repositories returns every wallet repository, each empty
+func repositories(t *testing.T) map[string]Repository {
+	sqliteRepo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "wallets.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqliteRepo.Close() })
+
+	return map[string]Repository{
+		"memory": NewMemoryRepository(),
+		"sqlite": sqliteRepo,
+	}
+}
+
+func TestRunInTxRollsBack(t *testing.T) {
+	ctx := context.Background()
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				return uow.CreateWallet(ctx, &entities.Wallet{UserID: "player1", Balance: 100})
+			}))
+
+			// A failure after the balance changed leaves no trace of the change
+			failed := errors.New("failed")
+			err := repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				wallet, err := uow.AdjustBalance(ctx, "player1", -40, 0)
+				require.NoError(t, err)
+				assert.Equal(t, int64(60), wallet.Balance)
+				require.NoError(t, uow.AddTransaction(ctx, &entities.Transaction{UserID: "player1", Amount: -40, Type: entities.TransactionTypeBet, BalanceAfter: 60}))
+				return failed
+			})
+			assert.ErrorIs(t, err, failed)
+
+			wallet, err := repo.GetWallet(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(100), wallet.Balance)
+			transactions, err := repo.GetTransactions(ctx, "player1", 10)
+			require.NoError(t, err)
+			assert.Empty(t, transactions)
+		})
+	}
+}
+
+func TestAdjustBalanceNeverGoesNegative(t *testing.T) {
+	ctx := context.Background()
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			err := repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				require.NoError(t, uow.CreateWallet(ctx, &entities.Wallet{UserID: "player1", Balance: 50, LoanAmount: 100}))
+				assert.ErrorIs(t, uow.CreateWallet(ctx, &entities.Wallet{UserID: "player1"}), ErrWalletExists)
+
+				_, err := uow.AdjustBalance(ctx, "player1", -60, 0)
+				assert.ErrorIs(t, err, ErrInsufficientFunds)
+				_, err = uow.AdjustBalance(ctx, "player1", 0, -200)
+				assert.ErrorIs(t, err, ErrInsufficientFunds)
+				_, err = uow.AdjustBalance(ctx, "player2", 10, 0)
+				assert.ErrorIs(t, err, ErrWalletNotFound)
+
+				wallet, err := uow.AdjustBalance(ctx, "player1", -50, -50)
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), wallet.Balance)
+				assert.Equal(t, int64(50), wallet.LoanAmount)
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSaveHold(t *testing.T) {
+	ctx := context.Background()
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			hold := &entities.Hold{UserID: "player1", RoundID: "round1", TableID: "table1", Amount: 30, CreatedAt: time.Now()}
+			require.NoError(t, repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				require.NoError(t, uow.CreateWallet(ctx, &entities.Wallet{UserID: "player1", Balance: 100}))
+				return uow.SaveHold(ctx, hold)
+			}))
+
+			// Closing the hold in a unit of work that fails leaves it open
+			failed := errors.New("failed")
+			err := repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				require.NoError(t, uow.SaveHold(ctx, &entities.Hold{UserID: "player1", RoundID: "round1"}))
+				_, err := uow.GetHold(ctx, "player1", "round1")
+				assert.ErrorIs(t, err, ErrHoldNotFound)
+				return failed
+			})
+			assert.ErrorIs(t, err, failed)
+
+			holds, err := repo.GetOpenHolds(ctx)
+			require.NoError(t, err)
+			require.Len(t, holds, 1)
+			assert.Equal(t, int64(30), holds[0].Amount)
+			assert.Equal(t, "table1", holds[0].TableID)
+
+			require.NoError(t, repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				holds, err := uow.GetRoundHolds(ctx, "round1")
+				require.NoError(t, err)
+				require.Len(t, holds, 1)
+				holds[0].Amount = 0
+				return uow.SaveHold(ctx, holds[0])
+			}))
+			holds, err = repo.GetOpenHolds(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, holds)
+		})
+	}
+}
+
+func TestSaveLoan(t *testing.T) {
+	ctx := context.Background()
+	for name, repo := range repositories(t) {
+		t.Run(name, func(t *testing.T) {
+			issued := time.Now()
+			older := &entities.Loan{ID: "loan2", UserID: "player1", Principal: 100, Status: entities.LoanStatusOpen,
+				IssuedAt: issued, DueAt: issued.Add(time.Hour), AccruedAt: issued}
+			newer := &entities.Loan{ID: "loan1", UserID: "player1", Principal: 200, Status: entities.LoanStatusOpen,
+				IssuedAt: issued.Add(time.Millisecond), DueAt: issued.Add(time.Hour), AccruedAt: issued}
+			require.NoError(t, repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				require.NoError(t, uow.SaveLoan(ctx, newer))
+				return uow.SaveLoan(ctx, older)
+			}))
+
+			// Paying off a loan in a unit of work that fails leaves it open
+			failed := errors.New("failed")
+			err := repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				loans, err := uow.GetUserOpenLoans(ctx, "player1")
+				require.NoError(t, err)
+				require.Len(t, loans, 2)
+				loans[0].Repaid, loans[0].Status, loans[0].RepaidAt = 100, entities.LoanStatusRepaid, issued
+				require.NoError(t, uow.SaveLoan(ctx, loans[0]))
+				loans, err = uow.GetUserOpenLoans(ctx, "player1")
+				require.NoError(t, err)
+				assert.Len(t, loans, 1)
+				return failed
+			})
+			assert.ErrorIs(t, err, failed)
+
+			loans, err := repo.GetOpenLoans(ctx)
+			require.NoError(t, err)
+			require.Len(t, loans, 2)
+			assert.Equal(t, "loan2", loans[0].ID, "oldest first")
+			assert.True(t, loans[0].DueAt.Equal(older.DueAt))
+
+			require.NoError(t, repo.RunInTx(ctx, func(uow UnitOfWork) error {
+				loans, err := uow.GetUserOpenLoans(ctx, "player1")
+				require.NoError(t, err)
+				loans[0].Repaid, loans[0].Status, loans[0].RepaidAt = 100, entities.LoanStatusRepaid, issued
+				return uow.SaveLoan(ctx, loans[0])
+			}))
+			loans, err = repo.GetOpenLoans(ctx)
+			require.NoError(t, err)
+			require.Len(t, loans, 1)
+			assert.Equal(t, "loan1", loans[0].ID)
+
+			// Repaid loans stay in the history
+			loans, err = repo.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			require.Len(t, loans, 2)
+			assert.Equal(t, entities.LoanStatusRepaid, loans[0].Status)
+			assert.Zero(t, loans[0].Outstanding())
+		})
+	}
+}
+
+func TestOpeningReclassifiesOldTransactions(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "wallets.db")
+	repo, err := NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+
+	// Transactions the way they were recorded before they were typed
+	old := []struct{ id, transactionType, description string }{
+		{"start", "ISSUE", "Starting chips from Tuco"},
+		{"bet", "REPAYMENT", "Blackjack bet"},
+		{"double", "REPAYMENT", "Double down bet"},
+		{"side", "REPAYMENT", "21+3 side bet"},
+		{"insurance", "REPAYMENT", "Insurance side bet"},
+		{"held", "HOLD", "Split bet"},
+		{"win", "LOAN", "Blackjack winnings"},
+		{"side-win", "LOAN", "21+3 side bet winnings"},
+		{"refund", "LOAN", "Blackjack refund: round round1 was interrupted by a restart"},
+		{"released", "RELEASE", "Held chips given back"},
+		{"loan", "LOAN", "Loan from Tuco"},
+		{"repayment", "REPAYMENT", "Loan repayment to Tuco"},
+	}
+	for _, transaction := range old {
+		_, err := repo.db.Exec(`INSERT INTO transactions (id, user_id, amount, type, reference_id, description, balance_after) VALUES (?, 'player1', 10, ?, '', ?, 100)`,
+			transaction.id, transaction.transactionType, transaction.description)
+		require.NoError(t, err)
+	}
+	_, err = repo.db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersionUntyped))
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	repo, err = NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+
+	expected := map[string]entities.TransactionType{
+		"start":     entities.TransactionTypeGrant,
+		"bet":       entities.TransactionTypeBet,
+		"double":    entities.TransactionTypeBet,
+		"side":      entities.TransactionTypeSideBet,
+		"insurance": entities.TransactionTypeInsurance,
+		"held":      entities.TransactionTypeBet,
+		"win":       entities.TransactionTypePayout,
+		"side-win":  entities.TransactionTypePayout,
+		"refund":    entities.TransactionTypePushRefund,
+		"released":  entities.TransactionTypePushRefund,
+		"loan":      entities.TransactionTypeLoan,
+		"repayment": entities.TransactionTypeRepayment,
+	}
+	transactions, err := repo.GetTransactions(ctx, "player1", len(old))
+	require.NoError(t, err)
+	require.Len(t, transactions, len(old))
+	for _, transaction := range transactions {
+		assert.Equal(t, expected[transaction.ID], transaction.Type, transaction.Description)
+		if transaction.ID == "refund" {
+			assert.Equal(t, "round1", transaction.ReferenceID)
+		}
+	}
+
+	// It only happens once, anything recorded after is left alone
+	_, err = repo.db.Exec(`INSERT INTO transactions (id, user_id, amount, type, reference_id, description, balance_after) VALUES ('later', 'player1', 10, 'REPAYMENT', '', 'Blackjack bet', 100)`)
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+	repo, err = NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	repayments, err := repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypeRepayment, 10)
+	require.NoError(t, err)
+	assert.Len(t, repayments, 2)
+}
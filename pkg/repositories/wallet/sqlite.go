@@ -39,6 +39,75 @@ const (
 		FOREIGN KEY (user_id) REFERENCES wallets(user_id)
 	)`
 
+	createLedgerEntriesTableSQL = `
+	CREATE TABLE IF NOT EXISTS ledger_entries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		transfer_id TEXT NOT NULL,
+		account TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		description TEXT,
+		timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries(account);
+	CREATE INDEX IF NOT EXISTS idx_ledger_entries_transfer_id ON ledger_entries(transfer_id)
+	`
+
+	createHoldsTableSQL = `
+	CREATE TABLE IF NOT EXISTS holds (
+		user_id TEXT NOT NULL,
+		round_id TEXT NOT NULL,
+		table_id TEXT NOT NULL DEFAULT '',
+		amount INTEGER NOT NULL,
+		created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, round_id),
+		FOREIGN KEY (user_id) REFERENCES wallets(user_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_holds_round_id ON holds(round_id)
+	`
+
+	createLoansTableSQL = `
+	CREATE TABLE IF NOT EXISTS loans (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		principal INTEGER NOT NULL,
+		interest INTEGER NOT NULL DEFAULT 0,
+		repaid INTEGER NOT NULL DEFAULT 0,
+		status TEXT NOT NULL,
+		issued_at TEXT NOT NULL,
+		due_at TEXT NOT NULL,
+		accrued_at TEXT NOT NULL,
+		reminders_sent INTEGER NOT NULL DEFAULT 0,
+		repaid_at TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY (user_id) REFERENCES wallets(user_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_loans_user_id ON loans(user_id);
+	CREATE INDEX IF NOT EXISTS idx_loans_status ON loans(status)
+	`
+
+	// reclassifyTransactionsSQL types the transactions recorded before every
+	// wallet change said what it was, going by their descriptions. Back then
+	// everything was a LOAN or a REPAYMENT, or one of the types that are gone,
+	// so real loans and repayments are left as they are.
+	reclassifyTransactionsSQL = `
+	UPDATE transactions SET type = CASE
+		WHEN description IN ('Blackjack bet', 'Double down bet', 'Split bet', 'Blackjack back bet') THEN 'BET'
+		WHEN description IN ('Insurance bet', 'Insurance side bet') THEN 'INSURANCE'
+		WHEN description LIKE '%winnings' THEN 'PAYOUT'
+		WHEN description LIKE '%side bet' THEN 'SIDE_BET'
+		WHEN description LIKE 'Blackjack refund%' OR description = 'Held chips given back' THEN 'PUSH_REFUND'
+		WHEN description LIKE 'Starting chips%' OR type = 'ISSUE' THEN 'GRANT'
+		WHEN description LIKE 'Loan repayment%' THEN 'REPAYMENT'
+		WHEN description LIKE 'Loan from%' THEN 'LOAN'
+		ELSE type
+	END
+	WHERE type IN ('ISSUE', 'HOLD', 'RELEASE')
+		OR (type IN ('LOAN', 'REPAYMENT') AND description NOT LIKE 'Loan from%' AND description NOT LIKE 'Loan repayment%');
+	UPDATE transactions
+	SET reference_id = substr(description, 25, instr(description, ' was interrupted') - 25)
+	WHERE (reference_id IS NULL OR reference_id = '')
+		AND description LIKE 'Blackjack refund: round % was interrupted%'
+	`
+
 	createTransactionIndexesSQL = `
 	CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions(user_id);
 	CREATE INDEX IF NOT EXISTS idx_transactions_type ON transactions(type);
@@ -81,17 +150,132 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		return nil, fmt.Errorf("error creating transaction indexes: %w", err)
 	}
 
-	return &SQLiteRepository{db: db}, nil
+	if _, err := db.Exec(createLedgerEntriesTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating ledger entries table: %w", err)
+	}
+
+	if _, err := db.Exec(createHoldsTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating holds table: %w", err)
+	}
+
+	if _, err := db.Exec(createLoansTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating loans table: %w", err)
+	}
+
+	if err := upgradeSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// SQLite takes one writer at a time, queue wallet changes up here rather
+	// than fail them with "database is locked"
+	db.SetMaxOpenConns(1)
+
+	repo := &SQLiteRepository{db: db}
+	if err := repo.openLedger(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error opening ledger: %w", err)
+	}
+	return repo, nil
+}
+
+// Wallet schema versions, kept in the database's user_version
+const (
+	schemaVersionUntyped = 0 // Transactions weren't typed
+	schemaVersionTyped   = 1 // Every transaction says what it was
+)
+
+// upgradeSchema brings the data in a wallet database up to the current
+// schema version, each step runs once and only on databases from before it
+func upgradeSchema(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("error reading the wallet schema version: %w", err)
+	}
+	if version >= schemaVersionTyped {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting the wallet schema upgrade: %w", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(reclassifyTransactionsSQL); err != nil {
+		return fmt.Errorf("error reclassifying transactions: %w", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersionTyped)); err != nil {
+		return fmt.Errorf("error setting the wallet schema version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing the wallet schema upgrade: %w", err)
+	}
+	log.Printf("[WALLET_REPO] Upgraded the wallet schema from version %d to %d", version, schemaVersionTyped)
+	return nil
+}
+
+// openLedger posts an opening balance for every wallet the first time the
+// ledger is used on a database, so wallets from before the ledger reconcile.
+// The chips in them are issued by the mint, loans outstanding are owed to the loan desk.
+func (r *SQLiteRepository) openLedger(ctx context.Context) error {
+	var posted int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM ledger_entries`).Scan(&posted); err != nil {
+		return fmt.Errorf("error counting ledger entries: %w", err)
+	}
+	if posted > 0 {
+		return nil
+	}
+
+	wallets, err := r.GetAllWallets(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.RunInTx(ctx, func(uow UnitOfWork) error {
+		for _, wallet := range wallets {
+			if wallet.Balance == 0 && wallet.LoanAmount == 0 {
+				continue
+			}
+			entries := []entities.LedgerEntry{
+				{Account: entities.UserAccount(wallet.UserID), Amount: wallet.Balance},
+				{Account: entities.LedgerAccountLoanDesk, Amount: -wallet.LoanAmount},
+				{Account: entities.LedgerAccountMint, Amount: wallet.LoanAmount - wallet.Balance},
+			}
+			if err := uow.PostEntries(ctx, "opening-"+wallet.UserID, "Opening balance", entries); err != nil {
+				return err
+			}
+		}
+		log.Printf("[WALLET_REPO] Opened the ledger with the balances of %d wallets", len(wallets))
+		return nil
+	})
 }
 
 // GetWallet retrieves a wallet by user ID
 func (r *SQLiteRepository) GetWallet(ctx context.Context, userID string) (*entities.Wallet, error) {
+	return getWallet(ctx, r.db, userID)
+}
+
+// queryer is what getWallet needs, the database or a transaction on it
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// execer is what addTransaction needs, the database or a transaction on it
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// getWallet retrieves a wallet by user ID
+func getWallet(ctx context.Context, q queryer, userID string) (*entities.Wallet, error) {
 	query := `SELECT user_id, balance, loan_amount, updated_at FROM wallets WHERE user_id = ?`
 
 	var wallet entities.Wallet
 	var updatedAt string
 
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+	err := q.QueryRowContext(ctx, query, userID).Scan(
 		&wallet.UserID,
 		&wallet.Balance,
 		&wallet.LoanAmount,
@@ -105,28 +289,33 @@ func (r *SQLiteRepository) GetWallet(ctx context.Context, userID string) (*entit
 		return nil, fmt.Errorf("error getting wallet: %w", err)
 	}
 
-	// Parse the timestamp
+	wallet.LastUpdated, err = parseTimestamp(updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+// parseTimestamp parses a timestamp read back from SQLite
+func parseTimestamp(value string) (time.Time, error) {
 	// Try parsing with different formats since SQLite might store timestamps in different formats
 	formats := []string{
-		"2006-01-02 15:04:05",  // SQLite default format
-		"2006-01-02T15:04:05Z",  // ISO 8601 format
+		"2006-01-02 15:04:05",       // SQLite default format
+		"2006-01-02T15:04:05Z",      // ISO 8601 format
 		"2006-01-02T15:04:05-07:00", // ISO 8601 with timezone
-		time.RFC3339,              // Another common format
+		time.RFC3339,                // Another common format
 	}
 
 	var parseErr error
 	for _, format := range formats {
-		wallet.LastUpdated, parseErr = time.Parse(format, updatedAt)
-		if parseErr == nil {
-			break
+		parsed, err := time.Parse(format, value)
+		if err == nil {
+			return parsed, nil
 		}
+		parseErr = err
 	}
-
-	if parseErr != nil {
-		return nil, fmt.Errorf("error parsing timestamp '%s': %w", updatedAt, parseErr)
-	}
-
-	return &wallet, nil
+	return time.Time{}, fmt.Errorf("error parsing timestamp '%s': %w", value, parseErr)
 }
 
 // SaveWallet creates or updates a wallet
@@ -200,8 +389,19 @@ func (r *SQLiteRepository) UpdateBalance(ctx context.Context, userID string, amo
 	return nil
 }
 
-// AddTransaction records a new transaction
+// AddTransaction records a new transaction and posts its ledger entries together
 func (r *SQLiteRepository) AddTransaction(ctx context.Context, transaction *entities.Transaction) error {
+	return r.RunInTx(ctx, func(uow UnitOfWork) error {
+		return uow.AddTransaction(ctx, transaction)
+	})
+}
+
+// addTransaction records a new transaction and posts its ledger entries
+func addTransaction(ctx context.Context, e execer, transaction *entities.Transaction) error {
+	if !entities.EntriesBalance(transaction.Entries) {
+		return ErrUnbalancedEntries
+	}
+
 	// Generate ID if not provided
 	if transaction.ID == "" {
 		transaction.ID = uuid.New().String()
@@ -218,7 +418,7 @@ func (r *SQLiteRepository) AddTransaction(ctx context.Context, transaction *enti
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := e.ExecContext(ctx, query,
 		transaction.ID,
 		transaction.UserID,
 		transaction.Amount,
@@ -233,9 +433,189 @@ func (r *SQLiteRepository) AddTransaction(ctx context.Context, transaction *enti
 		return fmt.Errorf("error adding transaction: %w", err)
 	}
 
+	return postEntries(ctx, e, transaction.ID, transaction.Description, transaction.Timestamp, transaction.Entries)
+}
+
+// postEntries posts ledger entries for a transfer
+func postEntries(ctx context.Context, e execer, transferID, description string, timestamp time.Time, entries []entities.LedgerEntry) error {
+	if !entities.EntriesBalance(entries) {
+		return ErrUnbalancedEntries
+	}
+
+	query := `
+		INSERT INTO ledger_entries (transfer_id, account, amount, description, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	for _, entry := range entries {
+		if entry.Amount == 0 {
+			continue
+		}
+		_, err := e.ExecContext(ctx, query, transferID, entry.Account, entry.Amount, description,
+			timestamp.Format("2006-01-02 15:04:05"))
+		if err != nil {
+			return fmt.Errorf("error posting ledger entry: %w", err)
+		}
+	}
 	return nil
 }
 
+// GetAllWallets retrieves every wallet
+func (r *SQLiteRepository) GetAllWallets(ctx context.Context) ([]*entities.Wallet, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT user_id, balance, loan_amount, updated_at FROM wallets ORDER BY user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying wallets: %w", err)
+	}
+	defer rows.Close()
+
+	var wallets []*entities.Wallet
+	for rows.Next() {
+		var wallet entities.Wallet
+		var updatedAt string
+		if err := rows.Scan(&wallet.UserID, &wallet.Balance, &wallet.LoanAmount, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning wallet row: %w", err)
+		}
+		if wallet.LastUpdated, err = parseTimestamp(updatedAt); err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, &wallet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating wallet rows: %w", err)
+	}
+	return wallets, nil
+}
+
+// GetLedgerTotals sums up every account in the chip ledger
+func (r *SQLiteRepository) GetLedgerTotals(ctx context.Context) (*entities.LedgerTotals, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT account, SUM(amount) FROM ledger_entries GROUP BY account`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying ledger balances: %w", err)
+	}
+	defer rows.Close()
+
+	totals := &entities.LedgerTotals{Balances: make(map[entities.LedgerAccount]int64)}
+	for rows.Next() {
+		var account entities.LedgerAccount
+		var balance int64
+		if err := rows.Scan(&account, &balance); err != nil {
+			return nil, fmt.Errorf("error scanning ledger balance: %w", err)
+		}
+		totals.Balances[account] = balance
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ledger balances: %w", err)
+	}
+
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN amount < 0 THEN -amount ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN amount > 0 THEN amount ELSE 0 END), 0)
+		FROM ledger_entries
+		WHERE account = ?
+	`
+	if err := r.db.QueryRowContext(ctx, query, entities.LedgerAccountMint).Scan(&totals.Issued, &totals.Burned); err != nil {
+		return nil, fmt.Errorf("error summing issued chips: %w", err)
+	}
+	return totals, nil
+}
+
+// GetOpenHolds retrieves every hold that's still open, ordered by round and user
+func (r *SQLiteRepository) GetOpenHolds(ctx context.Context) ([]*entities.Hold, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT user_id, round_id, table_id, amount, created_at FROM holds ORDER BY round_id, user_id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying holds: %w", err)
+	}
+	return scanHolds(rows)
+}
+
+// scanHolds reads the holds a query returned and closes its rows
+func scanHolds(rows *sql.Rows) ([]*entities.Hold, error) {
+	defer rows.Close()
+
+	var holds []*entities.Hold
+	for rows.Next() {
+		var hold entities.Hold
+		var createdAt string
+		if err := rows.Scan(&hold.UserID, &hold.RoundID, &hold.TableID, &hold.Amount, &createdAt); err != nil {
+			return nil, fmt.Errorf("error scanning hold row: %w", err)
+		}
+		var err error
+		if hold.CreatedAt, err = parseTimestamp(createdAt); err != nil {
+			return nil, err
+		}
+		holds = append(holds, &hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hold rows: %w", err)
+	}
+	return holds, nil
+}
+
+// loanColumns are the columns scanLoans reads, in order
+const loanColumns = `id, user_id, principal, interest, repaid, status, issued_at, due_at, accrued_at, reminders_sent, repaid_at`
+
+// GetLoans retrieves every loan made to a user, repaid or not, oldest first
+func (r *SQLiteRepository) GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+loanColumns+` FROM loans WHERE user_id = ?`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying loans: %w", err)
+	}
+	return scanLoans(rows)
+}
+
+// GetOpenLoans retrieves every loan that's still open, oldest first
+func (r *SQLiteRepository) GetOpenLoans(ctx context.Context) ([]*entities.Loan, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+loanColumns+` FROM loans WHERE status = ?`, entities.LoanStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("error querying open loans: %w", err)
+	}
+	return scanLoans(rows)
+}
+
+// scanLoans reads the loans a query returned, oldest first, and closes its rows.
+// Loan times are stored to the nanosecond so loans taken in the same second
+// keep their order.
+func scanLoans(rows *sql.Rows) ([]*entities.Loan, error) {
+	defer rows.Close()
+
+	var loans []*entities.Loan
+	for rows.Next() {
+		var loan entities.Loan
+		var issuedAt, dueAt, accruedAt, repaidAt string
+		if err := rows.Scan(&loan.ID, &loan.UserID, &loan.Principal, &loan.Interest, &loan.Repaid, &loan.Status,
+			&issuedAt, &dueAt, &accruedAt, &loan.RemindersSent, &repaidAt); err != nil {
+			return nil, fmt.Errorf("error scanning loan row: %w", err)
+		}
+		for _, field := range []struct {
+			value string
+			into  *time.Time
+		}{{issuedAt, &loan.IssuedAt}, {dueAt, &loan.DueAt}, {accruedAt, &loan.AccruedAt}, {repaidAt, &loan.RepaidAt}} {
+			if field.value == "" {
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339Nano, field.value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing loan time '%s': %w", field.value, err)
+			}
+			*field.into = parsed
+		}
+		loans = append(loans, &loan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating loan rows: %w", err)
+	}
+	sortLoans(loans)
+	return loans, nil
+}
+
+// loanTime formats a loan time for storage, empty if it's zero
+func loanTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
 // GetTransactions retrieves recent transactions for a user
 func (r *SQLiteRepository) GetTransactions(ctx context.Context, userID string, limit int) ([]*entities.Transaction, error) {
 	query := `
@@ -372,6 +752,206 @@ func (r *SQLiteRepository) GetTransactionsByType(ctx context.Context, userID str
 	return transactions, nil
 }
 
+// RunInTx runs fn in one SQLite transaction, committed if fn returns nil and
+// rolled back otherwise
+func (r *SQLiteRepository) RunInTx(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	if err := fn(&sqliteUnitOfWork{tx: tx}); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Printf("[WALLET_REPO] Error rolling back transaction: %v", rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// sqliteUnitOfWork runs wallet operations in an SQLite transaction
+type sqliteUnitOfWork struct {
+	tx *sql.Tx
+}
+
+// GetWallet retrieves a wallet by user ID
+func (u *sqliteUnitOfWork) GetWallet(ctx context.Context, userID string) (*entities.Wallet, error) {
+	return getWallet(ctx, u.tx, userID)
+}
+
+// CreateWallet creates a wallet that doesn't exist yet
+func (u *sqliteUnitOfWork) CreateWallet(ctx context.Context, wallet *entities.Wallet) error {
+	wallet.LastUpdated = time.Now()
+	query := `
+		INSERT INTO wallets (user_id, balance, loan_amount, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(user_id) DO NOTHING
+	`
+
+	result, err := u.tx.ExecContext(ctx, query, wallet.UserID, wallet.Balance, wallet.LoanAmount,
+		wallet.LastUpdated.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("error creating wallet: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrWalletExists
+	}
+	return nil
+}
+
+// AdjustBalance changes a wallet's balance and loan in a single conditional
+// update, which only goes through if neither would go below zero
+func (u *sqliteUnitOfWork) AdjustBalance(ctx context.Context, userID string, amount, loanAmount int64) (*entities.Wallet, error) {
+	// The balance has to cover what's taken out of it, and the loan what's repaid
+	var minBalance, minLoan int64
+	if amount < 0 {
+		minBalance = -amount
+	}
+	if loanAmount < 0 {
+		minLoan = -loanAmount
+	}
+
+	query := `
+		UPDATE wallets
+		SET balance = balance + ?,
+			loan_amount = loan_amount + ?,
+			updated_at = ?
+		WHERE user_id = ? AND balance >= ? AND loan_amount >= ?
+	`
+
+	result, err := u.tx.ExecContext(ctx, query, amount, loanAmount, time.Now().Format("2006-01-02 15:04:05"),
+		userID, minBalance, minLoan)
+	if err != nil {
+		return nil, fmt.Errorf("error updating balance: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	wallet, err := getWallet(ctx, u.tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		// The wallet is there, it just can't cover the change
+		return nil, ErrInsufficientFunds
+	}
+	return wallet, nil
+}
+
+// AddTransaction records a new transaction and posts its ledger entries in the unit of work
+func (u *sqliteUnitOfWork) AddTransaction(ctx context.Context, transaction *entities.Transaction) error {
+	return addTransaction(ctx, u.tx, transaction)
+}
+
+// PostEntries posts ledger entries in the unit of work
+func (u *sqliteUnitOfWork) PostEntries(ctx context.Context, transferID, description string, entries []entities.LedgerEntry) error {
+	return postEntries(ctx, u.tx, transferID, description, time.Now(), entries)
+}
+
+// GetAccountBalance returns the balance of a ledger account
+func (u *sqliteUnitOfWork) GetAccountBalance(ctx context.Context, account entities.LedgerAccount) (int64, error) {
+	var balance int64
+	err := u.tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM ledger_entries WHERE account = ?`, account).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("error getting account balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetHold retrieves a user's open hold for a round
+func (u *sqliteUnitOfWork) GetHold(ctx context.Context, userID, roundID string) (*entities.Hold, error) {
+	rows, err := u.tx.QueryContext(ctx, `SELECT user_id, round_id, table_id, amount, created_at FROM holds WHERE user_id = ? AND round_id = ?`,
+		userID, roundID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying hold: %w", err)
+	}
+	holds, err := scanHolds(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(holds) == 0 {
+		return nil, ErrHoldNotFound
+	}
+	return holds[0], nil
+}
+
+// GetRoundHolds retrieves the open holds for a round
+func (u *sqliteUnitOfWork) GetRoundHolds(ctx context.Context, roundID string) ([]*entities.Hold, error) {
+	rows, err := u.tx.QueryContext(ctx, `SELECT user_id, round_id, table_id, amount, created_at FROM holds WHERE round_id = ? ORDER BY user_id`,
+		roundID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying round holds: %w", err)
+	}
+	return scanHolds(rows)
+}
+
+// SaveHold creates or updates a hold, and deletes it once nothing is held
+func (u *sqliteUnitOfWork) SaveHold(ctx context.Context, hold *entities.Hold) error {
+	if hold.Amount == 0 {
+		_, err := u.tx.ExecContext(ctx, `DELETE FROM holds WHERE user_id = ? AND round_id = ?`, hold.UserID, hold.RoundID)
+		if err != nil {
+			return fmt.Errorf("error closing hold: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO holds (user_id, round_id, table_id, amount, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, round_id) DO UPDATE SET amount = excluded.amount
+	`
+	_, err := u.tx.ExecContext(ctx, query, hold.UserID, hold.RoundID, hold.TableID, hold.Amount,
+		hold.CreatedAt.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("error saving hold: %w", err)
+	}
+	return nil
+}
+
+// GetUserOpenLoans retrieves a user's open loans, oldest first
+func (u *sqliteUnitOfWork) GetUserOpenLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	rows, err := u.tx.QueryContext(ctx, `SELECT `+loanColumns+` FROM loans WHERE user_id = ? AND status = ?`,
+		userID, entities.LoanStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("error querying open loans: %w", err)
+	}
+	return scanLoans(rows)
+}
+
+// SaveLoan creates or updates a loan
+func (u *sqliteUnitOfWork) SaveLoan(ctx context.Context, loan *entities.Loan) error {
+	query := `
+		INSERT INTO loans (` + loanColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			interest = excluded.interest,
+			repaid = excluded.repaid,
+			status = excluded.status,
+			due_at = excluded.due_at,
+			accrued_at = excluded.accrued_at,
+			reminders_sent = excluded.reminders_sent,
+			repaid_at = excluded.repaid_at
+	`
+	_, err := u.tx.ExecContext(ctx, query, loan.ID, loan.UserID, loan.Principal, loan.Interest, loan.Repaid, loan.Status,
+		loanTime(loan.IssuedAt), loanTime(loan.DueAt), loanTime(loan.AccruedAt), loan.RemindersSent, loanTime(loan.RepaidAt))
+	if err != nil {
+		return fmt.Errorf("error saving loan: %w", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (r *SQLiteRepository) Close() error {
 	return r.db.Close()
@@ -0,0 +1,176 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+var (
+	ErrBackBetSeated        = errors.New("seated players can't back a seat")
+	ErrBackBetClosed        = errors.New("back bets are closed for this seat")
+	ErrBackBetAlreadyPlaced = errors.New("back bet already placed on this seat")
+)
+
+// BackBet is a bet a spectator places behind a seat. It rides on the seat's
+// own hand as if it were played without doubling or splitting, so it wins,
+// pushes, loses or gets half back when that hand does, and a blackjack pays
+// the table's blackjack payout.
+type BackBet struct {
+	PlayerID string `json:"player_id"`        // Spectator who placed it
+	HandID   string `json:"hand_id"`          // Seat it rides on
+	Amount   int64  `json:"amount"`           // Stake
+	Payout   int64  `json:"payout,omitempty"` // Amount returned, stake included, 0 if it lost
+	Settled  bool   `json:"settled,omitempty"`
+}
+
+// PlaceBackBet places a spectator's bet behind a seat. Back bets are taken
+// while the table is betting and close with the deal. A back bet on a seat
+// that sits out is given back when the round is paid.
+func (g *Game) PlaceBackBet(ctx context.Context, playerID, handID string, amount int64, walletService WalletService) error {
+	if g.State != entities.StateBetting {
+		return ErrBackBetClosed
+	}
+	if len(g.HandsForPlayer(playerID)) > 0 {
+		return ErrBackBetSeated
+	}
+	if !g.IsSeat(handID) {
+		return ErrPlayerNotFound
+	}
+	if g.GetBackBet(playerID, handID) != nil {
+		return ErrBackBetAlreadyPlaced
+	}
+
+	if amount <= 0 {
+		return ErrInvalidBet
+	}
+	// A round being played back took its bets under the limits of the day
+	if !g.replaying {
+		if err := g.Rules.CheckBet(amount); err != nil {
+			return err
+		}
+	}
+
+	wallet, _, err := walletService.EnsureFundsWithLoan(ctx, playerID, amount, walletService.GetStandardLoanIncrement())
+	if err != nil {
+		return fmt.Errorf("error ensuring funds: %w", err)
+	}
+	if wallet.Balance < amount {
+		return ErrInsufficientFundsForAction
+	}
+	if err := g.stake(ctx, walletService, playerID, handID, amount, entities.TransactionTypeBet, "Blackjack back bet"); err != nil {
+		return fmt.Errorf("error updating wallet: %w", err)
+	}
+
+	g.BackBets = append(g.BackBets, &BackBet{PlayerID: playerID, HandID: handID, Amount: amount})
+	g.emit(Event{Type: EventBackBetPlaced, PlayerID: playerID, HandID: handID, Amount: amount})
+	log.Printf("Spectator %s backed seat %s for $%d in channel %s", playerID, handID, amount, g.ChannelID)
+	return nil
+}
+
+// GetBackBet returns a spectator's back bet on a seat, or nil if they haven't placed one
+func (g *Game) GetBackBet(playerID, handID string) *BackBet {
+	for _, backBet := range g.BackBets {
+		if backBet.PlayerID == playerID && backBet.HandID == handID {
+			return backBet
+		}
+	}
+	return nil
+}
+
+// BackBetsOn returns the back bets riding on a seat in the order they were placed
+func (g *Game) BackBetsOn(handID string) []*BackBet {
+	var backBets []*BackBet
+	for _, backBet := range g.BackBets {
+		if backBet.HandID == handID {
+			backBets = append(backBets, backBet)
+		}
+	}
+	return backBets
+}
+
+// backBetPayout works out what a back bet returns given the result of the hand it rides on
+func (g *Game) backBetPayout(amount int64, result entities.StringResult) int64 {
+	switch result {
+	case entities.StringResultBlackjack:
+		return amount + g.Rules.BlackjackPayout.Winnings(amount)
+	case entities.StringResultWin:
+		return amount * 2
+	case entities.StringResultPush:
+		return amount
+	case entities.StringResultSurrender:
+		return amount / 2
+	}
+	return 0
+}
+
+// PayBackBets settles the back bets against the seats' results and pays
+// them through the wallet service, once per spectator. It's safe to call
+// more than once, a back bet is only ever paid once.
+func (g *Game) PayBackBets(ctx context.Context, results []HandResult, walletService WalletService) error {
+	seatResults := make(map[string]entities.StringResult)
+	for _, result := range results {
+		seatResults[result.HandID] = result.Result
+	}
+
+	// Add up each spectator's winnings and stakes given back by pushes and
+	// surrenders, so they're paid in one go
+	var backers []string
+	payouts := make(map[string]int64)
+	refunds := make(map[string]int64)
+	settling := make(map[string][]*BackBet) // Bets the payouts settle
+	pushing := make(map[string][]*BackBet)  // Bets the refunds settle
+	for _, backBet := range g.BackBets {
+		if backBet.Settled {
+			continue
+		}
+		result, exists := seatResults[backBet.HandID]
+		if !exists {
+			// The seat never played, so the bet comes back
+			result = entities.StringResultPush
+		}
+		backBet.Payout = g.backBetPayout(backBet.Amount, result)
+
+		_, seen := settling[backBet.PlayerID]
+		if _, pushed := pushing[backBet.PlayerID]; !seen && !pushed {
+			backers = append(backers, backBet.PlayerID)
+		}
+		if result == entities.StringResultPush || result == entities.StringResultSurrender {
+			refunds[backBet.PlayerID] += backBet.Payout
+			pushing[backBet.PlayerID] = append(pushing[backBet.PlayerID], backBet)
+		} else {
+			payouts[backBet.PlayerID] += backBet.Payout
+			settling[backBet.PlayerID] = append(settling[backBet.PlayerID], backBet)
+		}
+	}
+
+	for _, playerID := range backers {
+		if payout := payouts[playerID]; payout > 0 {
+			if err := walletService.AddFunds(ctx, playerID, g.ChannelID, payout, entities.TransactionTypePayout, g.reference(""), "Blackjack back bet winnings"); err != nil {
+				log.Printf("Error paying back bets for spectator %s: %v", playerID, err)
+				return err
+			}
+		}
+		g.settleBackBets(playerID, settling[playerID])
+		if refund := refunds[playerID]; refund > 0 {
+			if err := walletService.AddFunds(ctx, playerID, g.ChannelID, refund, entities.TransactionTypePushRefund, g.reference(""), "Blackjack back bet given back"); err != nil {
+				log.Printf("Error giving back bets back to spectator %s: %v", playerID, err)
+				return err
+			}
+		}
+		g.settleBackBets(playerID, pushing[playerID])
+	}
+	return nil
+}
+
+// settleBackBets marks a spectator's back bets paid as soon as the chips are
+// moved, so paying the round again doesn't pay them twice
+func (g *Game) settleBackBets(playerID string, backBets []*BackBet) {
+	for _, backBet := range backBets {
+		backBet.Settled = true
+		g.emit(Event{Type: EventBackBetSettled, PlayerID: playerID, HandID: backBet.HandID, Amount: backBet.Payout})
+	}
+}
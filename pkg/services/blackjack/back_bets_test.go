@@ -0,0 +1,103 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceBackBet(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	g := newStackedGame(t, game.NewMemoryRepository(), []string{"player1", "player1#2", "player2"})
+
+	_, err := g.PlaceBetWithWalletUpdate(ctx, "player1", 20, wallet)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, g.PlaceBackBet(ctx, "player2", "player1", 10, wallet), ErrBackBetSeated)
+	assert.ErrorIs(t, g.PlaceBackBet(ctx, "spectator", "nobody", 10, wallet), ErrPlayerNotFound)
+	assert.ErrorIs(t, g.PlaceBackBet(ctx, "spectator", "player1", g.Rules.MaxBet+1, wallet), ErrBetAboveMaximum)
+
+	require.NoError(t, g.PlaceBackBet(ctx, "spectator", "player1", 20, wallet))
+	assert.Equal(t, int64(20), wallet.removed["spectator"])
+	assert.ErrorIs(t, g.PlaceBackBet(ctx, "spectator", "player1", 10, wallet), ErrBackBetAlreadyPlaced)
+	assert.Equal(t, map[string]int64{"player1": 20, "spectator": 20}, RoundStakes(g.Events))
+}
+
+func TestBackBetsFollowTheSeat(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	repo := game.NewMemoryRepository()
+	g := newStackedGame(t, repo, []string{"player1", "player1#2", "player2"},
+		// First card to each seat, then the dealer's up-card
+		&entities.Card{Rank: entities.Ten, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Ace, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Nine, Suit: entities.Diamonds},
+		// Second cards: player1's seats make 19 and 17, player2 has blackjack, the dealer 18
+		&entities.Card{Rank: entities.Nine, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Seven, Suit: entities.Spades},
+		&entities.Card{Rank: entities.King, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Nine, Suit: entities.Clubs},
+	)
+
+	_, err := g.PlaceBetWithWalletUpdate(ctx, "player1", 10, wallet)
+	require.NoError(t, err)
+	require.NoError(t, g.PlaceBackBet(ctx, "backer1", "player1", 10, wallet))
+	_, err = g.PlaceBetWithWalletUpdate(ctx, "player1", 20, wallet)
+	require.NoError(t, err)
+	require.NoError(t, g.PlaceBackBet(ctx, "backer1", "player1#2", 20, wallet))
+	// A seat can be backed before it bets
+	require.NoError(t, g.PlaceBackBet(ctx, "backer2", "player2", 10, wallet))
+	_, err = g.PlaceBetWithWalletUpdate(ctx, "player2", 20, wallet)
+	require.NoError(t, err)
+
+	// Back bets close with the deal
+	assert.ErrorIs(t, g.PlaceBackBet(ctx, "backer3", "player2", 10, wallet), ErrBackBetClosed)
+
+	for g.State != entities.StateComplete {
+		switch g.State {
+		case StateSpecialBets:
+			handID, err := g.GetCurrentSpecialBetsPlayerID()
+			require.NoError(t, err)
+			require.NoError(t, g.DeclineSpecialBet(g.HandOwner(handID)))
+		case entities.StatePlaying, entities.StateDealer:
+			if g.State == entities.StatePlaying {
+				handID, err := g.GetCurrentTurnPlayerID()
+				require.NoError(t, err)
+				require.NoError(t, g.Stand(g.HandOwner(handID)))
+			}
+			_, err := g.CompleteGameIfDone(ctx, wallet)
+			require.NoError(t, err)
+		default:
+			t.Fatalf("unexpected state %s", g.State)
+		}
+	}
+	require.True(t, g.PayoutsProcessed)
+
+	// backer1 won with one seat and lost with the other, and is paid once;
+	// backer2 rode player2's blackjack at 3:2
+	assert.Equal(t, int64(20), g.GetBackBet("backer1", "player1").Payout)
+	assert.Zero(t, g.GetBackBet("backer1", "player1#2").Payout)
+	assert.Equal(t, int64(25), g.GetBackBet("backer2", "player2").Payout)
+	assert.Equal(t, int64(20), wallet.added["backer1"])
+	assert.Equal(t, int64(25), wallet.added["backer2"])
+	for _, backBet := range g.BackBets {
+		assert.True(t, backBet.Settled)
+	}
+
+	// Paying again doesn't pay twice
+	results, err := g.GetResults()
+	require.NoError(t, err)
+	require.NoError(t, g.PayBackBets(ctx, results, wallet))
+	assert.Equal(t, int64(20), wallet.added["backer1"])
+
+	// The round plays back with the same back bets
+	rebuilt, err := LoadRound(ctx, repo, "test-channel", g.ID, g.Rules)
+	require.NoError(t, err)
+	assert.Equal(t, g.BackBets, rebuilt.BackBets)
+}
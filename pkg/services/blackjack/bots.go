@@ -0,0 +1,229 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/strategy"
+)
+
+var (
+	ErrUnknownBotPersonality = errors.New("unknown bot personality")
+	ErrNotBotTurn            = errors.New("not a computer player's turn")
+)
+
+// BotPersonality is how a computer player plays its hands
+type BotPersonality string
+
+const (
+	BotBasic    BotPersonality = "basic"    // Flat bets the minimum and plays basic strategy
+	BotReckless BotPersonality = "reckless" // Bets big, always insures and hits until it has 21 or busts
+	BotCounter  BotPersonality = "counter"  // Counts Hi-Lo, spreading its bet and insuring when the count is high
+)
+
+// BotPersonalities lists every personality in the order they're offered
+var BotPersonalities = []BotPersonality{BotBasic, BotReckless, BotCounter}
+
+// BotIDPrefix starts the player ID of every computer player. Discord IDs are
+// numbers, so a computer player can never be mistaken for a real one.
+const BotIDPrefix = "bot-"
+
+const (
+	BotRecklessUnits  = 5 // Minimum bets the reckless bot puts down every round
+	BotCounterSpread  = 8 // Most minimum bets the counter puts down on a high count
+	BotInsuranceCount = 3 // True count at which the counter takes insurance
+)
+
+// ParseBotPersonality reads a personality by name
+func ParseBotPersonality(name string) (BotPersonality, error) {
+	for _, personality := range BotPersonalities {
+		if string(personality) == strings.ToLower(strings.TrimSpace(name)) {
+			return personality, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownBotPersonality, name)
+}
+
+// Name is what Tuco calls computer players with the personality
+func (p BotPersonality) Name() string {
+	switch p {
+	case BotReckless:
+		return "Loco Lupe"
+	case BotCounter:
+		return "El Contador"
+	}
+	return "El Profesor"
+}
+
+// NewBotID returns the player ID for a table's nth computer player with a personality
+func NewBotID(personality BotPersonality, n int) string {
+	return fmt.Sprintf("%s%s-%d", BotIDPrefix, personality, n)
+}
+
+// IsBotPlayer returns true if the player is a computer player
+func IsBotPlayer(playerID string) bool {
+	return strings.HasPrefix(playerID, BotIDPrefix)
+}
+
+// parseBotID splits a computer player's ID into its personality and seat number
+func parseBotID(playerID string) (BotPersonality, int, bool) {
+	if !IsBotPlayer(playerID) {
+		return "", 0, false
+	}
+	name, number, _ := strings.Cut(strings.TrimPrefix(playerID, BotIDPrefix), "-")
+	personality, err := ParseBotPersonality(name)
+	if err != nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return "", 0, false
+	}
+	return personality, n, true
+}
+
+// BotPersonalityOf returns the personality of a computer player, or false if
+// the player isn't one
+func BotPersonalityOf(playerID string) (BotPersonality, bool) {
+	personality, _, ok := parseBotID(playerID)
+	return personality, ok
+}
+
+// BotName returns the name a computer player is shown under, numbered when
+// there's more than one of a personality at the table
+func BotName(playerID string) string {
+	personality, n, _ := parseBotID(playerID)
+	if n > 1 {
+		return fmt.Sprintf("🤖 %s %d", personality.Name(), n)
+	}
+	return "🤖 " + personality.Name()
+}
+
+// BotTurnDue returns true if a computer player is up and has sat on the
+// decision for the delay, so the table doesn't fly by faster than people read it
+func (g *Game) BotTurnDue(now time.Time, delay time.Duration) bool {
+	g.RefreshTurnDeadline(now)
+	return g.turnHandID != "" && IsBotPlayer(g.HandOwner(g.turnHandID)) && !now.Before(g.turnStarted.Add(delay))
+}
+
+// PlayBotTurn makes the decision for the computer player whose turn it is. It
+// returns the player and what they did.
+func (g *Game) PlayBotTurn(ctx context.Context, walletService WalletService) (string, EventType, error) {
+	handID := g.CurrentTurnHandID()
+	playerID := g.HandOwner(handID)
+	personality, isBot := BotPersonalityOf(playerID)
+	if handID == "" || !isBot {
+		return "", "", ErrNotBotTurn
+	}
+	log.Printf("Computer player %s is deciding in %s in channel %s", playerID, g.State, g.ChannelID)
+
+	switch g.State {
+	case entities.StateBetting:
+		_, err := g.PlaceBetWithWalletUpdate(ctx, playerID, g.botBet(ctx, personality), walletService)
+		return playerID, EventBetPlaced, err
+	case StateSplitting:
+		if g.botPlay(handID, personality) == strategy.ActionSplit {
+			return playerID, EventSplit, g.Split(ctx, playerID, walletService)
+		}
+		return playerID, EventSplitDeclined, g.DeclineSplit(playerID)
+	case StateInsurance, StateSpecialBets:
+		if g.IsEligibleForInsurance() && g.botInsures(ctx, personality) {
+			return playerID, EventSideBetPlaced, g.PlaceInsurance(ctx, playerID, walletService)
+		}
+		switch g.botPlay(handID, personality) {
+		case strategy.ActionDouble:
+			if g.State == StateSpecialBets && g.IsEligibleForDoubleDown(handID) {
+				return playerID, EventDoubleDown, g.DoubleDown(ctx, playerID, walletService)
+			}
+		case strategy.ActionSurrender:
+			return playerID, EventSurrender, g.Surrender(playerID)
+		}
+		return playerID, EventDeclined, g.DeclineSpecialBet(playerID)
+	case entities.StatePlaying:
+		if g.botPlay(handID, personality) == strategy.ActionHit {
+			return playerID, EventHit, g.Hit(playerID)
+		}
+		return playerID, EventStand, g.Stand(playerID)
+	}
+	return playerID, "", ErrNotBotTurn
+}
+
+// botPlay returns the play a computer player makes with a hand. The reckless
+// bot never splits, doubles or surrenders, it just hits until it can't.
+func (g *Game) botPlay(handID string, personality BotPersonality) strategy.Action {
+	if personality == BotReckless {
+		if hand, exists := g.Players[handID]; exists && GetBestScore(hand.Cards) < 21 {
+			return strategy.ActionHit
+		}
+		return strategy.ActionStand
+	}
+	return g.advise(handID)
+}
+
+// botInsures returns true if a computer player takes insurance. Basic strategy
+// never does, a counter does when the shoe is rich in tens.
+func (g *Game) botInsures(ctx context.Context, personality BotPersonality) bool {
+	switch personality {
+	case BotReckless:
+		return true
+	case BotCounter:
+		return g.trueCount(ctx) >= BotInsuranceCount
+	}
+	return false
+}
+
+// botBet returns what a computer player bets on the next round
+func (g *Game) botBet(ctx context.Context, personality BotPersonality) int64 {
+	units := int64(1)
+	switch personality {
+	case BotReckless:
+		units = BotRecklessUnits
+	case BotCounter:
+		// One unit until the count turns positive, then a unit per true count
+		units = min(max(1, int64(math.Floor(g.trueCount(ctx)))), BotCounterSpread)
+	}
+
+	bet := units * g.Rules.MinBet
+	if g.Rules.MaxBet > 0 && bet > g.Rules.MaxBet {
+		bet = g.Rules.MaxBet
+		if g.Rules.BetIncrement > 0 {
+			bet -= bet % g.Rules.BetIncrement
+		}
+	}
+	return bet
+}
+
+// trueCount returns the Hi-Lo true count of the shoe as the players see it.
+// Between rounds the shoe is still in the repository, and one due to be
+// reshuffled counts as a fresh shoe.
+func (g *Game) trueCount(ctx context.Context) float64 {
+	shoe := g.Deck
+	if g.State == entities.StateBetting {
+		if g.repo == nil {
+			return 0
+		}
+		saved, err := g.repo.GetDeck(ctx, g.ChannelID)
+		if err != nil || ShouldReshuffle(saved, g.Rules) {
+			return 0
+		}
+		shoe = saved
+	}
+	if shoe == nil {
+		return 0
+	}
+
+	// Nobody has seen the dealer's hole card until the dealer plays
+	unseen := shoe.Cards
+	if len(g.Dealer.Cards) >= 2 && g.State != entities.StateBetting &&
+		g.State != entities.StateDealer && g.State != entities.StateComplete {
+		unseen = append(append([]*entities.Card(nil), unseen...), g.Dealer.Cards[1])
+	}
+	return HiLo.UnseenTrueCount(unseen)
+}
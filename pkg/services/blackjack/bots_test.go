@@ -0,0 +1,163 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotIDs(t *testing.T) {
+	botID := NewBotID(BotCounter, 2)
+	assert.True(t, IsBotPlayer(botID))
+	assert.False(t, IsBotPlayer("123456789"))
+
+	personality, isBot := BotPersonalityOf(botID)
+	assert.True(t, isBot)
+	assert.Equal(t, BotCounter, personality)
+	assert.Equal(t, "🤖 El Contador 2", BotName(botID))
+	assert.Equal(t, "🤖 El Profesor", BotName(NewBotID(BotBasic, 1)))
+
+	_, err := ParseBotPersonality("cheater")
+	assert.ErrorIs(t, err, ErrUnknownBotPersonality)
+}
+
+func TestBotsPlayTheirTurns(t *testing.T) {
+	ctx := context.Background()
+	repo := game.NewMemoryRepository()
+	botID := NewBotID(BotBasic, 1)
+	g := newStackedGame(t, repo, []string{"player1", botID},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Six, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Seven, Suit: entities.Diamonds},
+		&entities.Card{Rank: entities.Six, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.King, Suit: entities.Hearts},
+	)
+	wallets := NewHouseMoneyWallet(newStubWalletService())
+
+	// Nothing happens for a real player's turn
+	_, _, err := g.PlayBotTurn(ctx, wallets)
+	assert.ErrorIs(t, err, ErrNotBotTurn)
+	_, err = g.PlaceBetWithWalletUpdate(ctx, "player1", 10, wallets)
+	require.NoError(t, err)
+
+	// The bot thinks it over before betting the table minimum
+	start := time.Now()
+	assert.False(t, g.BotTurnDue(start, time.Second))
+	assert.True(t, g.BotTurnDue(start.Add(time.Second), time.Second))
+	playerID, action, err := g.PlayBotTurn(ctx, wallets)
+	require.NoError(t, err)
+	assert.Equal(t, botID, playerID)
+	assert.Equal(t, EventBetPlaced, action)
+	assert.Equal(t, g.Rules.MinBet, g.Bets[botID])
+
+	// Seventeen and sixteen against a six both stand
+	for g.State == StateSpecialBets {
+		if IsBotPlayer(g.HandOwner(g.CurrentTurnHandID())) {
+			_, action, err = g.PlayBotTurn(ctx, wallets)
+			require.NoError(t, err)
+			assert.Equal(t, EventDeclined, action)
+		} else {
+			require.NoError(t, g.DeclineSpecialBet("player1"))
+		}
+	}
+	require.NoError(t, g.Stand("player1"))
+	_, action, err = g.PlayBotTurn(ctx, wallets)
+	require.NoError(t, err)
+	assert.Equal(t, EventStand, action)
+
+	// The dealer busts and the bot is paid in house money
+	complete, err := g.CompleteGameIfDone(ctx, wallets)
+	require.NoError(t, err)
+	if !complete {
+		_, err = g.CompleteGameIfDone(ctx, wallets)
+		require.NoError(t, err)
+	}
+	require.Equal(t, entities.StateComplete, g.State)
+
+	wallet, _, err := wallets.GetOrCreateWallet(ctx, botID)
+	require.NoError(t, err)
+	assert.Equal(t, BotBankroll+g.Rules.MinBet, wallet.Balance)
+
+	// Only the real player has a record
+	results, err := repo.GetPlayerResults(ctx, botID)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+	results, err = repo.GetPlayerResults(ctx, "player1")
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestRecklessBotHitsToTwentyOne(t *testing.T) {
+	ctx := context.Background()
+	botID := NewBotID(BotReckless, 1)
+	g := newStackedGame(t, game.NewMemoryRepository(), []string{botID},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Nine, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Two, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Seven, Suit: entities.Diamonds},
+		&entities.Card{Rank: entities.Five, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Four, Suit: entities.Clubs},
+	)
+	wallets := NewHouseMoneyWallet(newStubWalletService())
+
+	var actions []EventType
+	for g.State != entities.StateDealer && g.State != entities.StateComplete {
+		_, action, err := g.PlayBotTurn(ctx, wallets)
+		require.NoError(t, err)
+		actions = append(actions, action)
+	}
+
+	// Twelve hits to seventeen and hits again to 21, where even Loco Lupe stands
+	assert.Equal(t, []EventType{EventBetPlaced, EventDeclined, EventHit, EventHit, EventStand}, actions)
+	assert.Equal(t, BotRecklessUnits*g.Rules.MinBet, g.Bets[botID])
+	assert.Equal(t, 21, g.Players[botID].Value())
+}
+
+func TestCounterSpreadsItsBet(t *testing.T) {
+	ctx := context.Background()
+	tens := make([]*entities.Card, 0, 26)
+	smalls := make([]*entities.Card, 0, 26)
+	for len(tens) < 26 {
+		tens = append(tens, &entities.Card{Rank: entities.King, Suit: entities.Spades})
+		smalls = append(smalls, &entities.Card{Rank: entities.Four, Suit: entities.Spades})
+	}
+	botID := NewBotID(BotCounter, 1)
+
+	// Half a deck of nothing but tens left is as hot as a shoe gets
+	g := newStackedGame(t, game.NewMemoryRepository(), []string{"player1", botID}, tens...)
+	assert.Equal(t, BotCounterSpread*g.Rules.MinBet, g.botBet(ctx, BotCounter))
+	assert.Equal(t, g.Rules.MinBet, g.botBet(ctx, BotBasic))
+
+	// A cold shoe gets the minimum
+	g = newStackedGame(t, game.NewMemoryRepository(), []string{"player1", botID}, smalls...)
+	assert.Equal(t, g.Rules.MinBet, g.botBet(ctx, BotCounter))
+}
+
+func TestHouseMoneyWallet(t *testing.T) {
+	ctx := context.Background()
+	stub := newStubWalletService()
+	wallets := NewHouseMoneyWallet(stub)
+	botID := NewBotID(BotBasic, 1)
+
+	// Real players go through to their wallets
+	require.NoError(t, wallets.HoldFunds(ctx, "player1", "table1", "round1", 10, entities.TransactionTypeBet, "round1", "Blackjack bet"))
+	assert.Equal(t, int64(10), stub.removed["player1"])
+
+	// Computer players never touch them
+	require.NoError(t, wallets.HoldFunds(ctx, botID, "table1", "round1", BotBankroll, entities.TransactionTypeBet, "round1", "Blackjack bet"))
+	assert.NotContains(t, stub.removed, botID)
+	assert.ErrorIs(t, wallets.HoldFunds(ctx, botID, "table1", "round1", 10, entities.TransactionTypeBet, "round1", "Blackjack bet"), ErrInsufficientFundsForAction)
+
+	// The house tops a broke computer player back up instead of lending to them
+	wallet, loanGiven, err := wallets.EnsureFundsWithLoan(ctx, botID, 10, 100)
+	require.NoError(t, err)
+	assert.False(t, loanGiven)
+	assert.Equal(t, BotBankroll, wallet.Balance)
+}
@@ -0,0 +1,67 @@
+package blackjack
+
+import (
+	"context"
+	"log"
+	"sort"
+)
+
+// roundProfits works out what each player won in the round less what they
+// staked, their hands, side bets and back bets together. Stakes given back,
+// by a push or a surrender, aren't winnings.
+func (g *Game) roundProfits(results []HandResult) map[string]int64 {
+	profits := make(map[string]int64)
+	for _, result := range results {
+		profits[result.PlayerID] += result.Payout - result.Bet
+	}
+	for handID, placed := range g.SideBets {
+		for _, sideBet := range placed {
+			if sideBet.Paid {
+				profits[g.HandOwner(handID)] += sideBet.Payout - sideBet.Amount
+			}
+		}
+	}
+	for _, backBet := range g.BackBets {
+		if backBet.Settled {
+			profits[backBet.PlayerID] += backBet.Payout - backBet.Amount
+		}
+	}
+	return profits
+}
+
+// collectFromProfits takes Tuco's cut of every player's profit on the round
+func (g *Game) collectFromProfits(ctx context.Context, walletService WalletService, results []HandResult) {
+	profits := g.roundProfits(results)
+	playerIDs := make([]string, 0, len(profits))
+	for playerID := range profits {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+	for _, playerID := range playerIDs {
+		g.collect(ctx, walletService, playerID, profits[playerID])
+	}
+}
+
+// collect takes Tuco's cut of what a player just won while they owe him on an
+// overdue loan, and records it for the results
+func (g *Game) collect(ctx context.Context, walletService WalletService, playerID string, winnings int64) {
+	if winnings <= 0 {
+		return
+	}
+
+	collected, err := walletService.CollectFromWinnings(ctx, playerID, winnings, g.reference(""))
+	if err != nil {
+		log.Printf("Error collecting on player %s's overdue loan: %v", playerID, err)
+		return
+	}
+	if collected <= 0 {
+		return
+	}
+
+	log.Printf("Collected $%d from player %s's winnings for an overdue loan", collected, playerID)
+	if g.Collections == nil {
+		g.Collections = make(map[string]int64)
+	}
+	g.Collections[playerID] += collected
+	g.emit(Event{Type: EventCollected, PlayerID: playerID, Amount: collected})
+}
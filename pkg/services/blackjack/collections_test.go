@@ -0,0 +1,67 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	walletService "github.com/fadedpez/tucoramirez/pkg/services/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverdueLoansAreCollectedFromWinnings(t *testing.T) {
+	ctx := context.Background()
+	for seed := byte(0); seed < 20; seed++ {
+		service := walletService.NewService(walletRepo.NewMemoryRepository())
+		terms := walletService.DefaultLoanTerms()
+		terms.Term = time.Millisecond
+		service.SetLoanTerms(terms)
+		_, _, err := service.GiveLoan(ctx, "player1", 100)
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+
+		before, err := service.GetBalance(ctx, "player1")
+		require.NoError(t, err)
+
+		repo := game.NewMemoryRepository()
+		wallets := NewHouseMoneyWallet(service)
+		g := dealHeldRound(t, repo, wallets, seed)
+		playOutWith(t, g, wallets)
+
+		// What player1 came away with, before Tuco's cut, less what they staked
+		after, err := service.GetBalance(ctx, "player1")
+		require.NoError(t, err)
+		profit := after + g.Collections["player1"] - before
+		if profit <= 0 {
+			// player1 didn't come out ahead this seed, there's nothing to take a cut of
+			assert.Empty(t, g.Collections, "seed %d", seed)
+			continue
+		}
+
+		// Tuco takes a quarter of the profit, and nothing from the player who doesn't owe him
+		assert.Equal(t, map[string]int64{"player1": profit / 4}, g.Collections, "seed %d", seed)
+		var collected []Event
+		for _, event := range g.Events {
+			if event.Type == EventCollected {
+				collected = append(collected, event)
+			}
+		}
+		require.Len(t, collected, 1)
+		assert.Equal(t, "player1", collected[0].PlayerID)
+		assert.Equal(t, profit/4, collected[0].Amount)
+		wallet, _, err := service.GetOrCreateWallet(ctx, "player1")
+		require.NoError(t, err)
+		assert.Equal(t, 100-profit/4, wallet.LoanAmount)
+		_, err = service.Reconcile(ctx)
+		assert.NoError(t, err)
+
+		// The collection is part of the round's log and plays back with it
+		_, err = LoadRound(ctx, repo, g.ChannelID, g.ID, DefaultRuleSet())
+		require.NoError(t, err)
+		return
+	}
+	t.Fatal("player1 never won a round")
+}
@@ -0,0 +1,113 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+var ErrUnknownCountSystem = errors.New("unknown count system")
+
+// Count systems players can train with
+const (
+	CountSystemHiLo    = "hi_lo"
+	CountSystemKO      = "ko"
+	CountSystemOmegaII = "omega_ii"
+)
+
+// CountSystem is a card counting system: a tag for every card, added up into
+// a running count as the cards come out
+type CountSystem struct {
+	Name  string      // Identifies the system in commands and records
+	Title string      // How Tuco calls it
+	Tags  map[int]int // Tag of each card value, 11 for Aces
+	// What a full deck counts to. Balanced systems count to 0 and have a true
+	// count, unbalanced ones start below zero so the count needs no converting.
+	Imbalance int
+}
+
+var (
+	// HiLo is the classic: small cards +1, tens and Aces -1
+	HiLo = CountSystem{
+		Name:  CountSystemHiLo,
+		Title: "Hi-Lo",
+		Tags:  map[int]int{2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 0, 8: 0, 9: 0, 10: -1, 11: -1},
+	}
+
+	// KO is Hi-Lo with the sevens counted too, so it's unbalanced
+	KO = CountSystem{
+		Name:      CountSystemKO,
+		Title:     "Knock-Out (KO)",
+		Tags:      map[int]int{2: 1, 3: 1, 4: 1, 5: 1, 6: 1, 7: 1, 8: 0, 9: 0, 10: -1, 11: -1},
+		Imbalance: 4,
+	}
+
+	// OmegaII is a level two count that leaves the Aces out
+	OmegaII = CountSystem{
+		Name:  CountSystemOmegaII,
+		Title: "Omega II",
+		Tags:  map[int]int{2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 1, 8: 0, 9: -1, 10: -2, 11: 0},
+	}
+)
+
+// CountSystems lists the count systems in the order they're offered
+var CountSystems = []CountSystem{HiLo, KO, OmegaII}
+
+// CountSystemByName returns the count system with the given name
+func CountSystemByName(name string) (CountSystem, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, system := range CountSystems {
+		if system.Name == name {
+			return system, nil
+		}
+	}
+	return CountSystem{}, fmt.Errorf("%w: %s", ErrUnknownCountSystem, name)
+}
+
+// Balanced returns true if a full shoe counts to zero, so the system has a true count
+func (c CountSystem) Balanced() bool {
+	return c.Imbalance == 0
+}
+
+// Tag returns the card's tag in the system
+func (c CountSystem) Tag(card *entities.Card) int {
+	return c.Tags[GetCardValue(card)]
+}
+
+// InitialCount returns the running count a fresh shoe starts on. Unbalanced
+// systems start low enough that the count comes back up to 0 halfway through
+// the shoe's extra decks.
+func (c CountSystem) InitialCount(decks int) int {
+	return -c.Imbalance * (decks - 1)
+}
+
+// RunningCount returns the running count after the cards have been dealt from a fresh shoe
+func (c CountSystem) RunningCount(dealt []*entities.Card, decks int) int {
+	count := c.InitialCount(decks)
+	for _, card := range dealt {
+		count += c.Tag(card)
+	}
+	return count
+}
+
+// TrueCount converts a running count to a true count, the running count per
+// deck left in the shoe. Only balanced systems have a true count.
+func (c CountSystem) TrueCount(running int, cardsLeft int) float64 {
+	if cardsLeft <= 0 {
+		return 0
+	}
+	return float64(running) / (float64(cardsLeft) / 52)
+}
+
+// UnseenTrueCount works out the true count from the cards that haven't been
+// seen yet. In a balanced system the running count of the cards that are out
+// is minus the count of the ones still to come.
+func (c CountSystem) UnseenTrueCount(unseen []*entities.Card) float64 {
+	running := 0
+	for _, card := range unseen {
+		running -= c.Tag(card)
+	}
+	return c.TrueCount(running, len(unseen))
+}
@@ -0,0 +1,67 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountSystems(t *testing.T) {
+	dealt := []*entities.Card{
+		{Rank: entities.Two, Suit: entities.Hearts},
+		{Rank: entities.Five, Suit: entities.Spades},
+		{Rank: entities.Seven, Suit: entities.Clubs},
+		{Rank: entities.Nine, Suit: entities.Diamonds},
+		{Rank: entities.King, Suit: entities.Hearts},
+		{Rank: entities.Ace, Suit: entities.Spades},
+	}
+
+	tests := []struct {
+		system  CountSystem
+		initial int
+		running int
+	}{
+		{system: HiLo, initial: 0, running: 0},
+		{system: KO, initial: -20, running: -19},
+		{system: OmegaII, initial: 0, running: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.system.Name, func(t *testing.T) {
+			assert.Equal(t, tt.initial, tt.system.InitialCount(6))
+			assert.Equal(t, tt.running, tt.system.RunningCount(dealt, 6))
+
+			// A whole deck counts to the system's imbalance
+			assert.Equal(t, tt.system.Imbalance, tt.system.RunningCount(entities.NewDeck().Cards, 1))
+			assert.Equal(t, tt.system.Imbalance == 0, tt.system.Balanced())
+		})
+	}
+}
+
+func TestCountSystemByName(t *testing.T) {
+	system, err := CountSystemByName(" Omega_II ")
+	require.NoError(t, err)
+	assert.Equal(t, OmegaII.Name, system.Name)
+
+	_, err = CountSystemByName("wong_halves")
+	assert.ErrorIs(t, err, ErrUnknownCountSystem)
+}
+
+func TestTrueCount(t *testing.T) {
+	assert.InDelta(t, 4.0, HiLo.TrueCount(6, 78), 0.001)
+	assert.Zero(t, HiLo.TrueCount(3, 0))
+
+	// Half a deck left holding two more high cards than low ones means the
+	// running count is +2, which is +4 a deck
+	unseen := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Ace, Suit: entities.Spades},
+	}
+	for len(unseen) < 26 {
+		unseen = append(unseen, &entities.Card{Rank: entities.Eight, Suit: entities.Hearts})
+	}
+	assert.InDelta(t, 4.0, HiLo.UnseenTrueCount(unseen), 0.001)
+	assert.Zero(t, HiLo.UnseenTrueCount(nil))
+}
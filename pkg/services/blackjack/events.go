@@ -0,0 +1,349 @@
+package blackjack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+)
+
+// ErrEventLogMismatch is returned when a round's events can't be played back
+var ErrEventLogMismatch = errors.New("event log does not match the round")
+
+// DealerHandID identifies the dealer's hand in events
+const DealerHandID = "dealer"
+
+// EventType identifies what happened in a round
+type EventType string
+
+const (
+	EventRoundStarted   EventType = "round_started"    // Players sat down in seat order and betting opened
+	EventBetPlaced      EventType = "bet_placed"       // A player placed the main bet on one of their seats
+	EventBetCancelled   EventType = "bet_cancelled"    // A bet was taken back because the wallet couldn't cover it
+	EventShoeShuffled   EventType = "shoe_shuffled"    // A new shoe was brought out
+	EventShoeRanOut     EventType = "shoe_ran_out"     // The shoe ran out mid-round, the new one follows
+	EventCardDealt      EventType = "card_dealt"       // A card was dealt to a player's hand or the dealer
+	EventDealerPeeked   EventType = "dealer_peeked"    // The dealer checked the hole card for blackjack
+	EventInsurance      EventType = "insurance"        // A player took insurance, only in rounds logged before insurance was a side bet
+	EventSurrender      EventType = "surrender"        // A player surrendered a hand
+	EventDeclined       EventType = "declined"         // A player passed on insurance, surrender and doubling
+	EventSplit          EventType = "split"            // A player split a pair
+	EventSplitDeclined  EventType = "split_declined"   // A player kept a pair together
+	EventDoubleDown     EventType = "double_down"      // A player doubled down
+	EventHit            EventType = "hit"              // A player hit
+	EventStand          EventType = "stand"            // A player stood
+	EventDealerDraw     EventType = "dealer_draw"      // The dealer drew a card on their turn
+	EventDealerFinished EventType = "dealer_finished"  // The dealer finished their turn
+	EventPayout         EventType = "payout"           // A player was paid
+	EventRoundComplete  EventType = "round_complete"   // All payouts were made
+	EventRefund         EventType = "refund"           // A player's stakes were given back because the round couldn't finish
+	EventTimedOut       EventType = "timed_out"        // A player ran out of time and the default was played for them
+	EventSatOut         EventType = "sat_out"          // A seat that hadn't bet was taken out of the round
+	EventSideBetPlaced  EventType = "side_bet_placed"  // A player placed a side bet
+	EventSideBetSettled EventType = "side_bet_settled" // A side bet was judged and paid, Amount is 0 if it lost
+	EventSideBetVoided  EventType = "side_bet_voided"  // A side bet was given back because its seat sat out
+	EventBackBetPlaced  EventType = "back_bet_placed"  // A spectator backed a seat, PlayerID is the spectator and HandID the seat
+	EventBackBetSettled EventType = "back_bet_settled" // A back bet was paid, Amount is 0 if it lost
+	EventCollected      EventType = "loan_collected"   // Tuco took a cut of a player's winnings for an overdue loan
+)
+
+// Event is one state change in a round. Only the fields that matter for the
+// event's type are set.
+type Event struct {
+	Sequence  int            `json:"sequence"`
+	Type      EventType      `json:"type"`
+	Time      time.Time      `json:"time"`
+	PlayerID  string         `json:"player_id,omitempty"`   // Player who acted, was paid or was refunded
+	HandID    string         `json:"hand_id,omitempty"`     // Hand the event applies to, DealerHandID for the dealer
+	NewHandID string         `json:"new_hand_id,omitempty"` // Hand created by a split
+	Card      *entities.Card `json:"card,omitempty"`        // Card dealt or drawn
+	Amount    int64          `json:"amount,omitempty"`      // Chips bet, paid or refunded
+	Players   []string       `json:"players,omitempty"`     // Seat order when the round started
+	Seed      string         `json:"seed,omitempty"`        // Seed of a new shoe
+	Blackjack bool           `json:"blackjack,omitempty"`   // Whether a peek found blackjack
+	SideBet   string         `json:"side_bet,omitempty"`    // Side bet placed or settled, e.g. "perfect_pairs"
+}
+
+// isPlayerAction returns true for events that record a decision a player made
+func (e Event) isPlayerAction() bool {
+	switch e.Type {
+	case EventBetPlaced, EventSideBetPlaced, EventInsurance, EventSurrender, EventDeclined,
+		EventSplit, EventSplitDeclined, EventDoubleDown, EventHit, EventStand:
+		return true
+	}
+	return false
+}
+
+// emit appends an event to the round's log and persists it
+func (g *Game) emit(event Event) {
+	if g.replaying {
+		// The log being played back already has this event
+		return
+	}
+
+	event.Sequence = len(g.Events)
+	event.Time = time.Now()
+	g.Events = append(g.Events, event)
+
+	if g.repo == nil {
+		return
+	}
+	record, err := event.toRecord(g.ID, g.ChannelID)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", event.Type, err)
+		return
+	}
+	if err := g.repo.AppendEvent(context.Background(), record); err != nil {
+		log.Printf("Error saving %s event for round %s: %v", event.Type, g.ID, err)
+	}
+}
+
+// dealCard deals the next card from the shoe to a hand
+func (g *Game) dealCard(handID string, hand *Hand) error {
+	card := g.drawCard()
+	g.emit(Event{Type: EventCardDealt, PlayerID: g.HandOwner(handID), HandID: handID, Card: card})
+	return hand.AddCard(card)
+}
+
+// dealToDealer deals the next card from the shoe to the dealer
+func (g *Game) dealToDealer(eventType EventType) error {
+	card := g.drawCard()
+	g.emit(Event{Type: eventType, HandID: DealerHandID, Card: card})
+	return g.Dealer.AddCard(card)
+}
+
+// HandActions returns the decisions made on a hand, in order
+func (g *Game) HandActions(handID string) []string {
+	actions := []string{}
+	for _, event := range g.Events {
+		if event.HandID == handID && event.isPlayerAction() {
+			actions = append(actions, string(event.Type))
+		}
+	}
+	return actions
+}
+
+// toRecord converts the event to its stored form
+func (e Event) toRecord(roundID, channelID string) (*game.EventRecord, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return &game.EventRecord{
+		RoundID:   roundID,
+		ChannelID: channelID,
+		Sequence:  e.Sequence,
+		Type:      string(e.Type),
+		PlayerID:  e.PlayerID,
+		HandID:    e.HandID,
+		Data:      data,
+		CreatedAt: e.Time,
+	}, nil
+}
+
+// EventsFromRecords decodes a round's stored event log
+func EventsFromRecords(records []*game.EventRecord) ([]Event, error) {
+	events := make([]Event, 0, len(records))
+	for _, record := range records {
+		var event Event
+		if err := json.Unmarshal(record.Data, &event); err != nil {
+			return nil, fmt.Errorf("decoding event %d of round %s: %w", record.Sequence, record.RoundID, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// LoadRound rebuilds a round from the event log saved in the repository
+func LoadRound(ctx context.Context, repo game.Repository, channelID, roundID string, rules RuleSet) (*Game, error) {
+	records, err := repo.GetRoundEvents(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := EventsFromRecords(records)
+	if err != nil {
+		return nil, err
+	}
+	return RebuildGame(channelID, roundID, rules, events)
+}
+
+// RebuildGame rebuilds a round by folding its events in order. The cards come
+// from the dealt events and every decision is played again, so the rebuilt game
+// ends up in exactly the state the round was in. The rebuilt game has no
+// repository and is meant for history, debugging and replays.
+func RebuildGame(channelID, roundID string, rules RuleSet, events []Event) (*Game, error) {
+	g, err := newReplayGame(channelID, roundID, rules, events)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if err := g.apply(event); err != nil {
+			return nil, fmt.Errorf("%w: event %d (%s): %v", ErrEventLogMismatch, event.Sequence, event.Type, err)
+		}
+	}
+	if len(g.Deck.Cards) != 0 {
+		return nil, fmt.Errorf("%w: %d dealt cards were never drawn", ErrEventLogMismatch, len(g.Deck.Cards))
+	}
+
+	g.replaying = false
+	g.repo = nil
+	g.Events = append([]Event(nil), events...)
+	return g, nil
+}
+
+// newReplayGame sets up a game to play a round's events back into, with the
+// shoe stacked with the cards in the order they were dealt
+func newReplayGame(channelID, roundID string, rules RuleSet, events []Event) (*Game, error) {
+	var cards []*entities.Card
+	for _, event := range events {
+		if event.Type == EventCardDealt || event.Type == EventDealerDraw {
+			cards = append(cards, event.Card)
+		}
+	}
+	repo := game.NewMemoryRepository()
+	shoe := &entities.Deck{
+		Cards:   cards,
+		Dealt:   rules.ShoeSize() - len(cards),
+		CutCard: rules.ShoeSize(),
+	}
+	if err := repo.SaveDeck(context.Background(), channelID, shoe); err != nil {
+		return nil, err
+	}
+
+	g := NewGame(channelID, repo, rules, nil)
+	g.ID = roundID
+	g.replaying = true
+	return g, nil
+}
+
+// apply folds one event into the game
+func (g *Game) apply(event Event) error {
+	ctx := context.Background()
+
+	switch event.Type {
+	case EventRoundStarted:
+		for _, seatID := range event.Players {
+			// A player's extra seats come after their first
+			if playerID, _, ok := parseSeatID(seatID); ok {
+				g.addSeat(playerID, seatID)
+				continue
+			}
+			if err := g.AddPlayer(seatID); err != nil {
+				return err
+			}
+		}
+		g.PlayerOrder = append([]string(nil), event.Players...)
+		return g.Start()
+	case EventBetPlaced:
+		return g.PlaceBet(event.PlayerID, event.Amount)
+	case EventBetCancelled:
+		g.revertBet(event.HandID)
+	case EventSideBetPlaced:
+		return g.PlaceSideBet(ctx, event.SideBet, event.PlayerID, event.Amount, replayWallet{})
+	case EventSideBetSettled:
+		g.settleSideBets()
+		placed := g.GetSideBet(event.HandID, event.SideBet)
+		if placed == nil || !placed.Settled() {
+			return fmt.Errorf("%s side bet on hand %s was never judged", event.SideBet, event.HandID)
+		}
+		// Pay what was paid at the time, the pay table may have changed since
+		placed.Payout = event.Amount
+		return g.paySideBet(ctx, event.HandID, placed, replayWallet{})
+	case EventBackBetPlaced:
+		return g.PlaceBackBet(ctx, event.PlayerID, event.HandID, event.Amount, replayWallet{})
+	case EventBackBetSettled:
+		backBet := g.GetBackBet(event.PlayerID, event.HandID)
+		if backBet == nil {
+			return fmt.Errorf("back bet by %s on seat %s was never placed", event.PlayerID, event.HandID)
+		}
+		backBet.Payout = event.Amount
+		backBet.Settled = true
+	case EventInsurance:
+		// Older rounds logged insurance before it was a side bet
+		return g.PlaceInsurance(ctx, event.PlayerID, replayWallet{})
+	case EventSurrender:
+		return g.Surrender(event.PlayerID)
+	case EventDeclined:
+		return g.DeclineSpecialBet(event.PlayerID)
+	case EventSplit:
+		g.replayHandIDs = append(g.replayHandIDs, event.NewHandID)
+		return g.Split(ctx, event.PlayerID, replayWallet{})
+	case EventSplitDeclined:
+		return g.DeclineSplit(event.PlayerID)
+	case EventDoubleDown:
+		return g.DoubleDown(ctx, event.PlayerID, replayWallet{})
+	case EventHit:
+		return g.Hit(event.PlayerID)
+	case EventStand:
+		return g.Stand(event.PlayerID)
+	case EventSatOut:
+		return g.SitOut(ctx, event.HandID, replayWallet{})
+	case EventDealerFinished:
+		return g.PlayDealer()
+	case EventRoundComplete:
+		g.State = entities.StateComplete
+		g.PayoutsProcessed = true
+	case EventShoeShuffled, EventShoeRanOut, EventCardDealt, EventDealerPeeked, EventDealerDraw, EventPayout, EventCollected, EventRefund, EventTimedOut, EventSideBetVoided:
+		// These follow from the decisions above, the cards are already in the shoe
+	default:
+		return fmt.Errorf("unknown event type %q", event.Type)
+	}
+	return nil
+}
+
+// replayWallet covers every bet while a round is played back, the chips moved
+// when the round was first played
+type replayWallet struct{}
+
+func (replayWallet) GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error) {
+	return &entities.Wallet{UserID: userID}, false, nil
+}
+
+func (replayWallet) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	return nil
+}
+
+func (replayWallet) EnsureFundsWithLoan(ctx context.Context, userID string, requiredAmount int64, loanAmount int64) (*entities.Wallet, bool, error) {
+	return &entities.Wallet{UserID: userID, Balance: requiredAmount}, false, nil
+}
+
+func (replayWallet) GetStandardLoanIncrement() int64 {
+	return StandardLoanAmount
+}
+
+func (replayWallet) HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	return nil
+}
+
+func (replayWallet) CaptureHold(ctx context.Context, userID, roundID string, amount int64) error {
+	return nil
+}
+
+func (replayWallet) CaptureRoundHolds(ctx context.Context, roundID string) error {
+	return nil
+}
+
+func (replayWallet) ReleaseHold(ctx context.Context, userID, roundID string) (int64, error) {
+	return 0, nil
+}
+
+func (replayWallet) ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error {
+	return nil
+}
+
+func (replayWallet) ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+
+func (replayWallet) SettleTable(ctx context.Context, tableID string) error {
+	return nil
+}
+
+func (replayWallet) CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error) {
+	return 0, nil
+}
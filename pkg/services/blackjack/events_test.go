@@ -0,0 +1,173 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playOut plays a dealt round to the end, taking every kind of decision along the way
+func playOut(t *testing.T, g *Game) {
+	playOutWith(t, g, newStubWalletService())
+}
+
+// playOutWith plays out a dealt round like playOut, with the given wallets
+func playOutWith(t *testing.T, g *Game, wallet WalletService) {
+	ctx := context.Background()
+
+	for g.State != entities.StateComplete {
+		switch g.State {
+		case StateSplitting:
+			handID := g.GetCurrentSplittingPlayerID()
+			// Split everything except tens, like basic strategy
+			if g.IsEligibleForSplit(handID) && g.Players[handID].Value() != 20 {
+				require.NoError(t, g.Split(ctx, g.HandOwner(handID), wallet))
+			} else {
+				require.NoError(t, g.DeclineSplit(g.HandOwner(handID)))
+			}
+		case StateInsurance, StateSpecialBets:
+			handID, err := g.GetCurrentSpecialBetsPlayerID()
+			require.NoError(t, err)
+			value := g.Players[handID].Value()
+			switch {
+			case g.IsEligibleForInsurance():
+				require.NoError(t, g.PlaceInsurance(ctx, g.HandOwner(handID), wallet))
+			case g.State == StateSpecialBets && g.IsEligibleForDoubleDown(handID) && value >= 9 && value <= 11:
+				require.NoError(t, g.DoubleDown(ctx, g.HandOwner(handID), wallet))
+			case g.IsEligibleForSurrender(handID) && value == 16:
+				require.NoError(t, g.Surrender(g.HandOwner(handID)))
+			default:
+				require.NoError(t, g.DeclineSpecialBet(g.HandOwner(handID)))
+			}
+		case entities.StatePlaying:
+			handID, err := g.GetCurrentTurnPlayerID()
+			require.NoError(t, err)
+			if g.Players[handID].Value() < 15 {
+				require.NoError(t, g.Hit(g.HandOwner(handID)))
+			} else {
+				require.NoError(t, g.Stand(g.HandOwner(handID)))
+			}
+			_, err = g.CompleteGameIfDone(ctx, wallet)
+			require.NoError(t, err)
+		case entities.StateDealer:
+			_, err := g.CompleteGameIfDone(ctx, wallet)
+			require.NoError(t, err)
+		default:
+			t.Fatalf("unexpected state %s", g.State)
+		}
+	}
+	if !g.PayoutsProcessed {
+		require.NoError(t, g.ProcessPayouts(ctx, wallet))
+	}
+}
+
+func TestRebuildGameFromEvents(t *testing.T) {
+	seen := make(map[EventType]bool)
+	rules := DefaultRuleSet()
+	rules.Surrender = SurrenderLate
+	for seed := byte(0); seed < 40; seed++ {
+		repo := game.NewMemoryRepository()
+		g := NewGame("test-channel", repo, rules, entities.NewSeededShuffler(entities.Seed{seed}))
+		for _, playerID := range []string{"player1", "player2", "player3"} {
+			require.NoError(t, g.AddPlayer(playerID))
+		}
+		require.NoError(t, g.Start())
+		for _, playerID := range g.PlayerOrder {
+			require.NoError(t, g.PlaceBet(playerID, 20))
+		}
+		playOut(t, g)
+		for _, event := range g.Events {
+			seen[event.Type] = true
+		}
+
+		rebuilt, err := LoadRound(context.Background(), repo, "test-channel", g.ID, rules)
+		require.NoError(t, err, "seed %d", seed)
+
+		assert.Equal(t, g.State, rebuilt.State)
+		assert.True(t, rebuilt.PayoutsProcessed)
+		assert.Equal(t, g.PlayerOrder, rebuilt.PlayerOrder)
+		assert.Equal(t, g.Bets, rebuilt.Bets)
+		assert.Equal(t, g.SideBets, rebuilt.SideBets, "seed %d", seed)
+		assert.Equal(t, g.Dealer.Cards, rebuilt.Dealer.Cards)
+		require.Len(t, rebuilt.Players, len(g.Players))
+		for handID, hand := range g.Players {
+			require.Contains(t, rebuilt.Players, handID)
+			assert.Equal(t, hand.Cards, rebuilt.Players[handID].Cards, "seed %d hand %s", seed, handID)
+			assert.Equal(t, hand.Status, rebuilt.Players[handID].Status)
+			assert.Equal(t, hand.Metadata, rebuilt.Players[handID].Metadata)
+		}
+		require.Len(t, rebuilt.Events, len(g.Events))
+		for i, event := range g.Events {
+			assert.Equal(t, event.Type, rebuilt.Events[i].Type)
+			assert.Equal(t, event.HandID, rebuilt.Events[i].HandID)
+			assert.Equal(t, event.Card, rebuilt.Events[i].Card)
+		}
+	}
+
+	// The seeds cover every decision a player can make, insurance is a side bet
+	for _, eventType := range []EventType{EventSplit, EventSplitDeclined, EventSideBetPlaced, EventSideBetSettled, EventSurrender, EventDoubleDown, EventHit, EventStand, EventDealerDraw} {
+		assert.True(t, seen[eventType], "no %s in any round", eventType)
+	}
+}
+
+func TestRebuildGameMidRound(t *testing.T) {
+	g := dealRound(t, game.NewMemoryRepository(), entities.NewSeededShuffler(entities.Seed{3}))
+
+	rebuilt, err := RebuildGame(g.ChannelID, g.ID, g.Rules, g.Events)
+	require.NoError(t, err)
+	assert.Equal(t, g.State, rebuilt.State)
+	assert.Equal(t, g.CurrentTurn, rebuilt.CurrentTurn)
+	assert.Equal(t, g.CurrentSpecialBetsTurn, rebuilt.CurrentSpecialBetsTurn)
+	for handID, hand := range g.Players {
+		assert.Equal(t, hand.Cards, rebuilt.Players[handID].Cards)
+	}
+}
+
+func TestRebuildGameRejectsTamperedLog(t *testing.T) {
+	g := dealRound(t, game.NewMemoryRepository(), entities.NewSeededShuffler(entities.Seed{5}))
+
+	// Drop the last card dealt, the round can no longer be dealt again
+	var events []Event
+	dropped := false
+	for i := len(g.Events) - 1; i >= 0; i-- {
+		if !dropped && g.Events[i].Type == EventCardDealt {
+			dropped = true
+			continue
+		}
+		events = append([]Event{g.Events[i]}, events...)
+	}
+
+	_, err := RebuildGame(g.ChannelID, g.ID, g.Rules, events)
+	assert.ErrorIs(t, err, ErrEventLogMismatch)
+}
+
+func TestHandActionsAreRecorded(t *testing.T) {
+	g := newDealtGame(DefaultRuleSet(), entities.StateDealing, []*entities.Card{
+		{Rank: entities.Five, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}, []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Seven, Suit: entities.Diamonds},
+	})
+	g.Deck = &entities.Deck{Cards: []*entities.Card{{Rank: entities.Two, Suit: entities.Clubs}}}
+
+	require.NoError(t, g.beginSpecialPhases())
+	require.Equal(t, StateSpecialBets, g.State)
+	require.NoError(t, g.DeclineSpecialBet("player1"))
+	require.NoError(t, g.Hit("player1"))
+	require.NoError(t, g.Stand("player1"))
+
+	assert.Equal(t, []string{"declined", "hit", "stand"}, g.HandActions("player1"))
+
+	dealt := g.Events[len(g.Events)-2]
+	assert.Equal(t, EventCardDealt, dealt.Type)
+	assert.Equal(t, "player1", dealt.HandID)
+	assert.Equal(t, entities.Two, dealt.Card.Rank)
+	for i, event := range g.Events {
+		assert.Equal(t, i, event.Sequence)
+	}
+}
@@ -0,0 +1,313 @@
+package blackjack
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// Provably fair errors
+var (
+	ErrNotProvablyFair    = errors.New("table is not provably fair")
+	ErrInvalidClientSeed  = errors.New("invalid client seed")
+	ErrInvalidServerSeed  = errors.New("invalid server seed")
+	ErrCommitmentMismatch = errors.New("server seed does not match commitment")
+)
+
+const (
+	MaxClientSeedLength = 32 // Longest client seed a player may add
+	maxRevealedShoes    = 20 // Revealed shoes kept around for announcing
+	serverSeedLength    = 32 // Bytes of randomness in a server seed
+)
+
+// ClientSeeder is a shuffler that accepts client seeds from players and
+// commits to its shoes before they are dealt
+type ClientSeeder interface {
+	entities.Shuffler
+	AddClientSeed(playerID, seed string) error
+	ClientSeedsWaiting() bool
+	Committed(seed entities.Seed) bool
+}
+
+// ClientSeed is a seed a player added to a shoe
+type ClientSeed struct {
+	PlayerID string
+	Seed     string
+}
+
+// FairShoe is the commitment for one shoe and, once the shoe is retired, the
+// server seed that lets anyone re-derive its card order
+type FairShoe struct {
+	Commitment  string       // SHA-256 of the server seed, posted before the shoe is dealt
+	ServerSeed  string       // Hex server seed, only set once the shoe is retired
+	ClientSeeds []ClientSeed // Seeds players added before the shoe was shuffled
+	Decks       int          // Number of decks in the shoe
+
+	serverSeed []byte
+	seed       entities.Seed
+}
+
+// ClientSeedValues returns the client seeds in the order they were mixed in
+func (s FairShoe) ClientSeedValues() []string {
+	values := make([]string, 0, len(s.ClientSeeds))
+	for _, clientSeed := range s.ClientSeeds {
+		values = append(values, clientSeed.Seed)
+	}
+	return values
+}
+
+// FairShuffler is a provably fair shuffler for one channel's shoes. The next
+// shoe's server seed is committed to ahead of time, players mix in their own
+// seeds while betting, and the server seed is revealed when the shoe is retired.
+type FairShuffler struct {
+	mu       sync.Mutex
+	next     *FairShoe  // Committed, not shuffled yet, still taking client seeds
+	current  *FairShoe  // Being dealt, server seed still secret
+	revealed []FairShoe // Retired shoes waiting to be announced
+}
+
+// NewFairShuffler creates a provably fair shuffler with its first commitment ready
+func NewFairShuffler() *FairShuffler {
+	return &FairShuffler{next: newFairShoe()}
+}
+
+// newFairShoe draws a new server seed and commits to it
+func newFairShoe() *FairShoe {
+	serverSeed := make([]byte, serverSeedLength)
+	if _, err := crand.Read(serverSeed); err != nil {
+		panic(fmt.Sprintf("blackjack: crypto/rand unavailable: %v", err))
+	}
+	return &FairShoe{
+		Commitment: CommitServerSeed(serverSeed),
+		serverSeed: serverSeed,
+	}
+}
+
+// NextCommitment returns the commitment for the next shoe to be shuffled
+func (f *FairShuffler) NextCommitment() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.next.Commitment
+}
+
+// CurrentCommitment returns the commitment for the shoe being dealt, if any
+func (f *FairShuffler) CurrentCommitment() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.current == nil {
+		return ""
+	}
+	return f.current.Commitment
+}
+
+// AddClientSeed mixes a player's seed into the next shoe. A player adding a
+// second seed replaces their first one.
+func (f *FairShuffler) AddClientSeed(playerID, seed string) error {
+	seed = strings.TrimSpace(seed)
+	if err := validateClientSeed(seed); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, clientSeed := range f.next.ClientSeeds {
+		if clientSeed.PlayerID == playerID {
+			f.next.ClientSeeds[i].Seed = seed
+			return nil
+		}
+	}
+	f.next.ClientSeeds = append(f.next.ClientSeeds, ClientSeed{PlayerID: playerID, Seed: seed})
+	return nil
+}
+
+// ClientSeedsWaiting returns true if players added seeds to the next shoe,
+// which is then brought out at the next deal rather than when the cut card
+// comes out
+func (f *FairShuffler) ClientSeedsWaiting() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.next.ClientSeeds) > 0
+}
+
+// validateClientSeed checks a client seed can be written down and typed back into /verify
+func validateClientSeed(seed string) error {
+	if seed == "" || len(seed) > MaxClientSeedLength {
+		return fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidClientSeed, MaxClientSeedLength)
+	}
+	for _, r := range seed {
+		if r == ',' || !unicode.IsPrint(r) {
+			return fmt.Errorf("%w: commas and control characters aren't allowed", ErrInvalidClientSeed)
+		}
+	}
+	return nil
+}
+
+// Shuffle retires the current shoe, revealing its server seed, and shuffles a
+// new shoe from the committed server seed and the players' client seeds
+func (f *FairShuffler) Shuffle(cards []*entities.Card) entities.Seed {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.retireCurrent()
+
+	shoe := f.next
+	shoe.Decks = len(cards) / 52
+	shoe.seed = DeriveShoeSeed(shoe.serverSeed, shoe.ClientSeedValues())
+	entities.ShuffleWithSeed(cards, shoe.seed)
+
+	f.current = shoe
+	f.next = newFairShoe()
+	return shoe.seed
+}
+
+// Committed returns true if the shoe with the given seed is the one being dealt
+// under a commitment
+func (f *FairShuffler) Committed(seed entities.Seed) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.current != nil && f.current.seed == seed
+}
+
+// retireCurrent moves the current shoe to the revealed list, callers hold f.mu
+func (f *FairShuffler) retireCurrent() {
+	if f.current == nil {
+		return
+	}
+
+	shoe := *f.current
+	shoe.ServerSeed = hex.EncodeToString(shoe.serverSeed)
+	f.revealed = append(f.revealed, shoe)
+	if len(f.revealed) > maxRevealedShoes {
+		f.revealed = f.revealed[len(f.revealed)-maxRevealedShoes:]
+	}
+	f.current = nil
+}
+
+// savedFairShoe is a shoe as it's saved with the table, secret server seed and all
+type savedFairShoe struct {
+	Commitment  string       `json:"commitment"`
+	ServerSeed  string       `json:"server_seed"`
+	ClientSeeds []ClientSeed `json:"client_seeds,omitempty"`
+	Decks       int          `json:"decks,omitempty"`
+}
+
+// savedFairShuffler is a shuffler's shoes as they're saved with the table
+type savedFairShuffler struct {
+	Next     savedFairShoe  `json:"next"`
+	Current  *savedFairShoe `json:"current,omitempty"`
+	Revealed []FairShoe     `json:"revealed,omitempty"`
+}
+
+func saveFairShoe(shoe *FairShoe) savedFairShoe {
+	return savedFairShoe{
+		Commitment:  shoe.Commitment,
+		ServerSeed:  hex.EncodeToString(shoe.serverSeed),
+		ClientSeeds: shoe.ClientSeeds,
+		Decks:       shoe.Decks,
+	}
+}
+
+// restore checks the saved server seed against its commitment and rebuilds the shoe
+func (s savedFairShoe) restore() (*FairShoe, error) {
+	serverSeed, err := hex.DecodeString(s.ServerSeed)
+	if err != nil || len(serverSeed) == 0 {
+		return nil, fmt.Errorf("%w: expected hex", ErrInvalidServerSeed)
+	}
+	if CommitServerSeed(serverSeed) != s.Commitment {
+		return nil, ErrCommitmentMismatch
+	}
+	return &FairShoe{
+		Commitment:  s.Commitment,
+		ClientSeeds: s.ClientSeeds,
+		Decks:       s.Decks,
+		serverSeed:  serverSeed,
+	}, nil
+}
+
+// MarshalJSON saves the shuffler's shoes, server seeds included, so a restart
+// keeps dealing the committed shoe and still reveals it once it's retired
+func (f *FairShuffler) MarshalJSON() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	saved := savedFairShuffler{Next: saveFairShoe(f.next), Revealed: f.revealed}
+	if f.current != nil {
+		current := saveFairShoe(f.current)
+		saved.Current = &current
+	}
+	return json.Marshal(saved)
+}
+
+// RestoreFairShuffler brings back a shuffler saved with MarshalJSON. Every
+// server seed has to match the commitment that was posted for it.
+func RestoreFairShuffler(data []byte) (*FairShuffler, error) {
+	var saved savedFairShuffler
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+
+	next, err := saved.Next.restore()
+	if err != nil {
+		return nil, err
+	}
+	f := &FairShuffler{next: next, revealed: saved.Revealed}
+	if saved.Current != nil {
+		if f.current, err = saved.Current.restore(); err != nil {
+			return nil, err
+		}
+		f.current.seed = DeriveShoeSeed(f.current.serverSeed, f.current.ClientSeedValues())
+	}
+	return f, nil
+}
+
+// TakeRevealed returns the shoes revealed since the last call
+func (f *FairShuffler) TakeRevealed() []FairShoe {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	revealed := f.revealed
+	f.revealed = nil
+	return revealed
+}
+
+// CommitServerSeed returns the commitment posted for a server seed
+func CommitServerSeed(serverSeed []byte) string {
+	sum := sha256.Sum256(serverSeed)
+	return hex.EncodeToString(sum[:])
+}
+
+// DeriveShoeSeed combines the server seed with the client seeds into the seed
+// the shoe is shuffled with
+func DeriveShoeSeed(serverSeed []byte, clientSeeds []string) entities.Seed {
+	mac := hmac.New(sha256.New, serverSeed)
+	mac.Write([]byte(strings.Join(clientSeeds, ",")))
+
+	var seed entities.Seed
+	copy(seed[:], mac.Sum(nil))
+	return seed
+}
+
+// VerifyShoe checks a revealed server seed against its commitment and rebuilds
+// the shoe, so the card order can be compared with what was dealt
+func VerifyShoe(serverSeedHex, commitment string, clientSeeds []string, decks int) (*entities.Deck, error) {
+	serverSeed, err := hex.DecodeString(strings.TrimSpace(serverSeedHex))
+	if err != nil || len(serverSeed) == 0 {
+		return nil, fmt.Errorf("%w: expected hex", ErrInvalidServerSeed)
+	}
+
+	if !strings.EqualFold(CommitServerSeed(serverSeed), strings.TrimSpace(commitment)) {
+		return nil, ErrCommitmentMismatch
+	}
+
+	return ReplayShoe(decks, DeriveShoeSeed(serverSeed, clientSeeds), 0), nil
+}
@@ -0,0 +1,159 @@
+package blackjack
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFairShoeVerifiesAfterReveal(t *testing.T) {
+	fair := NewFairShuffler()
+	commitment := fair.NextCommitment()
+	require.NoError(t, fair.AddClientSeed("player1", "  tuco  "))
+	require.NoError(t, fair.AddClientSeed("player2", "blondie"))
+	require.NoError(t, fair.AddClientSeed("player1", "angel eyes"), "a second seed replaces the first")
+
+	shoe := NewBlackjackDeck(StandardDecks, fair)
+	assert.True(t, fair.Committed(shoe.Seed))
+	assert.Equal(t, commitment, fair.CurrentCommitment())
+	assert.Empty(t, fair.TakeRevealed(), "the shoe in play stays secret")
+
+	// The next shuffle retires the shoe and reveals its server seed
+	NewBlackjackDeck(StandardDecks, fair)
+	assert.False(t, fair.Committed(shoe.Seed))
+
+	revealed := fair.TakeRevealed()
+	require.Len(t, revealed, 1)
+	assert.Equal(t, commitment, revealed[0].Commitment)
+	assert.Equal(t, StandardDecks, revealed[0].Decks)
+	assert.Equal(t, []string{"angel eyes", "blondie"}, revealed[0].ClientSeedValues())
+
+	verified, err := VerifyShoe(revealed[0].ServerSeed, revealed[0].Commitment, revealed[0].ClientSeedValues(), revealed[0].Decks)
+	require.NoError(t, err)
+	assert.Equal(t, shoe.Seed, verified.Seed)
+	assert.Equal(t, shoe.Cards, verified.Cards)
+
+	// Different client seeds give a different shoe
+	other, err := VerifyShoe(revealed[0].ServerSeed, revealed[0].Commitment, []string{"blondie"}, revealed[0].Decks)
+	require.NoError(t, err)
+	assert.NotEqual(t, shoe.Cards, other.Cards)
+}
+
+func TestVerifyShoeRejectsWrongServerSeed(t *testing.T) {
+	serverSeed := []byte("a server seed nobody committed to")
+	commitment := CommitServerSeed([]byte("the committed server seed"))
+
+	_, err := VerifyShoe(hex.EncodeToString(serverSeed), commitment, nil, StandardDecks)
+	assert.ErrorIs(t, err, ErrCommitmentMismatch)
+
+	_, err = VerifyShoe("not hex", commitment, nil, StandardDecks)
+	assert.ErrorIs(t, err, ErrInvalidServerSeed)
+}
+
+func TestInvalidClientSeeds(t *testing.T) {
+	fair := NewFairShuffler()
+	for _, seed := range []string{"", "   ", "one,two", "tab\there", "this client seed is far too long to keep"} {
+		assert.ErrorIs(t, fair.AddClientSeed("player1", seed), ErrInvalidClientSeed, "seed %q", seed)
+	}
+}
+
+func TestFairTableReplacesUncommittedShoe(t *testing.T) {
+	repo := game.NewMemoryRepository()
+
+	// A shoe left behind by an ordinary table, e.g. from before a restart
+	first := dealRound(t, repo, entities.NewSeededShuffler(entities.Seed{1}))
+
+	fair := NewFairShuffler()
+	commitment := fair.NextCommitment()
+	second := dealRound(t, repo, fair)
+	assert.NotEqual(t, first.ShoeSeed, second.ShoeSeed)
+	assert.Equal(t, 1, second.ShoePosition)
+	assert.Equal(t, commitment, fair.CurrentCommitment())
+	assert.True(t, fair.Committed(second.ShoeSeed))
+
+	// The committed shoe keeps being dealt
+	third := dealRound(t, repo, fair)
+	assert.Equal(t, second.ShoeSeed, third.ShoeSeed)
+	assert.Equal(t, 7, third.ShoePosition)
+}
+
+func TestAddClientSeedOnlyWhileBetting(t *testing.T) {
+	g := NewGame("test-channel", game.NewMemoryRepository(), DefaultRuleSet(), NewFairShuffler())
+	require.NoError(t, g.AddPlayer("player1"))
+	assert.ErrorIs(t, g.AddClientSeed("player1", "early"), ErrInvalidAction)
+
+	require.NoError(t, g.Start())
+	assert.NoError(t, g.AddClientSeed("player1", "just right"))
+	assert.ErrorIs(t, g.AddClientSeed("stranger", "sneaky"), ErrPlayerNotFound)
+
+	require.NoError(t, g.PlaceBet("player1", 10))
+	assert.ErrorIs(t, g.AddClientSeed("player1", "late"), ErrInvalidAction)
+
+	plain := NewGame("test-channel", game.NewMemoryRepository(), DefaultRuleSet(), nil)
+	require.NoError(t, plain.AddPlayer("player1"))
+	require.NoError(t, plain.Start())
+	assert.ErrorIs(t, plain.AddClientSeed("player1", "seed"), ErrNotProvablyFair)
+}
+
+func TestClientSeedBringsOutTheSeededShoe(t *testing.T) {
+	repo := game.NewMemoryRepository()
+	fair := NewFairShuffler()
+	first := dealRound(t, repo, fair)
+	commitment := fair.NextCommitment()
+
+	// A seed added while betting goes into the shoe this round is dealt from
+	g := NewGame("test-channel", repo, DefaultRuleSet(), fair)
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.Start())
+	require.NoError(t, g.AddClientSeed("player1", "blondie"))
+	assert.True(t, fair.ClientSeedsWaiting())
+	require.NoError(t, g.PlaceBet("player1", 10))
+
+	assert.NotEqual(t, first.ShoeSeed, g.ShoeSeed)
+	assert.Equal(t, 1, g.ShoePosition)
+	assert.Equal(t, commitment, fair.CurrentCommitment())
+	assert.False(t, fair.ClientSeedsWaiting())
+	require.Len(t, fair.TakeRevealed(), 1, "the shoe it replaced is revealed")
+}
+
+func TestFairShufflerSurvivesRestart(t *testing.T) {
+	fair := NewFairShuffler()
+	require.NoError(t, fair.AddClientSeed("player1", "tuco"))
+	shoe := NewBlackjackDeck(StandardDecks, fair)
+	require.NoError(t, fair.AddClientSeed("player2", "blondie"))
+
+	data, err := json.Marshal(fair)
+	require.NoError(t, err)
+	restored, err := RestoreFairShuffler(data)
+	require.NoError(t, err)
+
+	// The shoe being dealt is still committed to, and the next one is still taking seeds
+	assert.True(t, restored.Committed(shoe.Seed))
+	assert.Equal(t, fair.CurrentCommitment(), restored.CurrentCommitment())
+	assert.Equal(t, fair.NextCommitment(), restored.NextCommitment())
+	assert.True(t, restored.ClientSeedsWaiting())
+
+	// Retiring it after the restart reveals the server seed that was committed
+	// to, and a reveal that wasn't announced yet survives the next restart too
+	NewBlackjackDeck(StandardDecks, restored)
+	data, err = json.Marshal(restored)
+	require.NoError(t, err)
+	restored, err = RestoreFairShuffler(data)
+	require.NoError(t, err)
+	revealed := restored.TakeRevealed()
+	require.Len(t, revealed, 1)
+	verified, err := VerifyShoe(revealed[0].ServerSeed, revealed[0].Commitment, revealed[0].ClientSeedValues(), revealed[0].Decks)
+	require.NoError(t, err)
+	assert.Equal(t, shoe.Cards, verified.Cards)
+
+	// A saved server seed has to match its commitment
+	tampered := bytes.Replace(data, []byte(restored.CurrentCommitment()), []byte(CommitServerSeed([]byte("another seed"))), 1)
+	_, err = RestoreFairShuffler(tampered)
+	assert.ErrorIs(t, err, ErrCommitmentMismatch)
+}
@@ -5,11 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/fadedpez/tucoramirez/pkg/entities"
 	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/google/uuid"
 )
 
 // Game-specific errors
@@ -31,6 +32,7 @@ var (
 	ErrNotEligibleForDoubleDown   = errors.New("not eligible for double down")
 	ErrNotEligibleForSplit        = errors.New("not eligible for split")
 	ErrNotEligibleForInsurance    = errors.New("not eligible for insurance")
+	ErrNotEligibleForSurrender    = errors.New("not eligible for surrender")
 	ErrInsufficientFundsForAction = errors.New("insufficient funds for this action")
 	ErrNotAllPlayersHaveBet       = errors.New("not all players have placed bets")
 )
@@ -39,6 +41,7 @@ var (
 const (
 	StateBlackjack entities.GameState = "BLACKJACK"
 	// Special betting states
+	StateInsurance   entities.GameState = "INSURANCE"
 	StateSplitting   entities.GameState = "SPLITTING"
 	StateSpecialBets entities.GameState = "SPECIAL_BETS"
 )
@@ -65,35 +68,73 @@ type Game struct {
 	ID        string
 	State     entities.GameState
 	Deck      *entities.Deck
-	Players   map[string]*Hand // PlayerID -> Hand
+	Players   map[string]*Hand // HandID -> Hand, a player's first seat is their own ID
 	Dealer    *Hand
 	shuffled  bool // Flag to track if the deck has been shuffled
 	ChannelID string
+	Rules     RuleSet // Table rules this game is played under
 	repo      game.Repository
+	shuffler  entities.Shuffler // Shuffles every new shoe
+
+	// Shoe tracking, enough to deal the round again from a saved record
+	ShoeSeed     entities.Seed // Seed of the shoe the round was dealt from
+	ShoePosition int           // Cards already drawn from the shoe when the round was dealt
+	NewShoeSeed  entities.Seed // Seed of the shoe brought out when the round ran the first one out, zero if it didn't
 
 	// Betting fields
-	Bets                 map[string]int64 // PlayerID -> Bet amount
-	CurrentBettingPlayer int              // Index into PlayerOrder for whose turn it is to bet
-	PayoutsProcessed     bool             // Flag to track if payouts have been processed
+	Bets                 map[string]int64            // HandID -> Bet amount
+	SideBets             map[string][]*PlacedSideBet // HandID -> Side bets in the order they were placed
+	BackBets             []*BackBet                  // Spectators' bets behind the seats, in the order they were placed
+	Paid                 map[string]int64            // PlayerID -> Paid out for their hands so far, so retried payouts aren't paid twice
+	Collections          map[string]int64            // PlayerID -> Taken from their winnings for an overdue loan
+	CurrentBettingPlayer int                         // Index into PlayerOrder for whose turn it is to bet
+	PayoutsProcessed     bool                        // Flag to track if payouts have been processed
+	registry             *SideBetRegistry            // Side bets the table offers
 
 	// Turn tracking
-	PlayerOrder []string // Ordered list of player IDs
+	PlayerOrder []string // Ordered list of hand IDs, each player's seats and split hands side by side
 	CurrentTurn int      // Index into PlayerOrder
 
 	// Special bets tracking
 	CurrentSpecialBetsTurn int // Index into PlayerOrder for special bets
+
+	// Peek tracking
+	DealerPeeked bool // Whether the dealer has checked the hole card for blackjack
+
+	// Event log, every state change in the round in order
+	Events        []Event
+	replaying     bool     // Set while the round is rebuilt from its events
+	replayHandIDs []string // Hand IDs of the splits being played back
+
+	// Turn timer, the default is played for a player who lets it run out
+	Timers       TurnTimers         // How long each phase's turns last, no limits by default
+	TurnDeadline time.Time          // When the current turn times out, zero if it doesn't
+	turnHandID   string             // Hand the turn clock was started for
+	turnEvents   int                // Events logged when the turn clock was started
+	turnState    entities.GameState // Phase the turn clock was started in
+	turnStarted  time.Time          // When the turn clock was started
 }
 
 const StandardLoanAmount = 100
 
-func NewGame(channelID string, repo game.Repository) *Game {
+// NewGame creates a new game for a channel played under the given rules. A nil
+// shuffler uses a cryptographically secure one.
+func NewGame(channelID string, repo game.Repository, rules RuleSet, shuffler entities.Shuffler) *Game {
+	if shuffler == nil {
+		shuffler = entities.NewCryptoShuffler()
+	}
+
 	return &Game{
 		State:     entities.StateWaiting,
 		Players:   make(map[string]*Hand),
 		Dealer:    NewHand(),
 		ChannelID: channelID,
+		Rules:     rules,
 		repo:      repo,
+		shuffler:  shuffler,
 		Bets:      make(map[string]int64),
+		SideBets:  make(map[string][]*PlacedSideBet),
+		registry:  DefaultSideBetRegistry(),
 		Deck:      entities.NewDeck(), // Initialize with a new deck to avoid nil pointer issues
 	}
 }
@@ -105,7 +146,7 @@ func (g *Game) AddPlayer(playerID string) error {
 	}
 
 	// Check if maximum players limit has been reached
-	if len(g.Players) >= 7 {
+	if len(g.Players) >= g.Rules.MaxPlayers {
 		return ErrMaxPlayersReached
 	}
 
@@ -125,16 +166,21 @@ func (g *Game) Start() error {
 
 	// If we're in WAITING state and not in BETTING, transition to BETTING first
 	if g.State == entities.StateWaiting {
-		// Set up player order for betting
-		g.PlayerOrder = make([]string, 0, len(g.Players))
-		for playerID := range g.Players {
-			g.PlayerOrder = append(g.PlayerOrder, playerID)
+		// Set up player order for betting, unless the seats were already given
+		if len(g.PlayerOrder) != len(g.Players) {
+			g.PlayerOrder = g.seatOrder()
 		}
 
 		// Initialize betting turn to the first player
 		g.CurrentBettingPlayer = 0
 
+		// Every round gets its own ID for the event log and records
+		if g.ID == "" {
+			g.ID = uuid.New().String()
+		}
+
 		g.State = entities.StateBetting
+		g.emit(Event{Type: EventRoundStarted, Players: append([]string(nil), g.PlayerOrder...)})
 		return nil
 	}
 
@@ -155,75 +201,194 @@ func (g *Game) Start() error {
 		return ErrFailedToLoadDeck
 	}
 
-	// Create a new deck if none exists
+	// Create a new shoe if none exists, otherwise keep dealing the saved one
 	if deck == nil {
-		log.Printf("No existing deck found, creating new deck for channel %s", g.ChannelID)
-		g.Deck = entities.NewDeck()
-		g.Deck.Shuffle()
-
-		// Save the new deck to the repository
-		log.Printf("Saving new deck to repository for channel %s", g.ChannelID)
-		if err := g.repo.SaveDeck(context.Background(), g.ChannelID, g.Deck.Cards); err != nil {
-			log.Printf("Error saving deck: %v", err)
-			return ErrFailedToSaveDeck
-		}
+		log.Printf("No existing deck found, creating new shoe for channel %s", g.ChannelID)
+		g.reshuffle()
 	} else {
-		log.Printf("Using existing deck with %d cards for channel %s", len(deck), g.ChannelID)
-		g.Deck = &entities.Deck{Cards: deck}
+		log.Printf("Using existing deck with %d cards for channel %s", len(deck.Cards), g.ChannelID)
+		g.Deck = deck
 	}
+	g.prepareShoe()
 
-	// Only create a new deck if we don't have one or we need to reshuffle
-	if g.Deck == nil || ShouldReshuffle(g.Deck) {
-		g.Deck = NewBlackjackDeck()
-		g.shuffled = true
+	// Set up player order, keeping the betting order so the deal is repeatable
+	if len(g.PlayerOrder) != len(g.Players) {
+		g.PlayerOrder = g.seatOrder()
 	}
+	g.CurrentTurn = 0
+
+	// Record where in the shoe this round starts
+	g.ShoeSeed = g.Deck.Seed
+	g.ShoePosition = g.Deck.Dealt
+	g.NewShoeSeed = entities.Seed{}
 
 	// Deal initial cards
 	for i := 0; i < 2; i++ {
-		// Deal to each player
-		for _, hand := range g.Players {
-			if card := g.Deck.Draw(); card != nil {
-				hand.AddCard(card)
-			}
+		// Deal to each player in seat order
+		for _, playerID := range g.PlayerOrder {
+			g.dealCard(playerID, g.Players[playerID])
 		}
-		// Deal to dealer
-		if card := g.Deck.Draw(); card != nil {
-			g.Dealer.AddCard(card)
+		// Deal to dealer, who only takes one card up front at a no-hole-card table
+		if i == 1 && g.Rules.NoHoleCard {
+			continue
 		}
+		g.dealToDealer(EventCardDealt)
 	}
 
-	// Save the deck state after dealing
-	if err := g.repo.SaveDeck(context.Background(), g.ChannelID, g.Deck.Cards); err != nil {
-		return ErrFailedToSaveDeck
+	// Save the deck state after dealing, this also records a new shoe
+	if err := g.saveDeck(); err != nil {
+		return err
 	}
 
-	// Set up player order
-	g.PlayerOrder = make([]string, 0, len(g.Players))
-	for playerID := range g.Players {
-		g.PlayerOrder = append(g.PlayerOrder, playerID)
+	// Side bets are judged on the deal
+	g.settleSideBets()
+
+	return g.beginSpecialPhases()
+}
+
+// shoeCommitted returns false if the table is provably fair but the current shoe
+// wasn't shuffled under a commitment, e.g. a shoe saved before a restart
+func (g *Game) shoeCommitted() bool {
+	seeder, ok := g.shuffler.(ClientSeeder)
+	if !ok {
+		return true
 	}
-	g.CurrentTurn = 0
+	return seeder.Committed(g.Deck.Seed)
+}
 
-	// Check for special betting options
+// clientSeedsWaiting returns true if players seeded the next shoe at a provably fair table
+func (g *Game) clientSeedsWaiting() bool {
+	seeder, ok := g.shuffler.(ClientSeeder)
+	return ok && seeder.ClientSeedsWaiting()
+}
+
+// AddClientSeed mixes a player's seed into the next shoe at a provably fair table.
+// Client seeds are only taken while the table is betting, and the seeded shoe
+// is the one the round is dealt from.
+func (g *Game) AddClientSeed(playerID, seed string) error {
+	if g.State != entities.StateBetting {
+		return ErrInvalidAction
+	}
+	if _, exists := g.Players[playerID]; !exists {
+		return ErrPlayerNotFound
+	}
+
+	seeder, ok := g.shuffler.(ClientSeeder)
+	if !ok {
+		return ErrNotProvablyFair
+	}
+	return seeder.AddClientSeed(playerID, seed)
+}
+
+// beginSpecialPhases moves a freshly dealt game into the insurance phase when the
+// dealer is about to peek, otherwise straight on to the players' decisions
+func (g *Game) beginSpecialPhases() error {
+	if g.ShouldPeek() {
+		if g.AnyPlayerEligibleForInsurancePhase() {
+			// Insurance and early surrender have to be decided before the peek
+			log.Printf("Transitioning to insurance state before the dealer peeks")
+			g.State = StateInsurance
+			g.CurrentSpecialBetsTurn = -1
+			return g.AdvanceSpecialBetsTurn()
+		}
+		return g.peekForBlackjack()
+	}
+
+	return g.beginPlayerDecisions()
+}
+
+// ShouldPeek returns true if the dealer still has to check the hole card for
+// blackjack, which happens when the up-card is an Ace or a ten-value card
+func (g *Game) ShouldPeek() bool {
+	if !g.Rules.DealerPeeks || g.Rules.NoHoleCard || g.DealerPeeked || len(g.Dealer.Cards) < 2 {
+		return false
+	}
+
+	upCard := g.Dealer.Cards[0]
+	return IsAce(upCard) || GetCardValue(upCard) == 10
+}
+
+// peekForBlackjack has the dealer check the hole card. A dealer blackjack ends
+// the round on the spot, so only the original bets are lost and nobody gets
+// the chance to double or split into it.
+func (g *Game) peekForBlackjack() error {
+	g.DealerPeeked = true
+	g.emit(Event{Type: EventDealerPeeked, HandID: DealerHandID, Blackjack: IsBlackjack(g.Dealer.Cards)})
+
+	// Insurance is judged on the hole card
+	g.settleSideBets()
+
+	if !IsBlackjack(g.Dealer.Cards) {
+		log.Printf("Dealer peeked and does not have blackjack")
+		return g.beginPlayerDecisions()
+	}
+
+	log.Printf("Dealer peeked and has blackjack, ending the round")
+	for _, hand := range g.Players {
+		if hand.Status == StatusPlaying {
+			hand.Stand()
+		}
+	}
+	g.State = entities.StateComplete
+	return nil
+}
+
+// beginPlayerDecisions moves the game into splitting, special bets or straight
+// to playing, depending on what the players are eligible for
+func (g *Game) beginPlayerDecisions() error {
 	if g.AnyPlayerEligibleForSplit() {
 		// Transition to splitting state
 		log.Printf("Transitioning to splitting state as players are eligible for split")
-		g.State = StateSplitting
-		g.CurrentSpecialBetsTurn = 0
-	} else if g.AnyPlayerEligibleForSpecialBets() {
+		return g.beginSplitting()
+	}
+	if g.AnyPlayerEligibleForSpecialBets() {
 		// Transition to special bets state
 		log.Printf("Transitioning to special bets state as players are eligible for special bets")
-		g.State = StateSpecialBets
-		g.CurrentSpecialBetsTurn = 0
-	} else {
-		// No special bets available, go directly to playing state
-		log.Printf("No special betting options available, transitioning directly to playing state")
-		g.State = entities.StatePlaying
+		return g.beginSpecialBets()
 	}
 
+	// No special bets available, go directly to playing state
+	log.Printf("No special betting options available, transitioning directly to playing state")
+	g.beginPlayerTurns()
 	return nil
 }
 
+// beginSplitting enters the splitting phase on the first hand that can split
+func (g *Game) beginSplitting() error {
+	g.State = StateSplitting
+	g.CurrentSpecialBetsTurn = 0
+	if len(g.PlayerOrder) > 0 && !g.IsEligibleForSplit(g.PlayerOrder[0]) {
+		return g.AdvanceSplittingTurn()
+	}
+	return nil
+}
+
+// beginSpecialBets enters the special bets phase on the first hand with options
+func (g *Game) beginSpecialBets() error {
+	g.State = StateSpecialBets
+	g.CurrentSpecialBetsTurn = -1
+	return g.AdvanceSpecialBetsTurn()
+}
+
+// beginPlayerTurns enters the playing phase on the first hand still in play
+func (g *Game) beginPlayerTurns() {
+	g.State = entities.StatePlaying
+	g.CurrentTurn = 0
+
+	// Skip hands that are already done (bust or stand)
+	// This ensures players who doubled down and automatically stood are skipped
+	for len(g.PlayerOrder) > 0 && g.CheckPlayerDone(g.PlayerOrder[g.CurrentTurn]) && !g.CheckAllPlayersDone() {
+		log.Printf("Skipping hand %s which is already done", g.PlayerOrder[g.CurrentTurn])
+		g.CurrentTurn = (g.CurrentTurn + 1) % len(g.PlayerOrder)
+	}
+
+	// If all players are done after skipping, transition to dealer phase
+	if g.CheckAllPlayersDone() {
+		log.Printf("All players are done, transitioning to dealer phase")
+		g.State = entities.StateDealer
+	}
+}
+
 // StartBetting transitions the game to the betting phase
 func (g *Game) StartBetting() error {
 	if g.State != entities.StateWaiting {
@@ -231,10 +396,7 @@ func (g *Game) StartBetting() error {
 	}
 
 	// Set up player order for betting
-	g.PlayerOrder = make([]string, 0, len(g.Players))
-	for playerID := range g.Players {
-		g.PlayerOrder = append(g.PlayerOrder, playerID)
-	}
+	g.PlayerOrder = g.seatOrder()
 
 	// Initialize betting turn to the first player
 	g.CurrentBettingPlayer = 0
@@ -243,42 +405,44 @@ func (g *Game) StartBetting() error {
 	return nil
 }
 
-// ValidateBet checks if a player can place a bet
+// ValidateBet checks if a player can place a bet on the seat whose turn it is to bet
 func (g *Game) ValidateBet(playerID string) error {
 	// Check if game is in betting state
 	if g.State != entities.StateBetting {
 		return ErrInvalidAction
 	}
 
-	// Check if player is in the game
-	if _, exists := g.Players[playerID]; !exists {
-		return ErrPlayerNotFound
-	}
-
-	// Check if it's this player's turn to bet
-	if len(g.PlayerOrder) > 0 && g.CurrentBettingPlayer < len(g.PlayerOrder) {
-		currentPlayerID := g.PlayerOrder[g.CurrentBettingPlayer]
-		if currentPlayerID != playerID {
-			return ErrNotPlayerTurn
-		}
+	// Check if player is in the game and it's one of their seats' turn to bet
+	seatID, err := g.BettingSeat(playerID)
+	if err != nil {
+		return err
 	}
 
-	// Check if player has already bet
-	if _, hasBet := g.Bets[playerID]; hasBet {
+	// Check if the seat has already been bet on
+	if _, hasBet := g.Bets[seatID]; hasBet {
 		return ErrPlayerAlreadyBet
 	}
 
 	return nil
 }
 
-// PlaceBet places a bet for a player
+// PlaceBet places a player's bet within the table's limits on the seat whose
+// turn it is to bet
 func (g *Game) PlaceBet(playerID string, amount int64) error {
 	if err := g.ValidateBet(playerID); err != nil {
 		return err
 	}
+	seatID, _ := g.BettingSeat(playerID)
+	// A round being played back took its bets under the limits of the day
+	if !g.replaying {
+		if err := g.Rules.CheckBet(amount); err != nil {
+			return err
+		}
+	}
 
 	// Store bet amount
-	g.Bets[playerID] = amount
+	g.Bets[seatID] = amount
+	g.emit(Event{Type: EventBetPlaced, PlayerID: g.HandOwner(seatID), HandID: seatID, Amount: amount})
 
 	// Move to next player's turn
 	g.CurrentBettingPlayer++
@@ -305,8 +469,15 @@ func (g *Game) PlaceBet(playerID string, amount int64) error {
 // PlaceBetWithWalletUpdate places a bet for a player and updates their wallet
 // Returns whether a loan was given and any error
 func (g *Game) PlaceBetWithWalletUpdate(ctx context.Context, playerID string, betAmount int64, walletService WalletService) (bool, error) {
+	// The bet goes on the seat whose turn it is, the money comes out of the player's wallet
+	seatID, err := g.BettingSeat(playerID)
+	if err != nil {
+		return false, err
+	}
+	playerID = g.HandOwner(seatID)
+
 	// First validate the bet using the existing PlaceBet method
-	err := g.PlaceBet(playerID, betAmount)
+	err = g.PlaceBet(playerID, betAmount)
 	if err != nil {
 		return false, err
 	}
@@ -323,45 +494,43 @@ func (g *Game) PlaceBetWithWalletUpdate(ctx context.Context, playerID string, be
 	)
 	if err != nil {
 		// Revert the bet since the wallet update failed
-		delete(g.Bets, playerID)
-		// Move back to previous player's turn
-		if g.CurrentBettingPlayer > 0 {
-			g.CurrentBettingPlayer--
-		}
+		g.revertBet(seatID)
 		return false, fmt.Errorf("error ensuring funds: %w", err)
 	}
 
 	// Check if player has enough funds after potential loan
 	if wallet.Balance < betAmount {
 		// Revert the bet since player still doesn't have enough funds
-		delete(g.Bets, playerID)
-		// Move back to previous player's turn
-		if g.CurrentBettingPlayer > 0 {
-			g.CurrentBettingPlayer--
-		}
+		g.revertBet(seatID)
 		return loanGiven, fmt.Errorf("insufficient funds even after loan")
 	}
 
-	// Deduct from wallet
-	err = walletService.RemoveFunds(
-		ctx,
-		playerID,
-		betAmount,
-		"Blackjack bet",
-	)
+	// Hold the bet until the round is paid
+	err = g.stake(ctx, walletService, playerID, seatID, betAmount, entities.TransactionTypeBet, "Blackjack bet")
 	if err != nil {
 		// Revert the bet since the wallet update failed
-		delete(g.Bets, playerID)
-		// Move back to previous player's turn
-		if g.CurrentBettingPlayer > 0 {
-			g.CurrentBettingPlayer--
-		}
+		g.revertBet(seatID)
 		return loanGiven, fmt.Errorf("error updating wallet: %w", err)
 	}
 
+	// The last bet deals the round, pay any side bets judged on the deal
+	if err := g.PaySideBets(ctx, walletService); err != nil {
+		log.Printf("Error paying side bets, they'll be paid with the round: %v", err)
+	}
+
 	return loanGiven, nil
 }
 
+// revertBet takes back the bet on a seat and gives it the betting turn again
+func (g *Game) revertBet(seatID string) {
+	delete(g.Bets, seatID)
+	// Move back to previous player's turn
+	if g.CurrentBettingPlayer > 0 {
+		g.CurrentBettingPlayer--
+	}
+	g.emit(Event{Type: EventBetCancelled, PlayerID: g.HandOwner(seatID), HandID: seatID})
+}
+
 // CheckAllBetsPlaced returns true if all players have placed bets
 func (g *Game) CheckAllBetsPlaced() bool {
 	for playerID := range g.Players {
@@ -393,79 +562,48 @@ func (g *Game) StartDealing() error {
 	}
 
 	// Safety check: ensure we have a valid deck before dealing
-	if g.Deck == nil {
-		log.Printf("Warning: Deck was nil in StartDealing, initializing new deck")
-		// Try to load existing deck from repository
-		if g.repo != nil {
-			deck, err := g.repo.GetDeck(context.Background(), g.ChannelID)
-			if err == nil && deck != nil {
-				log.Printf("Loaded existing deck with %d cards from repository", len(deck))
-				g.Deck = &entities.Deck{Cards: deck}
-			} else {
-				log.Printf("Could not load deck from repository, creating new deck")
-				g.Deck = entities.NewDeck()
-				g.Deck.Shuffle()
-				g.shuffled = true
-			}
-		} else {
-			log.Printf("Repository not available, creating new deck")
-			g.Deck = entities.NewDeck()
-			g.Deck.Shuffle()
-			g.shuffled = true
+	if g.Deck == nil && g.repo != nil {
+		log.Printf("Warning: Deck was nil in StartDealing, loading deck from repository")
+		deck, err := g.repo.GetDeck(context.Background(), g.ChannelID)
+		if err == nil && deck != nil {
+			log.Printf("Loaded existing deck with %d cards from repository", len(deck.Cards))
+			g.Deck = deck
 		}
 	}
+	g.prepareShoe()
+
+	// Record where in the shoe this round starts
+	g.ShoeSeed = g.Deck.Seed
+	g.ShoePosition = g.Deck.Dealt
+	g.NewShoeSeed = entities.Seed{}
 
 	// Deal two cards to each player
 	for _, playerID := range g.PlayerOrder {
 		hand := g.Players[playerID]
 		for i := 0; i < 2; i++ {
-			card := g.Deck.Draw()
-			if card == nil {
-				// Reshuffle if deck is empty
-				g.Deck = entities.NewDeck()
-				g.Deck.Shuffle()
-				g.shuffled = true
-				card = g.Deck.Draw()
-			}
-			hand.AddCard(card)
+			g.dealCard(playerID, hand)
 		}
 	}
 
-	// Deal two cards to the dealer
-	for i := 0; i < 2; i++ {
-		card := g.Deck.Draw()
-		if card == nil {
-			g.Deck = entities.NewDeck()
-			g.Deck.Shuffle()
-			g.shuffled = true
-			card = g.Deck.Draw()
-		}
-		g.Dealer.AddCard(card)
+	// Deal two cards to the dealer, or just the up-card at a no-hole-card table
+	dealerCards := 2
+	if g.Rules.NoHoleCard {
+		dealerCards = 1
 	}
-
-	// Save the deck state after dealing if repository is available
-	if g.repo != nil {
-		if err := g.repo.SaveDeck(context.Background(), g.ChannelID, g.Deck.Cards); err != nil {
-			log.Printf("Warning: Failed to save deck state: %v", err)
-		}
+	for i := 0; i < dealerCards; i++ {
+		g.dealToDealer(EventCardDealt)
 	}
 
-	// Check for special betting options
-	if g.AnyPlayerEligibleForSplit() {
-		// Transition to splitting state
-		g.State = StateSplitting
-		g.CurrentSpecialBetsTurn = 0
-	} else if g.AnyPlayerEligibleForSpecialBets() {
-		// Transition to special bets state
-		g.State = StateSpecialBets
-		g.CurrentSpecialBetsTurn = 0
-	} else {
-		// No special bets available, go directly to playing state
-		g.State = entities.StatePlaying
-		g.CurrentTurn = 0
+	// Save the deck state after dealing
+	if err := g.saveDeck(); err != nil {
+		log.Printf("Warning: Failed to save deck state: %v", err)
 	}
 
-	return nil
+	// Side bets are judged on the deal
+	g.settleSideBets()
+
+	// Check for special betting options
+	return g.beginSpecialPhases()
 }
 
 // StartPlaying transitions the game from dealing to playing phase
@@ -496,12 +634,8 @@ func (g *Game) Hit(playerID string) error {
 		return err
 	}
 
-	// Determine which hand we're working with (original or split)
+	// The current turn is for one of the player's hands, which may be a split hand
 	targetHandID := currentPlayerID
-	if strings.HasSuffix(currentPlayerID, "_split") && strings.TrimSuffix(currentPlayerID, "_split") == playerID {
-		// If the current turn is for a split hand and the player ID matches the original player
-		targetHandID = currentPlayerID
-	}
 
 	hand, exists := g.Players[targetHandID]
 	if !exists {
@@ -509,25 +643,18 @@ func (g *Game) Hit(playerID string) error {
 	}
 
 	// Draw and add card
-	card := g.Deck.Draw()
-	if card == nil {
-		// Only create a new deck if we're completely out of cards
-		g.Deck = NewBlackjackDeck()
-		g.shuffled = true
-		card = g.Deck.Draw()
-	}
-
-	if err := hand.AddCard(card); err != nil {
+	g.emit(Event{Type: EventHit, PlayerID: playerID, HandID: targetHandID})
+	if err := g.dealCard(targetHandID, hand); err != nil {
 		return err
 	}
 
 	// Check if player busted after adding the card
 	if hand.Status == StatusBust {
 		g.AdvanceTurn()
-		
+
 		// Log the transition for debugging
 		log.Printf("Player %s busted on hand %s, advancing to next turn", playerID, targetHandID)
-		
+
 		// Check if all players are done and transition to dealer state if needed
 		if g.CheckAllPlayersDone() {
 			log.Printf("All players are done after bust, transitioning to dealer's turn")
@@ -555,12 +682,8 @@ func (g *Game) Stand(playerID string) error {
 		return err
 	}
 
-	// Determine which hand we're working with (original or split)
+	// The current turn is for one of the player's hands, which may be a split hand
 	targetHandID := currentPlayerID
-	if strings.HasSuffix(currentPlayerID, "_split") && strings.TrimSuffix(currentPlayerID, "_split") == playerID {
-		// If the current turn is for a split hand and the player ID matches the original player
-		targetHandID = currentPlayerID
-	}
 
 	hand, exists := g.Players[targetHandID]
 	if !exists {
@@ -569,6 +692,8 @@ func (g *Game) Stand(playerID string) error {
 
 	err = hand.Stand()
 	if err == nil {
+		g.emit(Event{Type: EventStand, PlayerID: playerID, HandID: targetHandID})
+
 		// Advance to next player's turn
 		g.AdvanceTurn()
 
@@ -593,15 +718,9 @@ func (g *Game) PlayDealer() error {
 
 	g.State = entities.StateDealer
 
-	// Dealer must hit on 16 and below, stand on 17 and above
-	for GetBestScore(g.Dealer.Cards) < 17 {
-		card := g.Deck.Draw()
-		if card == nil {
-			g.Deck = NewBlackjackDeck()
-			g.shuffled = true
-			card = g.Deck.Draw()
-		}
-		err := g.Dealer.AddCard(card)
+	// Dealer draws according to the table's soft 17 rule
+	for g.Rules.DealerShouldHit(g.Dealer.Cards) {
+		err := g.dealToDealer(EventDealerDraw)
 		if err != nil {
 			return err // Any error here is a real error
 		}
@@ -614,6 +733,13 @@ func (g *Game) PlayDealer() error {
 
 	// Transition to complete state
 	g.State = entities.StateComplete
+	g.emit(Event{Type: EventDealerFinished, HandID: DealerHandID})
+	g.settleSideBets()
+
+	// The dealer draws last, so this records every card the round used
+	if err := g.saveDeck(); err != nil {
+		log.Printf("Warning: Failed to save deck state: %v", err)
+	}
 
 	return nil
 }
@@ -648,15 +774,48 @@ func (g *Game) ProcessPayouts(ctx context.Context, walletService WalletService)
 
 	log.Printf("[DEBUG] Game state: %s, Players: %d, Bets: %d", g.State, len(g.Players), len(g.Bets))
 
+	// The round's over, everything held for it goes to the table before anyone's paid
+	if err := g.captureRoundHolds(ctx, walletService); err != nil {
+		log.Printf("Error capturing the stakes held for round %s: %v", g.ID, err)
+		return err
+	}
+
 	// Get detailed results for game record
+	// Judge anything still waiting on the dealer's cards
+	g.settleSideBets()
+
 	handResults, err := g.GetResults()
 	if err != nil {
 		log.Printf("Error getting detailed results: %v", err)
 		return err
 	}
 
-	// Calculate payouts
+	// Settle any side bets that weren't paid when the cards were dealt. Until
+	// everyone's paid the round isn't finished, the stakes stay at the table and
+	// the payouts can be tried again
+	if err := g.PaySideBets(ctx, walletService); err != nil {
+		log.Printf("Error paying side bets: %v", err)
+		return err
+	}
+
+	// Back bets follow the seats they ride on
+	if err := g.PayBackBets(ctx, handResults, walletService); err != nil {
+		log.Printf("Error paying back bets: %v", err)
+		return err
+	}
+
+	// Calculate payouts, a push or a surrender gives stake back rather than paying winnings
 	payouts := g.CalculatePayouts()
+	pushes := make(map[string]int64)
+	surrenders := make(map[string]int64)
+	for _, result := range handResults {
+		switch result.Result {
+		case entities.StringResultPush:
+			pushes[result.PlayerID] += result.Payout
+		case entities.StringResultSurrender:
+			surrenders[result.PlayerID] += result.Payout
+		}
+	}
 
 	// Process each player's payout
 	for playerID, payout := range payouts {
@@ -667,22 +826,50 @@ func (g *Game) ProcessPayouts(ctx context.Context, walletService WalletService)
 		wallet, created, err := walletService.GetOrCreateWallet(ctx, playerID)
 		if err != nil {
 			log.Printf("Error getting wallet for player %s: %v", playerID, err)
-			continue
+			return err
 		}
 
 		log.Printf("Before payout: Player %s wallet balance: $%d (wallet was just created: %v)", playerID, wallet.Balance, created)
 
 		// Add winnings to wallet if there are any
 		if payout > 0 {
-			log.Printf("Adding $%d winnings to player %s wallet with description: Blackjack winnings", payout, playerID)
-			log.Printf("[DEBUG] Calling walletService.AddFunds for player %s, amount %d", playerID, payout)
-
-			err = walletService.AddFunds(ctx, playerID, payout, "Blackjack winnings")
-			if err != nil {
-				log.Printf("Error adding winnings to player %s wallet: %v", playerID, err)
+			credits := []struct {
+				amount          int64
+				transactionType entities.TransactionType
+				description     string
+			}{
+				{payout - pushes[playerID] - surrenders[playerID], entities.TransactionTypePayout, "Blackjack winnings"},
+				{pushes[playerID], entities.TransactionTypePushRefund, "Blackjack push"},
+				{surrenders[playerID], entities.TransactionTypePushRefund, "Blackjack surrender"},
+			}
+			paid := int64(0)
+			alreadyPaid := g.Paid[playerID]
+			for _, credit := range credits {
+				if credit.amount <= 0 {
+					continue
+				}
+				if alreadyPaid >= credit.amount {
+					// Paid before the payouts were interrupted
+					alreadyPaid -= credit.amount
+					continue
+				}
+				log.Printf("Adding $%d to player %s wallet with description: %s", credit.amount, playerID, credit.description)
+				err = walletService.AddFunds(ctx, playerID, g.ChannelID, credit.amount, credit.transactionType, g.reference(""), credit.description)
+				if err != nil {
+					log.Printf("Error adding $%d to player %s wallet: %v", credit.amount, playerID, err)
+					return err
+				}
+				if g.Paid == nil {
+					g.Paid = make(map[string]int64)
+				}
+				g.Paid[playerID] += credit.amount
+				paid += credit.amount
+				g.emit(Event{Type: EventPayout, PlayerID: playerID, Amount: credit.amount})
+			}
+			if paid == 0 {
 				continue
 			}
-			log.Printf("Successfully added $%d winnings to player %s wallet", payout, playerID)
+			log.Printf("Successfully added $%d to player %s wallet", paid, playerID)
 
 			// Get updated wallet to verify
 			log.Printf("[DEBUG] Getting updated wallet for player %s after payout", playerID)
@@ -690,13 +877,21 @@ func (g *Game) ProcessPayouts(ctx context.Context, walletService WalletService)
 			if err != nil {
 				log.Printf("Error getting updated wallet for player %s: %v", playerID, err)
 			} else {
-				log.Printf("After payout: Player %s wallet balance: $%d (expected: $%d)", playerID, updatedWallet.Balance, wallet.Balance+payout)
+				log.Printf("After payout: Player %s wallet balance: $%d (expected: $%d)", playerID, updatedWallet.Balance, wallet.Balance+paid)
 			}
 		} else {
 			log.Printf("Player %s has zero payout (likely lost)", playerID)
 		}
 	}
 
+	// Everything's paid, what's left at the table goes to the house
+	if err := settleTable(ctx, walletService, g.ChannelID); err != nil {
+		return err
+	}
+
+	// Tuco takes his cut of what the round won anyone who owes him, once
+	g.collectFromProfits(ctx, walletService, handResults)
+
 	// Save game record to repository if available
 	if g.repo != nil {
 		// Create game record
@@ -709,10 +904,23 @@ func (g *Game) ProcessPayouts(ctx context.Context, walletService WalletService)
 			PlayerRecords: make([]game.HandRecord, 0, len(handResults)),
 			DealerCards:   getCardStrings(g.Dealer.Cards),
 			DealerScore:   g.Dealer.Value(),
+			Decks:         g.Rules.Decks,
+			ShoePosition:  g.ShoePosition,
+			DealOrder:     g.dealOrder(),
+		}
+		if !g.ShoeSeed.IsZero() {
+			gameRecord.ShoeSeed = g.ShoeSeed.String()
+		}
+		if !g.NewShoeSeed.IsZero() {
+			gameRecord.NewShoeSeed = g.NewShoeSeed.String()
 		}
 
-		// Add hand records
+		// Add hand records, computer players' hands don't count towards anyone's record
 		for _, result := range handResults {
+			if IsBotPlayer(result.PlayerID) {
+				continue
+			}
+
 			// Get cards for this hand
 			var cards []string
 			if hand, exists := g.Players[result.HandID]; exists {
@@ -735,14 +943,22 @@ func (g *Game) ProcessPayouts(ctx context.Context, walletService WalletService)
 				Result:          convertStringResultToResult(result.Result),
 				Payout:          result.Payout,
 				InsurancePayout: result.InsurancePayout,
-				Actions:         []string{}, // We don't track individual actions yet
+				Actions:         g.HandActions(result.HandID),
 				Metadata:        make(map[string]interface{}),
 			}
+			handRecord.Metadata = g.sideBetMetadata(result.HandID)
 
 			// Add to game record
 			gameRecord.PlayerRecords = append(gameRecord.PlayerRecords, handRecord)
 		}
 
+		// Score each player's decisions against basic strategy
+		if handScores, err := g.ScoreStrategy(); err != nil {
+			log.Printf("Error scoring strategy for game %s: %v", g.ID, err)
+		} else {
+			gameRecord.StrategyRecords = g.strategyRecords(handScores)
+		}
+
 		// Save game record
 		log.Printf("Saving game record to repository for game %s", g.ID)
 		err := g.repo.SaveGameResult(ctx, convertToGameResult(gameRecord))
@@ -756,12 +972,15 @@ func (g *Game) ProcessPayouts(ctx context.Context, walletService WalletService)
 
 	// Mark payouts as processed
 	g.PayoutsProcessed = true
+	g.emit(Event{Type: EventRoundComplete})
 	log.Printf("Payouts processed and marked as complete")
 
 	return nil
 }
 
 // CalculatePayouts calculates the payout amounts for each player based on their results
+// The payouts of all of a player's hands, split hands and extra seats included, are
+// added up so each player is paid once a round
 // This method does not update any wallets, it just calculates the payout amounts
 func (g *Game) CalculatePayouts() map[string]int64 {
 	// Calculate payouts for each player
@@ -776,14 +995,10 @@ func (g *Game) CalculatePayouts() map[string]int64 {
 
 	// Calculate payout for each player
 	for _, result := range results {
-		// Use the payout already calculated in GetResults
+		// Use the payout already calculated in GetResults, side bets like
+		// insurance are paid separately by PaySideBets
 		payout := result.Payout
 
-		// Add insurance payout if applicable
-		if result.HasInsurance {
-			payout += result.InsurancePayout
-		}
-
 		// Add the payout to the player's total
 		if existingPayout, exists := payouts[result.PlayerID]; exists {
 			payouts[result.PlayerID] = existingPayout + payout
@@ -797,29 +1012,31 @@ func (g *Game) CalculatePayouts() map[string]int64 {
 			logMsg += fmt.Sprintf(" (doubled down +$%d)", result.DoubleDownBet)
 		}
 		logMsg += fmt.Sprintf(", result: %s, payout: $%d", result.Result, payout)
-		if result.HasInsurance {
-			logMsg += fmt.Sprintf(" (includes insurance payout: $%d)", result.InsurancePayout)
-		}
 		log.Println(logMsg)
 	}
 
 	log.Printf("Calculated payouts: %v", payouts)
 
-	// Check if any players are missing from the payouts
-	for playerID := range g.Bets {
-		// Skip split hand IDs (they contain an underscore)
-		if strings.Contains(playerID, "_") {
-			continue
-		}
-
+	// Check if any players are missing from the payouts, their hands are grouped under the owner
+	for handID := range g.Bets {
+		playerID := g.HandOwner(handID)
 		if _, exists := payouts[playerID]; !exists {
-			log.Printf("WARNING: Player %s has a bet but no payout calculated", playerID)
+			log.Printf("WARNING: Player %s has a bet on hand %s but no payout calculated", playerID, handID)
 		}
 	}
 
 	return payouts
 }
 
+// payoutType returns the wallet transaction type of a hand's payout, a push
+// only gives the stake back
+func payoutType(result entities.Result) entities.TransactionType {
+	if result == entities.StringResultPush {
+		return entities.TransactionTypePushRefund
+	}
+	return entities.TransactionTypePayout
+}
+
 // CalculateResults calculates the results of the game
 func (g *Game) CalculateResults(ctx context.Context, walletService WalletService) (map[string]*entities.PlayerResult, error) {
 	results := make(map[string]*entities.PlayerResult)
@@ -884,30 +1101,16 @@ func (g *Game) CalculateResults(ctx context.Context, walletService WalletService
 		} else if playerBlackjack && !dealerBlackjack {
 			// Player has blackjack, dealer doesn't
 			result.Result = entities.StringResultBlackjack
-			// Blackjack pays 3:2
-			payout = betAmount + int64(float64(betAmount)*1.5)
+			// Blackjack pays according to the table rules
+			payout = betAmount + g.Rules.BlackjackPayout.Winnings(betAmount)
 		} else if dealerBlackjack && !playerBlackjack {
 			// Dealer has blackjack, player doesn't
 			result.Result = entities.StringResultLose
 			payout = 0
-
-			// Check for insurance
-			if hand.HasInsurance() {
-				insuranceBet := hand.GetInsuranceBet()
-				// Insurance pays 2:1 when dealer has blackjack
-				payout = insuranceBet * 3
-			}
 		} else if dealerBlackjack && playerBlackjack {
 			// Both have blackjack, it's a push
 			result.Result = entities.StringResultPush
 			payout = betAmount
-
-			// Check for insurance
-			if hand.HasInsurance() {
-				insuranceBet := hand.GetInsuranceBet()
-				// Insurance pays 2:1 when dealer has blackjack
-				payout += insuranceBet * 3
-			}
 		} else if playerScore > dealerScore {
 			// Player score is higher than dealer
 			result.Result = entities.StringResultWin
@@ -926,13 +1129,6 @@ func (g *Game) CalculateResults(ctx context.Context, walletService WalletService
 			// Dealer score is higher than player
 			result.Result = entities.StringResultLose
 			payout = 0
-
-			// Check for insurance if dealer has blackjack
-			if dealerBlackjack && hand.HasInsurance() {
-				insuranceBet := hand.GetInsuranceBet()
-				// Insurance pays 2:1 when dealer has blackjack
-				payout = insuranceBet * 3
-			}
 		} else {
 			// Scores are equal, it's a push
 			result.Result = entities.StringResultPush
@@ -945,7 +1141,10 @@ func (g *Game) CalculateResults(ctx context.Context, walletService WalletService
 			err := walletService.AddFunds(
 				ctx,
 				playerID,
+				g.ChannelID,
 				payout,
+				payoutType(result.Result),
+				g.reference(playerID),
 				"Blackjack winnings",
 			)
 			if err != nil {
@@ -1010,7 +1209,10 @@ func (g *Game) CalculateResults(ctx context.Context, walletService WalletService
 					err := walletService.AddFunds(
 						ctx,
 						playerID, // Note: winnings go to the original player, not the split hand ID
+						g.ChannelID,
 						splitPayout,
+						payoutType(splitResult.Result),
+						g.reference(splitHandID),
 						"Blackjack split hand winnings",
 					)
 					if err != nil {
@@ -1118,20 +1320,65 @@ func (g *Game) IsPlayerTurn(playerID string) bool {
 		return false
 	}
 
-	// Direct match for the player ID
-	if playerID == currentPlayer {
-		return true
+	// The current hand may be any of the player's hands, including split hands
+	return g.HandOwner(currentPlayer) == playerID
+}
+
+// HandOwner returns the ID of the player who owns a hand
+func (g *Game) HandOwner(handID string) string {
+	if hand, exists := g.Players[handID]; exists {
+		if ownerID := hand.GetOwnerID(); ownerID != "" {
+			return ownerID
+		}
 	}
+	return handID
+}
 
-	// Check if this is a split hand situation
-	// If the current player is a split hand (e.g., "player1_split"),
-	// then the original player ("player1") should be able to play it
-	if strings.HasSuffix(currentPlayer, "_split") {
-		originalPlayer := strings.TrimSuffix(currentPlayer, "_split")
-		return playerID == originalPlayer
+// HandsForPlayer returns the IDs of all hands owned by a player in turn order
+func (g *Game) HandsForPlayer(playerID string) []string {
+	var handIDs []string
+	for _, handID := range g.HandIDsInOrder() {
+		if g.HandOwner(handID) == playerID {
+			handIDs = append(handIDs, handID)
+		}
 	}
+	return handIDs
+}
 
-	return false
+// HandIDsInOrder returns all hand IDs in turn order, with each player's seats
+// side by side and split hands following the hand they were split from
+func (g *Game) HandIDsInOrder() []string {
+	// If we have a predefined player order, use that
+	if len(g.PlayerOrder) > 0 {
+		return g.PlayerOrder
+	}
+
+	// Otherwise order the hands from the Players map, sorted for a stable display.
+	// A player's extra seats sort right after their first.
+	var seats []string
+	split := make(map[string][]string)
+	for handID, hand := range g.Players {
+		if parentID := hand.GetParentHandID(); parentID != "" {
+			split[parentID] = append(split[parentID], handID)
+			continue
+		}
+		seats = append(seats, handID)
+	}
+	sort.Strings(seats)
+
+	handIDs := make([]string, 0, len(g.Players))
+	var addHand func(handID string)
+	addHand = func(handID string) {
+		handIDs = append(handIDs, handID)
+		sort.Strings(split[handID])
+		for _, splitHandID := range split[handID] {
+			addHand(splitHandID)
+		}
+	}
+	for _, seatID := range seats {
+		addHand(seatID)
+	}
+	return handIDs
 }
 
 // IsGameComplete returns true if the game is in a completed state
@@ -1142,6 +1389,7 @@ func (g *Game) IsGameComplete() bool {
 // PlayerInfo contains all UI-relevant information about a player
 type PlayerInfo struct {
 	PlayerID          string
+	HandID            string // Seat the info is about, the player's own ID for their first seat
 	HasBet            bool
 	BetAmount         int64
 	WalletBalance     int64
@@ -1158,32 +1406,44 @@ type GameUIInfo struct {
 	ShouldProcessPayouts bool
 }
 
-// WalletService defines the interface for wallet operations
+// WalletService defines the interface for wallet operations. A round's
+// stakes are held rather than taken outright: the held chips are captured
+// into the table's escrow together once the round is paid, and given back if
+// the round is abandoned, so a round lost to a restart never loses anyone's
+// money. Once a round is paid the table's escrow is settled with the house.
 type WalletService interface {
 	GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error)
-	AddFunds(ctx context.Context, userID string, amount int64, description string) error
-	RemoveFunds(ctx context.Context, userID string, amount int64, description string) error
+	AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error
 	EnsureFundsWithLoan(ctx context.Context, userID string, requiredAmount int64, loanAmount int64) (*entities.Wallet, bool, error)
 	GetStandardLoanIncrement() int64
+	HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error
+	CaptureHold(ctx context.Context, userID, roundID string, amount int64) error
+	CaptureRoundHolds(ctx context.Context, roundID string) error
+	ReleaseHold(ctx context.Context, userID, roundID string) (int64, error)
+	ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error
+	ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error)
+	SettleTable(ctx context.Context, tableID string) error
+	CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error)
+}
+
+// settleTable settles a table's escrow with the house
+func settleTable(ctx context.Context, walletService WalletService, tableID string) error {
+	if err := walletService.SettleTable(ctx, tableID); err != nil {
+		log.Printf("Error settling table %s with the house: %v", tableID, err)
+		return err
+	}
+	return nil
 }
 
 // GetPlayerWallets retrieves wallets for all players in the game and identifies the highest balance
-// Returns a map of player IDs to wallets and the highest balance amount
+// Returns a map of player IDs to wallets and the highest balance amount among the real players
+// Players with more than one seat or hand have one wallet
 func (g *Game) GetPlayerWallets(ctx context.Context, walletService WalletService) (map[string]*entities.Wallet, int64, error) {
 	playerWallets := make(map[string]*entities.Wallet)
 	highestBalance := int64(-1)
 
-	// Use PlayerOrder if available, otherwise use the Players map
-	playerIDs := g.PlayerOrder
-	if len(playerIDs) == 0 {
-		playerIDs = make([]string, 0, len(g.Players))
-		for playerID := range g.Players {
-			playerIDs = append(playerIDs, playerID)
-		}
-	}
-
-	// Collect all player wallets
-	for _, playerID := range playerIDs {
+	// Collect all player wallets, one for each player however many hands they play
+	for _, playerID := range g.PlayerIDs() {
 		wallet, _, err := walletService.GetOrCreateWallet(ctx, playerID)
 		if err != nil {
 			log.Printf("Error getting wallet for player %s: %v", playerID, err)
@@ -1191,7 +1451,8 @@ func (g *Game) GetPlayerWallets(ctx context.Context, walletService WalletService
 		}
 
 		playerWallets[playerID] = wallet
-		if wallet.Balance > highestBalance {
+		// House money doesn't compete with real wallets
+		if !IsBotPlayer(playerID) && wallet.Balance > highestBalance {
 			highestBalance = wallet.Balance
 		}
 	}
@@ -1210,31 +1471,35 @@ func (g *Game) GetAllPlayersInfo(ctx context.Context, walletService WalletServic
 
 	// Use PlayerOrder if available
 	if len(g.PlayerOrder) > 0 {
-		for i, playerID := range g.PlayerOrder {
+		for i, handID := range g.PlayerOrder {
+			playerID := g.HandOwner(handID)
 			wallet := playerWallets[playerID]
-			bet, hasBet := g.Bets[playerID]
+			bet, hasBet := g.Bets[handID]
 
 			playersInfo = append(playersInfo, PlayerInfo{
 				PlayerID:          playerID,
+				HandID:            handID,
 				HasBet:            hasBet,
 				BetAmount:         bet,
 				WalletBalance:     wallet.Balance,
-				HasHighestBalance: wallet.Balance == highestBalance,
+				HasHighestBalance: wallet.Balance == highestBalance && !IsBotPlayer(playerID),
 				IsCurrentTurn:     g.CurrentBettingPlayer == i,
 			})
 		}
 	} else {
 		// Fallback to Players map
-		for playerID := range g.Players {
+		for _, handID := range g.HandIDsInOrder() {
+			playerID := g.HandOwner(handID)
 			wallet := playerWallets[playerID]
-			bet, hasBet := g.Bets[playerID]
+			bet, hasBet := g.Bets[handID]
 
 			playersInfo = append(playersInfo, PlayerInfo{
 				PlayerID:          playerID,
+				HandID:            handID,
 				HasBet:            hasBet,
 				BetAmount:         bet,
 				WalletBalance:     wallet.Balance,
-				HasHighestBalance: wallet.Balance == highestBalance,
+				HasHighestBalance: wallet.Balance == highestBalance && !IsBotPlayer(playerID),
 				IsCurrentTurn:     false, // Can't determine current turn without PlayerOrder
 			})
 		}
@@ -1318,16 +1583,18 @@ func (g *Game) GetCurrentBettingPlayerInfo(ctx context.Context, walletService Wa
 		return nil, nil
 	}
 
-	playerID := g.PlayerOrder[g.CurrentBettingPlayer]
+	handID := g.PlayerOrder[g.CurrentBettingPlayer]
+	playerID := g.HandOwner(handID)
 	wallet, _, err := walletService.GetOrCreateWallet(ctx, playerID)
 	if err != nil {
 		return nil, err
 	}
 
-	bet, hasBet := g.Bets[playerID]
+	bet, hasBet := g.Bets[handID]
 
 	return &PlayerInfo{
 		PlayerID:      playerID,
+		HandID:        handID,
 		HasBet:        hasBet,
 		BetAmount:     bet,
 		WalletBalance: wallet.Balance,
@@ -1341,16 +1608,18 @@ func (g *Game) GetCurrentPlayerInfo(ctx context.Context, walletService WalletSer
 		return nil, nil
 	}
 
-	playerID := g.PlayerOrder[g.CurrentTurn]
+	handID := g.PlayerOrder[g.CurrentTurn]
+	playerID := g.HandOwner(handID)
 	wallet, _, err := walletService.GetOrCreateWallet(ctx, playerID)
 	if err != nil {
 		return nil, err
 	}
 
-	bet, hasBet := g.Bets[playerID]
+	bet, hasBet := g.Bets[handID]
 
 	return &PlayerInfo{
 		PlayerID:      playerID,
+		HandID:        handID,
 		HasBet:        hasBet,
 		BetAmount:     bet,
 		WalletBalance: wallet.Balance,
@@ -1420,7 +1689,7 @@ type HandResult struct {
 	DoubleDownBet   int64
 	HasInsurance    bool
 	InsuranceBet    int64
-	InsurancePayout int64
+	InsurancePayout int64 // Stake included, paid with the other side bets
 }
 
 // GetResults evaluates all hands against the dealer and returns results
@@ -1434,17 +1703,16 @@ func (g *Game) GetResults() ([]HandResult, error) {
 	dealerScore := GetBestScore(g.Dealer.Cards)
 	dealerBJ := IsBlackjack(g.Dealer.Cards)
 
-	// Process all player hands (including split hands)
-	for handID, hand := range g.Players {
-		// Determine the player ID for this hand
-		playerID := handID
-
-		// If this is a split hand, the handID might be different from the playerID
-		// In that case, we need to get the actual playerID from the parent hand ID
-		if hand.IsSplit() && hand.GetParentHandID() != "" {
-			playerID = hand.GetParentHandID()
+	// Process all player hands (including split hands) in turn order
+	for _, handID := range g.HandIDsInOrder() {
+		hand, exists := g.Players[handID]
+		if !exists {
+			continue
 		}
 
+		// Determine the player who owns this hand, split hands belong to the original player
+		playerID := g.HandOwner(handID)
+
 		// Create base result
 		result := HandResult{
 			PlayerID: playerID,
@@ -1467,10 +1735,26 @@ func (g *Game) GetResults() ([]HandResult, error) {
 			result.Bet += result.DoubleDownBet
 		}
 
-		// Check for insurance
-		if hand.HasInsurance() {
+		// Insurance is a side bet, paid by PaySideBets
+		if insurance := g.GetInsurance(handID); insurance != nil {
 			result.HasInsurance = true
-			result.InsuranceBet = hand.GetInsuranceBet()
+			result.InsuranceBet = insurance.Amount
+			result.InsurancePayout = insurance.Payout
+		}
+
+		// Handle surrendered hands, which get half the bet back
+		if hand.IsSurrendered() {
+			if dealerBJ && g.Rules.Surrender != SurrenderEarly {
+				// Late surrender doesn't protect against a dealer blackjack
+				result.Result = entities.StringResultLose
+				result.Payout = 0
+			} else {
+				result.Result = entities.StringResultSurrender
+				result.Payout = result.Bet / 2
+			}
+
+			results = append(results, result)
+			continue
 		}
 
 		// Handle busted hands first
@@ -1488,14 +1772,7 @@ func (g *Game) GetResults() ([]HandResult, error) {
 				result.Payout = result.Bet // Return the original bet
 			} else {
 				result.Result = entities.StringResultBlackjack
-				result.Payout = result.Bet + int64(float64(result.Bet)*1.5) // Blackjack pays 3:2
-			}
-
-			// Calculate insurance payout if applicable
-			if result.HasInsurance && dealerBJ {
-				result.InsurancePayout = result.InsuranceBet * 2 // Insurance pays 2:1
-			} else if result.HasInsurance {
-				result.InsurancePayout = 0 // No insurance payout if dealer doesn't have blackjack
+				result.Payout = result.Bet + g.Rules.BlackjackPayout.Winnings(result.Bet)
 			}
 
 			results = append(results, result)
@@ -1508,11 +1785,6 @@ func (g *Game) GetResults() ([]HandResult, error) {
 		if dealerBJ {
 			result.Result = entities.StringResultLose
 			result.Payout = 0 // No payout for loss
-
-			// Calculate insurance payout if applicable
-			if result.HasInsurance {
-				result.InsurancePayout = result.InsuranceBet * 2 // Insurance pays 2:1
-			}
 		} else if hand.Status == StatusBust {
 			result.Result = entities.StringResultLose
 			result.Payout = 0 // No payout for busted hands
@@ -1530,11 +1802,6 @@ func (g *Game) GetResults() ([]HandResult, error) {
 			result.Payout = result.Bet // Return the original bet
 		}
 
-		// Calculate insurance payout if applicable and not already calculated
-		if result.HasInsurance && dealerBJ && result.InsurancePayout == 0 {
-			result.InsurancePayout = result.InsuranceBet * 2 // Insurance pays 2:1
-		}
-
 		results = append(results, result)
 	}
 
@@ -1551,10 +1818,20 @@ func (g *Game) AnyPlayerEligibleForSplit() bool {
 	return false
 }
 
-// AnyPlayerEligibleForSpecialBets checks if any player is eligible for special bets (double down or insurance)
+// AnyPlayerEligibleForInsurancePhase checks if any player has a decision to make before the dealer peeks
+func (g *Game) AnyPlayerEligibleForInsurancePhase() bool {
+	for _, playerID := range g.PlayerOrder {
+		if g.hasInsuranceOptions(playerID) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyPlayerEligibleForSpecialBets checks if any player is eligible for special bets (double down, insurance or surrender)
 func (g *Game) AnyPlayerEligibleForSpecialBets() bool {
 	for _, playerID := range g.PlayerOrder {
-		if g.IsEligibleForDoubleDown(playerID) || g.IsEligibleForInsurance() {
+		if g.IsEligibleForDoubleDown(playerID) || g.IsEligibleForInsurance() || g.IsEligibleForSurrender(playerID) {
 			return true
 		}
 	}
@@ -1563,8 +1840,13 @@ func (g *Game) AnyPlayerEligibleForSpecialBets() bool {
 
 // BlackjackGameDetails implements the entities.GameDetails interface for blackjack games
 type BlackjackGameDetails struct {
-	DealerCards []string `json:"dealer_cards"`
-	DealerScore int      `json:"dealer_score"`
+	DealerCards  []string `json:"dealer_cards"`
+	DealerScore  int      `json:"dealer_score"`
+	Decks        int      `json:"decks"`
+	ShoeSeed     string   `json:"shoe_seed,omitempty"`
+	ShoePosition int      `json:"shoe_position"`
+	NewShoeSeed  string   `json:"new_shoe_seed,omitempty"`
+	DealOrder    []string `json:"deal_order,omitempty"`
 }
 
 // GameType returns the game type
@@ -1583,6 +1865,17 @@ func (d BlackjackGameDetails) ValidateDetails() error {
 
 // Helper functions for game record conversion
 
+// dealOrder returns the seats in the order they were dealt, leaving out split hands
+func (g *Game) dealOrder() []string {
+	order := make([]string, 0, len(g.PlayerOrder))
+	for _, handID := range g.PlayerOrder {
+		if g.IsSeat(handID) {
+			order = append(order, handID)
+		}
+	}
+	return order
+}
+
 // getCardStrings converts a slice of Card pointers to a slice of strings
 func getCardStrings(cards []*entities.Card) []string {
 	result := make([]string, len(cards))
@@ -1606,8 +1899,13 @@ func convertToGameResult(record game.GameRecord) *entities.GameResult {
 		CompletedAt:   record.EndTime,
 		PlayerResults: make([]*entities.PlayerResult, 0, len(record.PlayerRecords)),
 		Details: BlackjackGameDetails{
-			DealerCards: record.DealerCards,
-			DealerScore: record.DealerScore,
+			DealerCards:  record.DealerCards,
+			DealerScore:  record.DealerScore,
+			Decks:        record.Decks,
+			ShoeSeed:     record.ShoeSeed,
+			ShoePosition: record.ShoePosition,
+			NewShoeSeed:  record.NewShoeSeed,
+			DealOrder:    record.DealOrder,
 		},
 	}
 
@@ -1641,10 +1939,24 @@ func convertToGameResult(record game.GameRecord) *entities.GameResult {
 			playerResult.Metadata["insurance_payout"] = handRecord.InsurancePayout
 		}
 
+		// Side bets and anything else recorded with the hand
+		for key, value := range handRecord.Metadata {
+			playerResult.Metadata[key] = value
+		}
+
 		// Add score information
 		playerResult.Metadata["score"] = handRecord.FinalScore
 		playerResult.Metadata["bet"] = handRecord.InitialBet
 
+		// How well the player followed basic strategy over the whole round
+		for _, strategyRecord := range record.StrategyRecords {
+			if strategyRecord.PlayerID == handRecord.PlayerID {
+				playerResult.Metadata["strategy_decisions"] = strategyRecord.Decisions
+				playerResult.Metadata["strategy_correct"] = strategyRecord.Correct
+				playerResult.Metadata["strategy_accuracy"] = strategyRecord.Accuracy
+			}
+		}
+
 		// Add to result
 		result.PlayerResults = append(result.PlayerResults, playerResult)
 	}
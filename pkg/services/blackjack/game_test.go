@@ -30,10 +30,16 @@ func (s *GameTestSuite) SetupTest() {
 	s.mockGameRepo = mock_game.NewMockRepository(s.ctrl)
 	s.mockWalletRepo = mock_wallet.NewMockRepository(s.ctrl)
 
+	// Every state change is appended to the round's event log
+	s.mockGameRepo.EXPECT().
+		AppendEvent(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
 	s.channelID = "test-channel"
-	s.game = NewGame(s.channelID, s.mockGameRepo)
+	s.game = NewGame(s.channelID, s.mockGameRepo, DefaultRuleSet(), nil)
 
-	s.testDeck = NewBlackjackDeck()
+	s.testDeck = NewBlackjackDeck(StandardDecks, nil)
 	s.testDeck.Shuffle()
 }
 
@@ -120,7 +126,7 @@ func (s *GameTestSuite) TestStartGame() {
 	// Mock the repository calls for Start
 	s.mockGameRepo.EXPECT().
 		GetDeck(gomock.Any(), "test-channel").
-		Return(s.testDeck.Cards, nil)
+		Return(&entities.Deck{Cards: s.testDeck.Cards}, nil)
 
 	_ = s.game.Start()
 
@@ -268,7 +274,7 @@ func (s *GameTestSuite) TestPayoutCalculation() {
 
 	// Expect AddFunds to be called with the regular win payout amount
 	mockWalletService.EXPECT().
-		AddFunds(gomock.Any(), "player1", int64(200), gomock.Any()).
+		AddFunds(gomock.Any(), "player1", s.channelID, int64(200), entities.TransactionTypePayout, gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -472,7 +478,7 @@ func (s *GameTestSuite) TestPayoutCalculation_Push() {
 
 	// Expect AddFunds to be called with the original bet amount
 	mockWalletService.EXPECT().
-		AddFunds(gomock.Any(), "player1", int64(100), gomock.Any()).
+		AddFunds(gomock.Any(), "player1", s.channelID, int64(100), entities.TransactionTypePushRefund, gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -577,7 +583,7 @@ func (s *GameTestSuite) TestPayoutCalculation_Blackjack() {
 
 	// Expect AddFunds to be called with the blackjack payout amount
 	mockWalletService.EXPECT().
-		AddFunds(gomock.Any(), "player1", int64(250), gomock.Any()).
+		AddFunds(gomock.Any(), "player1", s.channelID, int64(250), entities.TransactionTypePayout, gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -683,7 +689,7 @@ func (s *GameTestSuite) TestPayoutCalculation_DealerBust() {
 
 	// Expect AddFunds to be called with the regular win payout amount
 	mockWalletService.EXPECT().
-		AddFunds(gomock.Any(), "player1", int64(200), gomock.Any()).
+		AddFunds(gomock.Any(), "player1", s.channelID, int64(200), entities.TransactionTypePayout, gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -787,7 +793,7 @@ func (s *GameTestSuite) TestBlackjackPayout() {
 
 	// Expect AddFunds to be called with the blackjack payout amount
 	mockWalletService.EXPECT().
-		AddFunds(gomock.Any(), "player1", int64(250), gomock.Any()).
+		AddFunds(gomock.Any(), "player1", s.channelID, int64(250), entities.TransactionTypePayout, gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
@@ -914,7 +920,12 @@ func (m *mockWalletServiceWrapper) CanRepayLoan(ctx context.Context, userID stri
 	return strings.Contains(userID, "loan"), nil
 }
 
-// wrapMockWalletService wraps a mock wallet service to implement the full WalletService interface
+// wrapMockWalletService wraps a mock wallet service to implement the full
+// WalletService interface. Capturing the round's holds and settling the table
+// always succeed, the tests check what's paid.
 func wrapMockWalletService(mock *mock_wallet_service.MockWalletService) WalletService {
+	mock.EXPECT().CaptureRoundHolds(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mock.EXPECT().SettleTable(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mock.EXPECT().CollectFromWinnings(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), nil).AnyTimes()
 	return &mockWalletServiceWrapper{MockWalletService: mock}
 }
@@ -19,8 +19,8 @@ const (
 	MetaKeySplit        = "is_split"        // Whether the hand is a split hand
 	MetaKeySplitHandID  = "split_hand_id"   // ID of the split hand (for tracking split pairs)
 	MetaKeyParentHandID = "parent_hand_id"  // ID of the parent hand (for split hands)
-	MetaKeyInsurance    = "has_insurance"   // Whether insurance was taken
-	MetaKeyInsuranceBet = "insurance_bet"   // The amount of the insurance bet
+	MetaKeySurrendered  = "surrendered"     // Whether the player surrendered the hand
+	MetaKeyOwnerID      = "owner_id"        // ID of the player who owns the hand
 )
 
 // Status represents the current state of the hand
@@ -187,38 +187,44 @@ func (h *Hand) SetParentHandID(id string) {
 	h.Metadata[MetaKeyParentHandID] = id
 }
 
-// HasInsurance checks if a hand has insurance
-func (h *Hand) HasInsurance() bool {
-	if val, ok := h.Metadata[MetaKeyInsurance]; ok {
-		if boolVal, ok := val.(bool); ok {
-			return boolVal
+// GetOwnerID gets the ID of the player who owns the hand
+func (h *Hand) GetOwnerID() string {
+	if val, ok := h.Metadata[MetaKeyOwnerID]; ok {
+		if strVal, ok := val.(string); ok {
+			return strVal
 		}
 	}
-	return false
+	return ""
 }
 
-// SetInsurance sets the insurance status of a hand
-func (h *Hand) SetInsurance(value bool) {
+// SetOwnerID sets the ID of the player who owns the hand
+func (h *Hand) SetOwnerID(id string) {
 	if h.Metadata == nil {
 		h.Metadata = make(map[string]interface{})
 	}
-	h.Metadata[MetaKeyInsurance] = value
+	h.Metadata[MetaKeyOwnerID] = id
 }
 
-// GetInsuranceBet gets the insurance bet amount
-func (h *Hand) GetInsuranceBet() int64 {
-	if val, ok := h.Metadata[MetaKeyInsuranceBet]; ok {
-		if intVal, ok := val.(int64); ok {
-			return intVal
+// IsSplitAces checks if a hand was created by splitting a pair of aces
+func (h *Hand) IsSplitAces() bool {
+	return h.IsSplit() && len(h.Cards) > 0 && IsAce(h.Cards[0])
+}
+
+// IsSurrendered checks if a hand has been surrendered
+func (h *Hand) IsSurrendered() bool {
+	if val, ok := h.Metadata[MetaKeySurrendered]; ok {
+		if boolVal, ok := val.(bool); ok {
+			return boolVal
 		}
 	}
-	return 0
+	return false
 }
 
-// SetInsuranceBet sets the insurance bet amount
-func (h *Hand) SetInsuranceBet(amount int64) {
+// SetSurrendered sets the surrendered status of a hand
+func (h *Hand) SetSurrendered(value bool) {
 	if h.Metadata == nil {
 		h.Metadata = make(map[string]interface{})
 	}
-	h.Metadata[MetaKeyInsuranceBet] = amount
+	h.Metadata[MetaKeySurrendered] = value
 }
+
@@ -0,0 +1,168 @@
+package blackjack
+
+import (
+	"fmt"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/fadedpez/tucoramirez/pkg/services/strategy"
+)
+
+// StrategyScore is how closely a player's decisions followed basic strategy
+type StrategyScore struct {
+	Decisions int // Decisions basic strategy has an answer for
+	Correct   int // Decisions that matched it
+}
+
+// Accuracy returns the share of decisions that matched basic strategy, from 0 to 1
+func (s StrategyScore) Accuracy() float64 {
+	if s.Decisions == 0 {
+		return 0
+	}
+	return float64(s.Correct) / float64(s.Decisions)
+}
+
+// Hint returns basic strategy's play for the hand a player is deciding on.
+// While splitting and special bets are being decided the hint covers the
+// whole hand, so a Hit while doubling is offered means to pass and then hit.
+func (g *Game) Hint(playerID string) (strategy.Action, error) {
+	var handID string
+	var err error
+	switch g.State {
+	case StateInsurance, StateSpecialBets:
+		handID, err = g.GetCurrentSpecialBetsPlayerID()
+	case StateSplitting:
+		handID = g.GetCurrentSplittingPlayerID()
+	case entities.StatePlaying:
+		handID, err = g.GetCurrentTurnPlayerID()
+	default:
+		return "", ErrInvalidAction
+	}
+	if err != nil {
+		return "", err
+	}
+	if handID == "" || g.HandOwner(handID) != playerID {
+		return "", ErrNotPlayerTurn
+	}
+
+	return g.advise(handID), nil
+}
+
+// allowedPlays returns what a hand may do in the current phase, counting the
+// double and surrender still to come while a pair is deciding whether to split
+func (g *Game) allowedPlays(handID string) strategy.Allowed {
+	switch g.State {
+	case StateInsurance:
+		return strategy.Allowed{Surrender: g.IsEligibleForEarlySurrender(handID)}
+	case StateSplitting:
+		return strategy.Allowed{
+			Split:     g.IsEligibleForSplit(handID),
+			Double:    g.IsEligibleForDoubleDown(handID),
+			Surrender: g.IsEligibleForSurrender(handID),
+		}
+	case StateSpecialBets:
+		return strategy.Allowed{
+			Double:    g.IsEligibleForDoubleDown(handID),
+			Surrender: g.IsEligibleForSurrender(handID),
+		}
+	}
+	return strategy.Allowed{}
+}
+
+// advise returns basic strategy's play for a hand against the dealer's up-card
+func (g *Game) advise(handID string) strategy.Action {
+	hand, exists := g.Players[handID]
+	if !exists || len(g.Dealer.Cards) == 0 {
+		return strategy.ActionStand
+	}
+
+	// Once the dealer has peeked, surrendering is late surrender whatever the table calls it
+	rules := g.Rules.StrategyRules()
+	if g.State != StateInsurance {
+		rules.EarlySurrender = false
+	}
+	return strategy.ChartFor(rules).Advise(hand.Cards, g.Dealer.Cards[0], g.allowedPlays(handID))
+}
+
+// followedStrategy returns whether a decision matched basic strategy's play.
+// Passing on special bets only counts when doubling or surrendering was on offer.
+func followedStrategy(eventType EventType, advice strategy.Action, allowed strategy.Allowed) (followed bool, scored bool) {
+	switch eventType {
+	case EventSplit:
+		return advice == strategy.ActionSplit, true
+	case EventSplitDeclined:
+		return advice != strategy.ActionSplit, true
+	case EventDoubleDown:
+		return advice == strategy.ActionDouble, true
+	case EventSurrender:
+		return advice == strategy.ActionSurrender, true
+	case EventDeclined:
+		if !allowed.Double && !allowed.Surrender {
+			return false, false
+		}
+		return advice != strategy.ActionDouble && advice != strategy.ActionSurrender, true
+	case EventHit:
+		return advice == strategy.ActionHit, true
+	case EventStand:
+		return advice == strategy.ActionStand, true
+	}
+	return false, false
+}
+
+// ScoreStrategy plays the round's events back and checks every decision
+// against basic strategy. It returns the score for each hand, by hand ID.
+func (g *Game) ScoreStrategy() (map[string]StrategyScore, error) {
+	replay, err := newReplayGame(g.ChannelID, g.ID, g.Rules, g.Events)
+	if err != nil {
+		return nil, err
+	}
+	replay.registry = g.registry
+
+	scores := make(map[string]StrategyScore)
+	for _, event := range g.Events {
+		// Every decision is made before the dealer's turn
+		if replay.State == entities.StateDealer || replay.State == entities.StateComplete {
+			break
+		}
+
+		if followed, scored := followedStrategy(event.Type, replay.advise(event.HandID), replay.allowedPlays(event.HandID)); scored {
+			score := scores[event.HandID]
+			score.Decisions++
+			if followed {
+				score.Correct++
+			}
+			scores[event.HandID] = score
+		}
+
+		if err := replay.apply(event); err != nil {
+			return nil, fmt.Errorf("%w: event %d (%s): %v", ErrEventLogMismatch, event.Sequence, event.Type, err)
+		}
+	}
+	return scores, nil
+}
+
+// strategyRecords adds up each player's hands, every seat and split included,
+// into their strategy record for the round. Computer players don't get one.
+func (g *Game) strategyRecords(handScores map[string]StrategyScore) []game.StrategyRecord {
+	records := []game.StrategyRecord{}
+	for _, playerID := range g.PlayerIDs() {
+		if IsBotPlayer(playerID) {
+			continue
+		}
+		var total StrategyScore
+		for _, handID := range g.HandsForPlayer(playerID) {
+			total.Decisions += handScores[handID].Decisions
+			total.Correct += handScores[handID].Correct
+		}
+		if total.Decisions == 0 {
+			continue
+		}
+		records = append(records, game.StrategyRecord{
+			PlayerID:  playerID,
+			Decisions: total.Decisions,
+			Correct:   total.Correct,
+			Accuracy:  total.Accuracy(),
+		})
+	}
+	return records
+}
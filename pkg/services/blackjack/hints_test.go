@@ -0,0 +1,100 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/fadedpez/tucoramirez/pkg/services/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStackedGame seats the players in seat order at a game whose shoe deals the
+// given cards in order. Extra seats like "player1#2" go to their player.
+func newStackedGame(t *testing.T, repo game.Repository, seatIDs []string, cards ...*entities.Card) *Game {
+	rules := DefaultRuleSet()
+	require.NoError(t, repo.SaveDeck(context.Background(), "test-channel", &entities.Deck{
+		Cards:   cards,
+		Dealt:   rules.ShoeSize() - len(cards),
+		CutCard: rules.ShoeSize(),
+	}))
+
+	g := NewGame("test-channel", repo, rules, nil)
+	for _, seatID := range seatIDs {
+		if playerID, _, ok := parseSeatID(seatID); ok {
+			_, err := g.AddSeat(playerID)
+			require.NoError(t, err)
+			continue
+		}
+		require.NoError(t, g.AddPlayer(seatID))
+	}
+	g.PlayerOrder = append([]string(nil), seatIDs...)
+	require.NoError(t, g.Start())
+	return g
+}
+
+func TestHintFollowsBasicStrategy(t *testing.T) {
+	g := newDealtGame(DefaultRuleSet(), entities.StateDealing, []*entities.Card{
+		{Rank: entities.Six, Suit: entities.Spades},
+		{Rank: entities.Five, Suit: entities.Hearts},
+	}, []*entities.Card{
+		{Rank: entities.Six, Suit: entities.Clubs},
+		{Rank: entities.Ten, Suit: entities.Diamonds},
+	})
+	require.NoError(t, g.beginSpecialPhases())
+	require.Equal(t, StateSpecialBets, g.State)
+
+	// Eleven against a six is a double
+	hint, err := g.Hint("player1")
+	require.NoError(t, err)
+	assert.Equal(t, strategy.ActionDouble, hint)
+
+	_, err = g.Hint("player2")
+	assert.ErrorIs(t, err, ErrNotPlayerTurn)
+
+	// Once doubling is passed up the hint is to hit
+	require.NoError(t, g.DeclineSpecialBet("player1"))
+	require.Equal(t, entities.StatePlaying, g.State)
+	hint, err = g.Hint("player1")
+	require.NoError(t, err)
+	assert.Equal(t, strategy.ActionHit, hint)
+}
+
+func TestStrategyAccuracyIsRecorded(t *testing.T) {
+	ctx := context.Background()
+	repo := game.NewMemoryRepository()
+	g := newStackedGame(t, repo, []string{"player1"},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Six, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Six, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Diamonds},
+		&entities.Card{Rank: entities.Five, Suit: entities.Spades},
+		&entities.Card{Rank: entities.King, Suit: entities.Clubs},
+	)
+	require.NoError(t, g.PlaceBet("player1", 10))
+
+	// Passing on doubling and surrender with 16 against a 6 is right, hitting
+	// it isn't, and standing on the 21 that came is
+	require.Equal(t, StateSpecialBets, g.State)
+	require.NoError(t, g.DeclineSpecialBet("player1"))
+	require.NoError(t, g.Hit("player1"))
+	require.NoError(t, g.Stand("player1"))
+
+	scores, err := g.ScoreStrategy()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]StrategyScore{"player1": {Decisions: 3, Correct: 2}}, scores)
+
+	complete, err := g.CompleteGameIfDone(ctx, newStubWalletService())
+	require.NoError(t, err)
+	require.True(t, complete)
+
+	results, err := repo.GetPlayerResults(ctx, "player1")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	metadata := results[0].PlayerResults[0].Metadata
+	assert.Equal(t, 3, metadata["strategy_decisions"])
+	assert.Equal(t, 2, metadata["strategy_correct"])
+	assert.InDelta(t, 2.0/3.0, metadata["strategy_accuracy"], 0.001)
+}
@@ -0,0 +1,39 @@
+package blackjack
+
+import (
+	"context"
+	"log"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// reference ties a wallet transaction to a hand of the round, e.g.
+// "<round ID>/<hand ID>", or to the round itself if handID is empty
+func (g *Game) reference(handID string) string {
+	if handID == "" {
+		return g.ID
+	}
+	return g.ID + "/" + handID
+}
+
+// stake holds a player's stake on a hand for the round
+func (g *Game) stake(ctx context.Context, walletService WalletService, playerID, handID string, amount int64, transactionType entities.TransactionType, description string) error {
+	return walletService.HoldFunds(ctx, playerID, g.ChannelID, g.ID, amount, transactionType, g.reference(handID), description)
+}
+
+// captureStake hands a stake settled before the round is paid over to the
+// table. Once the round is complete its holds are captured together by
+// ProcessPayouts instead.
+func (g *Game) captureStake(ctx context.Context, walletService WalletService, playerID string, amount int64) {
+	if g.State == entities.StateComplete {
+		return
+	}
+	if err := walletService.CaptureHold(ctx, playerID, g.ID, amount); err != nil {
+		log.Printf("Error capturing $%d held from player %s for round %s: %v", amount, playerID, g.ID, err)
+	}
+}
+
+// captureRoundHolds hands everything still held for the round over to the table
+func (g *Game) captureRoundHolds(ctx context.Context, walletService WalletService) error {
+	return walletService.CaptureRoundHolds(ctx, g.ID)
+}
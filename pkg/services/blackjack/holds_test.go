@@ -0,0 +1,171 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	walletService "github.com/fadedpez/tucoramirez/pkg/services/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dealHeldRound deals a round whose bets are held by a real wallet service
+func dealHeldRound(t *testing.T, repo game.Repository, wallets WalletService, seed byte) *Game {
+	g := NewGame("test-channel", repo, DefaultRuleSet(), entities.NewSeededShuffler(entities.Seed{seed}))
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.AddPlayer("player2"))
+	require.NoError(t, g.Start())
+	for range g.PlayerOrder {
+		_, err := g.PlaceBetWithWalletUpdate(context.Background(), g.PlayerOrder[g.CurrentBettingPlayer], 10, wallets)
+		require.NoError(t, err)
+	}
+	return g
+}
+
+func TestStakesAreHeldUntilTheRoundIsPaid(t *testing.T) {
+	ctx := context.Background()
+	service := walletService.NewService(walletRepo.NewMemoryRepository())
+	wallets := NewHouseMoneyWallet(service)
+	g := dealHeldRound(t, game.NewMemoryRepository(), wallets, 3)
+
+	holds, err := service.GetOpenHolds(ctx)
+	require.NoError(t, err)
+	require.Len(t, holds, 2)
+	for _, hold := range holds {
+		assert.Equal(t, g.ID, hold.RoundID)
+		assert.Equal(t, g.ChannelID, hold.TableID)
+		assert.Equal(t, int64(10), hold.Amount)
+	}
+	balance, err := service.GetBalance(ctx, "player1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(90), balance)
+
+	// Paying the round captures everything held for it, doubles and splits included
+	playOutWith(t, g, wallets)
+	holds, err = service.GetOpenHolds(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, holds)
+
+	reconciliation, err := service.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reconciliation.Problems)
+}
+
+func TestAbandonedRoundReleasesItsHolds(t *testing.T) {
+	ctx := context.Background()
+	repo := game.NewMemoryRepository()
+	service := walletService.NewService(walletRepo.NewMemoryRepository())
+	wallets := NewHouseMoneyWallet(service)
+	g := dealHeldRound(t, repo, wallets, 3)
+
+	refunded, err := RefundRound(ctx, repo, wallets, g.ChannelID, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"player1": 10, "player2": 10}, refunded)
+
+	// The bets were never taken, so they come back without a payout
+	for _, playerID := range []string{"player1", "player2"} {
+		balance, err := service.GetBalance(ctx, playerID)
+		require.NoError(t, err)
+		assert.Equal(t, walletService.StartingBalance, balance)
+	}
+	holds, err := service.GetOpenHolds(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, holds)
+
+	// Nobody gets their bet back twice
+	refunded, err = RefundRound(ctx, repo, wallets, g.ChannelID, g.ID)
+	require.NoError(t, err)
+	assert.Empty(t, refunded)
+	balance, err := service.GetBalance(ctx, "player1")
+	require.NoError(t, err)
+	assert.Equal(t, walletService.StartingBalance, balance)
+
+	reconciliation, err := service.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reconciliation.Problems)
+}
+
+// flakyWallet fails to pay one player while it's broken, and counts the times
+// the table is settled
+type flakyWallet struct {
+	WalletService
+	broken   bool
+	playerID string
+	settled  int
+}
+
+func (w *flakyWallet) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	if w.broken && userID == w.playerID {
+		return errors.New("wallet database is locked")
+	}
+	return w.WalletService.AddFunds(ctx, userID, tableID, amount, transactionType, referenceID, description)
+}
+
+func (w *flakyWallet) SettleTable(ctx context.Context, tableID string) error {
+	w.settled++
+	return w.WalletService.SettleTable(ctx, tableID)
+}
+
+func TestFailedPayoutsCanBeRetried(t *testing.T) {
+	ctx := context.Background()
+	for seed := byte(0); seed < 40; seed++ {
+		service := walletService.NewService(walletRepo.NewMemoryRepository())
+		wallets := &flakyWallet{WalletService: NewHouseMoneyWallet(service), playerID: "player2"}
+		g := dealHeldRound(t, game.NewMemoryRepository(), wallets, seed)
+
+		// Everyone stands, the dealer plays without paying anyone
+		for g.State != entities.StateComplete {
+			switch g.State {
+			case StateSplitting:
+				require.NoError(t, g.DeclineSplit(g.HandOwner(g.GetCurrentSplittingPlayerID())))
+			case StateInsurance, StateSpecialBets:
+				handID, err := g.GetCurrentSpecialBetsPlayerID()
+				require.NoError(t, err)
+				require.NoError(t, g.DeclineSpecialBet(g.HandOwner(handID)))
+			case entities.StatePlaying:
+				handID, err := g.GetCurrentTurnPlayerID()
+				require.NoError(t, err)
+				require.NoError(t, g.Stand(g.HandOwner(handID)))
+			case entities.StateDealer:
+				require.NoError(t, g.PlayDealer())
+			default:
+				t.Fatalf("unexpected state %s", g.State)
+			}
+		}
+		payouts := g.CalculatePayouts()
+		if payouts["player2"] == 0 {
+			// player2 isn't owed anything this seed, so there's nothing to fail
+			continue
+		}
+
+		// A payout that fails leaves the round open with its chips at the table
+		wallets.broken = true
+		assert.Error(t, g.ProcessPayouts(ctx, wallets))
+		assert.False(t, g.PayoutsProcessed)
+		assert.Zero(t, wallets.settled)
+		for _, event := range g.Events {
+			assert.NotEqual(t, EventRoundComplete, event.Type)
+		}
+
+		// Trying again pays everyone once
+		wallets.broken = false
+		require.NoError(t, g.ProcessPayouts(ctx, wallets))
+		assert.True(t, g.PayoutsProcessed)
+		assert.Equal(t, 1, wallets.settled)
+		for _, playerID := range []string{"player1", "player2"} {
+			balance, err := service.GetBalance(ctx, playerID)
+			require.NoError(t, err)
+			assert.Equal(t, walletService.StartingBalance-10+payouts[playerID], balance, playerID)
+		}
+
+		reconciliation, err := service.Reconcile(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, reconciliation.Problems)
+		return
+	}
+	t.Fatal("player2 never won a round")
+}
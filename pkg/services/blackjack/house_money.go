@@ -0,0 +1,199 @@
+package blackjack
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// BotBankroll is the house money each computer player sits down with
+const BotBankroll int64 = 1000
+
+// HouseMoneyWallet keeps computer players' chips apart from the real wallets.
+// Computer players play with house money, which the house tops back up
+// whenever they run short, and every other player goes through to the wallet
+// service underneath.
+type HouseMoneyWallet struct {
+	WalletService
+
+	mu       sync.Mutex
+	balances map[string]int64            // Computer player ID -> house money in front of them
+	held     map[string]map[string]int64 // Round ID -> computer player ID -> house money held for the round
+}
+
+// NewHouseMoneyWallet wraps the real wallets with a bankroll for computer players
+func NewHouseMoneyWallet(wallets WalletService) *HouseMoneyWallet {
+	return &HouseMoneyWallet{
+		WalletService: wallets,
+		balances:      make(map[string]int64),
+		held:          make(map[string]map[string]int64),
+	}
+}
+
+// balance returns a computer player's chips, sitting them down with the
+// bankroll the first time. Callers hold h.mu.
+func (h *HouseMoneyWallet) balance(playerID string) (int64, bool) {
+	balance, exists := h.balances[playerID]
+	if !exists {
+		balance = BotBankroll
+		h.balances[playerID] = balance
+	}
+	return balance, !exists
+}
+
+// botWallet shows a computer player's house money as a wallet
+func botWallet(playerID string, balance int64) *entities.Wallet {
+	return &entities.Wallet{
+		UserID:      playerID,
+		Balance:     balance,
+		LastUpdated: time.Now(),
+	}
+}
+
+// GetOrCreateWallet returns a player's wallet, or a computer player's house money
+func (h *HouseMoneyWallet) GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error) {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.GetOrCreateWallet(ctx, userID)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	balance, created := h.balance(userID)
+	return botWallet(userID, balance), created, nil
+}
+
+// AddFunds pays a player, computer players are paid in house money
+func (h *HouseMoneyWallet) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.AddFunds(ctx, userID, tableID, amount, transactionType, referenceID, description)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	balance, _ := h.balance(userID)
+	h.balances[userID] = balance + amount
+	return nil
+}
+
+// EnsureFundsWithLoan makes sure a player can cover a stake. Computer players
+// never borrow, the house just tops them back up to their bankroll.
+func (h *HouseMoneyWallet) EnsureFundsWithLoan(ctx context.Context, userID string, requiredAmount int64, loanAmount int64) (*entities.Wallet, bool, error) {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.EnsureFundsWithLoan(ctx, userID, requiredAmount, loanAmount)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	balance, _ := h.balance(userID)
+	if balance < requiredAmount {
+		balance = max(BotBankroll, requiredAmount)
+		h.balances[userID] = balance
+	}
+	return botWallet(userID, balance), false, nil
+}
+
+// HoldFunds holds a player's stake for a round, computer players' house
+// money is held here
+func (h *HouseMoneyWallet) HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.HoldFunds(ctx, userID, tableID, roundID, amount, transactionType, referenceID, description)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	balance, _ := h.balance(userID)
+	if balance < amount {
+		return ErrInsufficientFundsForAction
+	}
+	h.balances[userID] = balance - amount
+	if h.held[roundID] == nil {
+		h.held[roundID] = make(map[string]int64)
+	}
+	h.held[roundID][userID] += amount
+	return nil
+}
+
+// CaptureHold hands part of a player's hold for a round over to the table
+func (h *HouseMoneyWallet) CaptureHold(ctx context.Context, userID, roundID string, amount int64) error {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.CaptureHold(ctx, userID, roundID, amount)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.held[roundID][userID] = max(h.held[roundID][userID]-amount, 0)
+	return nil
+}
+
+// CaptureRoundHolds hands everything still held for a round over to the table
+func (h *HouseMoneyWallet) CaptureRoundHolds(ctx context.Context, roundID string) error {
+	h.mu.Lock()
+	delete(h.held, roundID)
+	h.mu.Unlock()
+	return h.WalletService.CaptureRoundHolds(ctx, roundID)
+}
+
+// ReleaseHold gives a player back what's still held for them in a round
+func (h *HouseMoneyWallet) ReleaseHold(ctx context.Context, userID, roundID string) (int64, error) {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.ReleaseHold(ctx, userID, roundID)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	released := h.held[roundID][userID]
+	delete(h.held[roundID], userID)
+	balance, _ := h.balance(userID)
+	h.balances[userID] = balance + released
+	return released, nil
+}
+
+// ReleaseFromHold gives a player back part of their hold for a round
+func (h *HouseMoneyWallet) ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error {
+	if !IsBotPlayer(userID) {
+		return h.WalletService.ReleaseFromHold(ctx, userID, roundID, amount)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	released := min(amount, h.held[roundID][userID])
+	if released == 0 {
+		return nil
+	}
+	h.held[roundID][userID] -= released
+	balance, _ := h.balance(userID)
+	h.balances[userID] = balance + released
+	return nil
+}
+
+// ReleaseRoundHolds gives every player back what's still held for them in an abandoned round
+func (h *HouseMoneyWallet) ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error) {
+	released, err := h.WalletService.ReleaseRoundHolds(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	if released == nil {
+		released = make(map[string]int64)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for playerID, amount := range h.held[roundID] {
+		balance, _ := h.balance(playerID)
+		h.balances[playerID] = balance + amount
+		released[playerID] = amount
+	}
+	delete(h.held, roundID)
+	return released, nil
+}
+
+// CollectFromWinnings takes Tuco's cut of a player's winnings for an overdue
+// loan, computer players never borrow so there's nothing to collect from them
+func (h *HouseMoneyWallet) CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error) {
+	if IsBotPlayer(userID) {
+		return 0, nil
+	}
+	return h.WalletService.CollectFromWinnings(ctx, userID, winnings, referenceID)
+}
@@ -0,0 +1,103 @@
+package blackjack
+
+import (
+	"context"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// SideBetInsurance names insurance in events and records
+const SideBetInsurance = "insurance"
+
+// DefaultInsurancePays is what insurance pays to one at most tables
+const DefaultInsurancePays = 2
+
+// Insurance outcomes
+const (
+	InsuranceDealerBlackjack = "dealer_blackjack"
+	InsuranceNoBlackjack     = "no_blackjack"
+)
+
+// Insurance is a side bet that the dealer has blackjack, offered on each hand's
+// turn while the dealer shows an Ace
+type Insurance struct {
+	Pays int64 // What insurance pays to one
+}
+
+// Name identifies insurance in events and records
+func (i Insurance) Name() string {
+	return SideBetInsurance
+}
+
+// Label is what insurance is called at the table
+func (i Insurance) Label() string {
+	return "Insurance"
+}
+
+// Window has insurance offered on the hand's turn before the dealer peeks
+func (i Insurance) Window() SideBetWindow {
+	return SideBetWindowInsurance
+}
+
+// JudgedOn has insurance judged once the hole card is known
+func (i Insurance) JudgedOn() SideBetJudgement {
+	return JudgedOnHoleCard
+}
+
+// Limits lets a player insure up to half their main bet
+func (i Insurance) Limits(mainBet int64) (int64, int64) {
+	if mainBet/2 < 1 {
+		return 1, 1
+	}
+	return 1, mainBet / 2
+}
+
+// Describe returns whether the dealer had blackjack
+func (i Insurance) Describe(outcome string) string {
+	if outcome == InsuranceDealerBlackjack {
+		return "Dealer Blackjack"
+	}
+	return "No Dealer Blackjack"
+}
+
+// Evaluate pays insurance if the dealer's first two cards are a blackjack
+func (i Insurance) Evaluate(playerCards, dealerCards []*entities.Card, amount int64) (string, int64) {
+	if !IsBlackjack(dealerCards) {
+		return InsuranceNoBlackjack, 0
+	}
+	return InsuranceDealerBlackjack, amount + amount*i.Pays
+}
+
+// PlaceInsurance insures the current hand for half its bet
+func (g *Game) PlaceInsurance(ctx context.Context, playerID string, walletService WalletService) error {
+	// Validate game state
+	if !g.isTakingSpecialBets() {
+		return ErrInvalidAction
+	}
+
+	// Validate player turn, the current hand must belong to this player
+	handID, err := g.GetCurrentSpecialBetsPlayerID()
+	if err != nil {
+		return err
+	}
+	if g.HandOwner(handID) != playerID {
+		return ErrNotPlayerTurn
+	}
+
+	// Check if player is eligible for insurance
+	if !g.IsEligibleForInsurance() {
+		return ErrNotEligibleForInsurance
+	}
+
+	insurance, offered := g.registry.Lookup(SideBetInsurance)
+	if !offered {
+		return ErrNotEligibleForInsurance
+	}
+	_, insuranceAmount := insurance.Limits(g.Bets[handID])
+	return g.PlaceSideBet(ctx, SideBetInsurance, playerID, insuranceAmount, walletService)
+}
+
+// GetInsurance returns a hand's insurance bet, or nil if it isn't insured
+func (g *Game) GetInsurance(handID string) *PlacedSideBet {
+	return g.GetSideBet(handID, SideBetInsurance)
+}
@@ -0,0 +1,77 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsuranceIsPaidAsASideBet(t *testing.T) {
+	ctx := context.Background()
+	hard16 := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+
+	t.Run("dealer blackjack", func(t *testing.T) {
+		wallet := newStubWalletService()
+		g := newDealtGame(DefaultRuleSet(), entities.StateDealing, hard16, []*entities.Card{
+			{Rank: entities.Ace, Suit: entities.Clubs},
+			{Rank: entities.Jack, Suit: entities.Diamonds},
+		})
+		require.NoError(t, g.beginSpecialPhases())
+		require.NoError(t, g.PlaceInsurance(ctx, "player1", wallet))
+
+		assert.Equal(t, &PlacedSideBet{Type: SideBetInsurance, Amount: 50, Outcome: InsuranceDealerBlackjack, Payout: 150},
+			g.GetInsurance("player1"))
+		assert.Equal(t, "Insurance: Dealer Blackjack", g.DescribeSideBet(g.GetInsurance("player1")))
+
+		// The main bet is lost and the insurance pays 2:1 on top of the stake
+		require.NoError(t, g.ProcessPayouts(ctx, wallet))
+		assert.Equal(t, map[string]int64{"player1": 50}, wallet.removed)
+		assert.Equal(t, map[string]int64{"player1": 150}, wallet.added)
+		assert.True(t, g.GetInsurance("player1").Paid)
+	})
+
+	t.Run("no dealer blackjack", func(t *testing.T) {
+		wallet := newStubWalletService()
+		g := newDealtGame(DefaultRuleSet(), entities.StateDealing, hard16, []*entities.Card{
+			{Rank: entities.Ace, Suit: entities.Clubs},
+			{Rank: entities.Seven, Suit: entities.Diamonds},
+		})
+		require.NoError(t, g.beginSpecialPhases())
+		require.NoError(t, g.PlaceInsurance(ctx, "player1", wallet))
+
+		// The peek judges the insurance without turning the hole card over
+		assert.Equal(t, StateSpecialBets, g.State)
+		assert.Equal(t, InsuranceNoBlackjack, g.GetInsurance("player1").Outcome)
+		assert.Equal(t, int64(0), g.GetInsurance("player1").Payout)
+	})
+}
+
+func TestInsuranceLimits(t *testing.T) {
+	ctx := context.Background()
+	g := newDealtGame(DefaultRuleSet(), entities.StateDealing, []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}, []*entities.Card{
+		{Rank: entities.Ace, Suit: entities.Clubs},
+		{Rank: entities.Seven, Suit: entities.Diamonds},
+	})
+	require.NoError(t, g.beginSpecialPhases())
+	wallet := newStubWalletService()
+
+	// Insurance is up to half the main bet, and only on the player's own turn
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetInsurance, "player1", 60, wallet), ErrSideBetTooLarge)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetInsurance, "player2", 10, wallet), ErrNotPlayerTurn)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, "player1", 10, wallet), ErrInvalidAction)
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetInsurance, "player1", 20, wallet))
+	assert.Equal(t, int64(20), g.GetInsurance("player1").Amount)
+
+	// The bet was the hand's decision, so the dealer has peeked and moved on
+	assert.True(t, g.DealerPeeked)
+	assert.False(t, g.IsEligibleForInsurance())
+}
@@ -0,0 +1,143 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekEndsRoundOnDealerBlackjack(t *testing.T) {
+	// Eleven is a double down hand, the peek should stop the player from doubling into a blackjack
+	eleven := []*entities.Card{
+		{Rank: entities.Five, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+	g := newDealtGame(DefaultRuleSet(), entities.StateDealing, eleven, []*entities.Card{
+		{Rank: entities.King, Suit: entities.Clubs},
+		{Rank: entities.Ace, Suit: entities.Diamonds},
+	})
+
+	require.NoError(t, g.beginSpecialPhases())
+
+	assert.True(t, g.DealerPeeked)
+	assert.Equal(t, entities.StateComplete, g.State)
+
+	results, err := g.GetResults()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, entities.StringResultLose, results[0].Result)
+	assert.Equal(t, int64(100), results[0].Bet, "only the original bet is lost")
+	assert.False(t, results[0].IsDoubledDown)
+}
+
+func TestPeekWithoutBlackjackContinues(t *testing.T) {
+	eleven := []*entities.Card{
+		{Rank: entities.Five, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+	g := newDealtGame(DefaultRuleSet(), entities.StateDealing, eleven, []*entities.Card{
+		{Rank: entities.King, Suit: entities.Clubs},
+		{Rank: entities.Nine, Suit: entities.Diamonds},
+	})
+
+	require.NoError(t, g.beginSpecialPhases())
+
+	assert.True(t, g.DealerPeeked)
+	assert.Equal(t, StateSpecialBets, g.State)
+	assert.True(t, g.IsEligibleForDoubleDown("player1"))
+}
+
+func TestInsuranceIsDecidedBeforePeek(t *testing.T) {
+	hard16 := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+
+	t.Run("insurance pays when the dealer has blackjack", func(t *testing.T) {
+		g := newDealtGame(DefaultRuleSet(), entities.StateDealing, hard16, []*entities.Card{
+			{Rank: entities.Ace, Suit: entities.Clubs},
+			{Rank: entities.Queen, Suit: entities.Diamonds},
+		})
+
+		require.NoError(t, g.beginSpecialPhases())
+		require.Equal(t, StateInsurance, g.State)
+		assert.False(t, g.DealerPeeked)
+		assert.ErrorIs(t, g.DoubleDown(context.Background(), "player1", newStubWalletService()), ErrInvalidAction)
+
+		require.NoError(t, g.PlaceInsurance(context.Background(), "player1", newStubWalletService()))
+
+		assert.True(t, g.DealerPeeked)
+		assert.Equal(t, entities.StateComplete, g.State)
+
+		results, err := g.GetResults()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, entities.StringResultLose, results[0].Result)
+		assert.Equal(t, int64(50), results[0].InsuranceBet)
+		// Insurance pays 2:1, and the stake comes back with it
+		assert.Equal(t, int64(150), results[0].InsurancePayout)
+	})
+
+	t.Run("insurance is not offered again after a clean peek", func(t *testing.T) {
+		g := newDealtGame(DefaultRuleSet(), entities.StateDealing, hard16, []*entities.Card{
+			{Rank: entities.Ace, Suit: entities.Clubs},
+			{Rank: entities.Seven, Suit: entities.Diamonds},
+		})
+
+		require.NoError(t, g.beginSpecialPhases())
+		require.Equal(t, StateInsurance, g.State)
+		require.NoError(t, g.DeclineSpecialBet("player1"))
+
+		assert.True(t, g.DealerPeeked)
+		assert.Equal(t, StateSpecialBets, g.State)
+		assert.False(t, g.IsEligibleForInsurance())
+	})
+}
+
+func TestEarlySurrenderBeforePeek(t *testing.T) {
+	hard16 := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+	dealerBlackjack := []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Ace, Suit: entities.Diamonds},
+	}
+
+	// With late surrender nothing is decided before a ten gets peeked
+	late := newDealtGame(DefaultRuleSet(), entities.StateDealing, hard16, dealerBlackjack)
+	require.NoError(t, late.beginSpecialPhases())
+	assert.Equal(t, entities.StateComplete, late.State)
+
+	rules := DefaultRuleSet()
+	rules.Surrender = SurrenderEarly
+	g := newDealtGame(rules, entities.StateDealing, hard16, dealerBlackjack)
+
+	require.NoError(t, g.beginSpecialPhases())
+	require.Equal(t, StateInsurance, g.State)
+	require.NoError(t, g.Surrender("player1"))
+	assert.Equal(t, entities.StateComplete, g.State)
+
+	results, err := g.GetResults()
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, entities.StringResultSurrender, results[0].Result)
+	assert.Equal(t, int64(50), results[0].Payout)
+}
+
+func TestNoPeekWithoutHoleCard(t *testing.T) {
+	g := newDealtGame(EuropeanRules(), entities.StateDealing, []*entities.Card{
+		{Rank: entities.Five, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}, []*entities.Card{
+		{Rank: entities.Ace, Suit: entities.Clubs},
+	})
+
+	assert.False(t, g.ShouldPeek())
+	require.NoError(t, g.beginSpecialPhases())
+	assert.False(t, g.DealerPeeked)
+	assert.Equal(t, StateSpecialBets, g.State)
+}
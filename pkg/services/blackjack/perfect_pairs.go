@@ -0,0 +1,138 @@
+package blackjack
+
+import (
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// SideBetPerfectPairs names the Perfect Pairs side bet in events and records
+const SideBetPerfectPairs = "perfect_pairs"
+
+// PairType is how a player's first two cards pair up for Perfect Pairs
+type PairType string
+
+const (
+	PairNone    PairType = "none"
+	PairMixed   PairType = "mixed_pair"   // Same rank, one red and one black
+	PairColored PairType = "colored_pair" // Same rank and color, different suits
+	PairPerfect PairType = "perfect_pair" // Same rank and suit
+)
+
+// String returns the pair as Tuco calls it at the table
+func (p PairType) String() string {
+	switch p {
+	case PairMixed:
+		return "Mixed Pair"
+	case PairColored:
+		return "Colored Pair"
+	case PairPerfect:
+		return "Perfect Pair"
+	}
+	return "No Pair"
+}
+
+// PairPayTable maps each kind of pair to what it pays to one
+type PairPayTable map[PairType]int64
+
+// DefaultPerfectPairsPays returns the Perfect Pairs pay table Tuco uses
+func DefaultPerfectPairsPays() PairPayTable {
+	return PairPayTable{
+		PairPerfect: 25,
+		PairColored: 12,
+		PairMixed:   6,
+	}
+}
+
+// ParsePairPayTable reads a Perfect Pairs pay table as comma separated
+// pair=pays entries, e.g. "perfect=30,colored=10,mixed=5". Pairs left out pay nothing.
+func ParsePairPayTable(spec string) (PairPayTable, error) {
+	pays, err := parsePayTable(spec, func(name string) (string, bool) {
+		switch name {
+		case "perfect", string(PairPerfect):
+			return string(PairPerfect), true
+		case "colored", string(PairColored):
+			return string(PairColored), true
+		case "mixed", string(PairMixed):
+			return string(PairMixed), true
+		}
+		return "", false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	table := make(PairPayTable, len(pays))
+	for pair, amount := range pays {
+		table[PairType(pair)] = amount
+	}
+	return table, nil
+}
+
+// isRed returns true for hearts and diamonds
+func isRed(suit entities.Suit) bool {
+	return suit == entities.Hearts || suit == entities.Diamonds
+}
+
+// EvaluatePair judges two cards for Perfect Pairs. Only cards of the same rank
+// pair up, a Ten and a King don't.
+func EvaluatePair(first, second *entities.Card) PairType {
+	switch {
+	case first.Rank != second.Rank:
+		return PairNone
+	case first.Suit == second.Suit:
+		return PairPerfect
+	case isRed(first.Suit) == isRed(second.Suit):
+		return PairColored
+	}
+	return PairMixed
+}
+
+// PerfectPairs is the Perfect Pairs side bet, paid when the player's first two
+// cards are a pair
+type PerfectPairs struct {
+	Pays   PairPayTable // What each kind of pair pays to one
+	MinBet int64        // Smallest side bet taken
+	MaxBet int64        // Largest side bet taken, 0 for no limit
+}
+
+// Name identifies Perfect Pairs in events and records
+func (p PerfectPairs) Name() string {
+	return SideBetPerfectPairs
+}
+
+// Label is what Perfect Pairs is called at the table
+func (p PerfectPairs) Label() string {
+	return "Perfect Pairs"
+}
+
+// Describe returns the pair a Perfect Pairs bet made
+func (p PerfectPairs) Describe(outcome string) string {
+	return PairType(outcome).String()
+}
+
+// Window has Perfect Pairs placed before the main bet
+func (p PerfectPairs) Window() SideBetWindow {
+	return SideBetWindowBetting
+}
+
+// JudgedOn has Perfect Pairs judged as soon as the cards are dealt
+func (p PerfectPairs) JudgedOn() SideBetJudgement {
+	return JudgedOnDeal
+}
+
+// Limits returns the table's Perfect Pairs limits, whatever the main bet
+func (p PerfectPairs) Limits(mainBet int64) (int64, int64) {
+	return p.MinBet, p.MaxBet
+}
+
+// Evaluate judges a Perfect Pairs bet on the player's first two cards, the dealer's don't count
+func (p PerfectPairs) Evaluate(playerCards, dealerCards []*entities.Card, amount int64) (string, int64) {
+	if len(playerCards) < 2 {
+		return string(PairNone), 0
+	}
+
+	pair := EvaluatePair(playerCards[0], playerCards[1])
+	if pays, wins := p.Pays[pair]; wins {
+		return string(pair), amount + amount*pays
+	}
+	return string(pair), 0
+}
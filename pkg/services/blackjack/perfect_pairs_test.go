@@ -0,0 +1,50 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatePair(t *testing.T) {
+	tests := []struct {
+		name          string
+		first, second entities.Card
+		want          PairType
+	}{
+		{"perfect pair", entities.Card{Rank: entities.Eight, Suit: entities.Spades}, entities.Card{Rank: entities.Eight, Suit: entities.Spades}, PairPerfect},
+		{"colored pair", entities.Card{Rank: entities.Queen, Suit: entities.Hearts}, entities.Card{Rank: entities.Queen, Suit: entities.Diamonds}, PairColored},
+		{"mixed pair", entities.Card{Rank: entities.Ace, Suit: entities.Clubs}, entities.Card{Rank: entities.Ace, Suit: entities.Hearts}, PairMixed},
+		{"ten values aren't a pair", entities.Card{Rank: entities.Ten, Suit: entities.Clubs}, entities.Card{Rank: entities.King, Suit: entities.Clubs}, PairNone},
+		{"no pair", entities.Card{Rank: entities.Two, Suit: entities.Clubs}, entities.Card{Rank: entities.Nine, Suit: entities.Clubs}, PairNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EvaluatePair(&tt.first, &tt.second))
+		})
+	}
+}
+
+func TestParsePairPayTable(t *testing.T) {
+	pays, err := ParsePairPayTable("perfect=30, colored=10,mixed_pair=5")
+	require.NoError(t, err)
+	assert.Equal(t, PairPayTable{PairPerfect: 30, PairColored: 10, PairMixed: 5}, pays)
+
+	// Pairs left out pay nothing
+	pays, err = ParsePairPayTable("perfect=30")
+	require.NoError(t, err)
+	outcome, payout := PerfectPairs{Pays: pays}.Evaluate([]*entities.Card{
+		{Rank: entities.Ace, Suit: entities.Clubs},
+		{Rank: entities.Ace, Suit: entities.Hearts},
+	}, nil, 10)
+	assert.Equal(t, string(PairMixed), outcome)
+	assert.Equal(t, int64(0), payout)
+
+	for _, spec := range []string{"", "perfect", "royal=10", "mixed=-1", "colored=lots"} {
+		_, err := ParsePairPayTable(spec)
+		assert.ErrorIs(t, err, ErrInvalidPayTable, spec)
+	}
+}
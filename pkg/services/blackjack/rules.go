@@ -7,9 +7,9 @@ import (
 )
 
 const (
-	StandardDecks      = 6  // Standard number of decks in the shoe
-	ReshuffleThreshold = 75 // Reshuffle when 75 cards remain (~25% of one shoe)
-	MaxPlayers         = 7  // Max number of players allowed in a blackjack game
+	StandardDecks       = 6    // Standard number of decks in the shoe
+	StandardPenetration = 0.75 // The cut card goes in around 75% of the way into the shoe
+	MaxPlayers          = 7    // Max number of players allowed in a blackjack game
 )
 
 // Result represents the outcome of a blackjack hand
@@ -20,6 +20,7 @@ const (
 	ResultLose      Result = "LOSE"
 	ResultPush      Result = "PUSH"
 	ResultBlackjack Result = "BLACKJACK"
+	ResultSurrender Result = "SURRENDER"
 )
 
 // String returns the string representation of the result
@@ -75,6 +76,21 @@ func GetBestScore(cards []*entities.Card) int {
 	return score
 }
 
+// IsSoftHand returns true if the hand contains an ace currently counted as 11
+func IsSoftHand(cards []*entities.Card) bool {
+	hardScore := 0
+	hasAce := false
+	for _, card := range cards {
+		if IsAce(card) {
+			hasAce = true
+			hardScore++
+		} else {
+			hardScore += GetCardValue(card)
+		}
+	}
+	return hasAce && hardScore+10 <= 21
+}
+
 func IsBlackjack(cards []*entities.Card) bool {
 	return len(cards) == 2 && GetBestScore(cards) == 21
 }
@@ -122,20 +138,43 @@ func CompareHands(hand1, hand2 []*entities.Card) int {
 	return 0
 }
 
-// NewBlackjackDeck creates a new shuffled shoe with a standard number of decks
-func NewBlackjackDeck() *entities.Deck {
+// NewBlackjackDeck creates a new shoe with the given number of decks, shuffled
+// by the given shuffler. A nil shuffler uses a cryptographically secure one.
+func NewBlackjackDeck(decks int, shuffler entities.Shuffler) *entities.Deck {
+	if shuffler == nil {
+		shuffler = entities.NewCryptoShuffler()
+	}
+
+	deck := newUnshuffledShoe(decks)
+	deck.ShuffleWith(shuffler)
+	return deck
+}
+
+// ReplayShoe rebuilds a shoe from its seed and draws the cards dealt before the
+// given position, so the shoe deals exactly as it did in the recorded round
+func ReplayShoe(decks int, seed entities.Seed, position int) *entities.Deck {
+	deck := newUnshuffledShoe(decks)
+	entities.ShuffleWithSeed(deck.Cards, seed)
+	deck.Seed = seed
+
+	for i := 0; i < position; i++ {
+		deck.Draw()
+	}
+	return deck
+}
+
+// newUnshuffledShoe returns the given number of decks stacked in order
+func newUnshuffledShoe(decks int) *entities.Deck {
+	if decks < 1 {
+		decks = StandardDecks
+	}
+
 	deck := entities.NewDeck()
 
-	// Add more decks to meet the StandardDecks size definition
-	for i := 1; i < StandardDecks; i++ {
+	// Add more decks to meet the requested shoe size
+	for i := 1; i < decks; i++ {
 		deck.Cards = append(deck.Cards, entities.NewDeck().Cards...)
 	}
 
-	deck.Shuffle()
 	return deck
 }
-
-// ShouldReshuffle checks if the deck should be reshuffled based on the remaining cards
-func ShouldReshuffle(deck *entities.Deck) bool {
-	return len(deck.Cards) < ReshuffleThreshold
-}
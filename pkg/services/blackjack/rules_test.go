@@ -0,0 +1,63 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeededShufflerIsRepeatable(t *testing.T) {
+	seed := entities.Seed{42}
+
+	first := NewBlackjackDeck(StandardDecks, entities.NewSeededShuffler(seed))
+	second := NewBlackjackDeck(StandardDecks, entities.NewSeededShuffler(seed))
+	assert.Equal(t, first.Cards, second.Cards)
+	assert.Equal(t, first.Seed, second.Seed)
+
+	other := NewBlackjackDeck(StandardDecks, entities.NewSeededShuffler(entities.Seed{43}))
+	assert.NotEqual(t, first.Cards, other.Cards)
+
+	parsed, err := entities.ParseSeed(first.Seed.String())
+	require.NoError(t, err)
+	assert.Equal(t, first.Seed, parsed)
+}
+
+// dealRound plays a round up to the initial deal on a shared repository
+func dealRound(t *testing.T, repo game.Repository, shuffler entities.Shuffler) *Game {
+	g := NewGame("test-channel", repo, DefaultRuleSet(), shuffler)
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.AddPlayer("player2"))
+	require.NoError(t, g.Start())
+	// The last bet deals the cards
+	for _, playerID := range g.PlayerOrder {
+		require.NoError(t, g.PlaceBet(playerID, 10))
+	}
+	require.NotEqual(t, entities.StateBetting, g.State)
+	return g
+}
+
+func TestReplayShoeDealsRoundAgain(t *testing.T) {
+	repo := game.NewMemoryRepository()
+	shuffler := entities.NewSeededShuffler(entities.Seed{7})
+
+	first := dealRound(t, repo, shuffler)
+	assert.Equal(t, 1, first.ShoePosition, "the burn card comes off first")
+	assert.False(t, first.ShoeSeed.IsZero())
+
+	// The second round continues the same shoe
+	second := dealRound(t, repo, shuffler)
+	assert.Equal(t, first.ShoeSeed, second.ShoeSeed)
+	assert.Equal(t, 7, second.ShoePosition)
+
+	// Rebuild the shoe from the record and deal it the same way
+	shoe := ReplayShoe(second.Rules.Decks, second.ShoeSeed, second.ShoePosition)
+	for i := 0; i < 2; i++ {
+		for _, playerID := range second.PlayerOrder {
+			assert.Equal(t, second.Players[playerID].Cards[i], shoe.Draw())
+		}
+		assert.Equal(t, second.Dealer.Cards[i], shoe.Draw())
+	}
+}
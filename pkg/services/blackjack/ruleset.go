@@ -0,0 +1,345 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/strategy"
+)
+
+// Rule set errors
+var (
+	ErrUnknownRuleSet   = errors.New("unknown rule set")
+	ErrInvalidRuleSet   = errors.New("invalid rule set")
+	ErrInvalidBetLimits = errors.New("invalid bet limits")
+)
+
+// Table bet limit errors
+var (
+	ErrBetBelowMinimum = errors.New("bet is under the table minimum")
+	ErrBetAboveMaximum = errors.New("bet is over the table maximum")
+	ErrBetIncrement    = errors.New("bet isn't in the table's increments")
+)
+
+// Preset rule set names
+const (
+	RuleSetHouse        = "house"
+	RuleSetVegasStrip   = "vegas_strip"
+	RuleSetAtlanticCity = "atlantic_city"
+	RuleSetEuropean     = "european"
+)
+
+// SurrenderRule controls whether and when a player may surrender their hand
+type SurrenderRule string
+
+const (
+	SurrenderNone  SurrenderRule = "none"  // Surrender is not offered
+	SurrenderLate  SurrenderRule = "late"  // Surrender only counts if the dealer doesn't have blackjack
+	SurrenderEarly SurrenderRule = "early" // Surrender counts even against a dealer blackjack
+)
+
+// PayoutRatio represents how much a winning wager pays, e.g. 3:2
+type PayoutRatio struct {
+	Numerator   int64
+	Denominator int64
+}
+
+// Common blackjack payout ratios
+var (
+	PayoutThreeToTwo = PayoutRatio{Numerator: 3, Denominator: 2}
+	PayoutSixToFive  = PayoutRatio{Numerator: 6, Denominator: 5}
+	PayoutEvenMoney  = PayoutRatio{Numerator: 1, Denominator: 1}
+)
+
+// Winnings returns the amount won on a bet, not including the original stake
+func (p PayoutRatio) Winnings(bet int64) int64 {
+	if p.Denominator <= 0 {
+		return 0
+	}
+	return bet * p.Numerator / p.Denominator
+}
+
+// String returns the ratio in the usual "3:2" format
+func (p PayoutRatio) String() string {
+	return fmt.Sprintf("%d:%d", p.Numerator, p.Denominator)
+}
+
+// RuleSet describes the rules a blackjack table is played under
+type RuleSet struct {
+	Name             string        // Preset name, if any
+	Decks            int           // Number of decks in the shoe
+	MaxPlayers       int           // Max number of players at the table
+	DealerHitsSoft17 bool          // H17 when true, S17 when false
+	DoubleAfterSplit bool          // Whether split hands may double down
+	NoHoleCard       bool          // European style: dealer takes the second card after the players act
+	DealerPeeks      bool          // American style: dealer checks the hole card for blackjack under an Ace or ten
+	BlackjackPayout  PayoutRatio   // Payout for a natural blackjack
+	Surrender        SurrenderRule // When players may surrender half their bet
+	MaxSplitHands    int           // Max hands a player can split into, 1 disables splitting
+	ResplitAces      bool          // Whether split aces that pair up again may be re-split
+	HitSplitAces     bool          // Whether split aces may draw more than one card each
+	SplitAnyTens     bool          // Whether any two ten-value cards may be split, e.g. K-Q
+	Penetration      float64       // Fraction of the shoe dealt before the cut card comes out
+	BurnCard         bool          // Whether the first card of a new shoe is discarded
+	MinBet           int64         // Smallest main bet taken, 0 for no minimum
+	MaxBet           int64         // Largest main bet taken, 0 for no maximum
+	BetIncrement     int64         // Main bets must be a multiple of this, 0 for any amount
+}
+
+// DefaultRuleSet returns Tuco's house rules
+func DefaultRuleSet() RuleSet {
+	return RuleSet{
+		Name:             RuleSetHouse,
+		Decks:            StandardDecks,
+		MaxPlayers:       MaxPlayers,
+		DealerHitsSoft17: false,
+		DoubleAfterSplit: false,
+		NoHoleCard:       false,
+		DealerPeeks:      true,
+		BlackjackPayout:  PayoutThreeToTwo,
+		Surrender:        SurrenderNone,
+		MaxSplitHands:    4,
+		ResplitAces:      false,
+		HitSplitAces:     false,
+		SplitAnyTens:     false,
+		Penetration:      StandardPenetration,
+		BurnCard:         true,
+		MinBet:           5,
+		MaxBet:           500,
+		BetIncrement:     5,
+	}
+}
+
+// AllowsSurrender returns true if players may surrender at this table
+func (r RuleSet) AllowsSurrender() bool {
+	return r.Surrender == SurrenderLate || r.Surrender == SurrenderEarly
+}
+
+// StrategyRules returns the rules basic strategy depends on
+func (r RuleSet) StrategyRules() strategy.Rules {
+	return strategy.Rules{
+		Decks:            r.Decks,
+		DealerHitsSoft17: r.DealerHitsSoft17,
+		DoubleAfterSplit: r.DoubleAfterSplit,
+		Surrender:        r.AllowsSurrender(),
+		EarlySurrender:   r.Surrender == SurrenderEarly,
+	}
+}
+
+// VegasStripRules returns the classic Las Vegas Strip rules
+func VegasStripRules() RuleSet {
+	rules := DefaultRuleSet()
+	rules.Name = RuleSetVegasStrip
+	rules.Decks = 4
+	rules.DoubleAfterSplit = true
+	rules.Surrender = SurrenderLate
+	return rules
+}
+
+// AtlanticCityRules returns the standard Atlantic City rules
+func AtlanticCityRules() RuleSet {
+	rules := DefaultRuleSet()
+	rules.Name = RuleSetAtlanticCity
+	rules.Decks = 8
+	rules.DoubleAfterSplit = true
+	rules.Surrender = SurrenderLate
+	rules.Penetration = 0.8
+	return rules
+}
+
+// EuropeanRules returns European no-hole-card rules
+func EuropeanRules() RuleSet {
+	rules := DefaultRuleSet()
+	rules.Name = RuleSetEuropean
+	rules.Decks = 6
+	rules.DoubleAfterSplit = true
+	rules.NoHoleCard = true
+	rules.DealerPeeks = false
+	rules.Surrender = SurrenderNone
+	rules.MaxSplitHands = 2
+	return rules
+}
+
+// RuleSetByName returns the preset rule set with the given name
+func RuleSetByName(name string) (RuleSet, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", RuleSetHouse:
+		return DefaultRuleSet(), nil
+	case RuleSetVegasStrip:
+		return VegasStripRules(), nil
+	case RuleSetAtlanticCity:
+		return AtlanticCityRules(), nil
+	case RuleSetEuropean:
+		return EuropeanRules(), nil
+	default:
+		return RuleSet{}, fmt.Errorf("%w: %s", ErrUnknownRuleSet, name)
+	}
+}
+
+// Validate checks that the rule set describes a playable table
+func (r RuleSet) Validate() error {
+	if r.Decks < 1 || r.Decks > 8 {
+		return fmt.Errorf("%w: deck count must be between 1 and 8", ErrInvalidRuleSet)
+	}
+	if r.MaxPlayers < 1 || r.MaxPlayers > MaxPlayers {
+		return fmt.Errorf("%w: max players must be between 1 and %d", ErrInvalidRuleSet, MaxPlayers)
+	}
+	if r.BlackjackPayout.Numerator <= 0 || r.BlackjackPayout.Denominator <= 0 {
+		return fmt.Errorf("%w: blackjack payout must be positive", ErrInvalidRuleSet)
+	}
+	if r.MaxSplitHands < 1 || r.MaxSplitHands > 8 {
+		return fmt.Errorf("%w: max split hands must be between 1 and 8", ErrInvalidRuleSet)
+	}
+	if r.DealerPeeks && r.NoHoleCard {
+		return fmt.Errorf("%w: the dealer can't peek without a hole card", ErrInvalidRuleSet)
+	}
+	switch r.Surrender {
+	case "", SurrenderNone, SurrenderLate, SurrenderEarly:
+	default:
+		return fmt.Errorf("%w: unknown surrender rule %q", ErrInvalidRuleSet, r.Surrender)
+	}
+	if r.Penetration <= 0 || r.Penetration >= 1 {
+		return fmt.Errorf("%w: penetration must be between 0 and 1", ErrInvalidRuleSet)
+	}
+	if r.ReshuffleThreshold() < r.CardsReserved() {
+		return fmt.Errorf("%w: penetration leaves fewer than the %d cards a full table needs behind the cut card", ErrInvalidRuleSet, r.CardsReserved())
+	}
+	if r.MinBet < 0 || r.MaxBet < 0 || r.BetIncrement < 0 {
+		return fmt.Errorf("%w: bet limits can't be negative", ErrInvalidRuleSet)
+	}
+	if r.MaxBet > 0 && r.MaxBet < r.MinBet {
+		return fmt.Errorf("%w: maximum bet is under the minimum", ErrInvalidRuleSet)
+	}
+	if r.BetIncrement > 0 && (r.MinBet%r.BetIncrement != 0 || r.MaxBet%r.BetIncrement != 0) {
+		return fmt.Errorf("%w: bet limits must be multiples of the increment", ErrInvalidRuleSet)
+	}
+	return nil
+}
+
+// CheckBet checks a main bet against the table's limits
+func (r RuleSet) CheckBet(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidBet
+	}
+	if amount < r.MinBet {
+		return fmt.Errorf("%w of $%d", ErrBetBelowMinimum, r.MinBet)
+	}
+	if r.MaxBet > 0 && amount > r.MaxBet {
+		return fmt.Errorf("%w of $%d", ErrBetAboveMaximum, r.MaxBet)
+	}
+	if r.BetIncrement > 0 && amount%r.BetIncrement != 0 {
+		return fmt.Errorf("%w of $%d", ErrBetIncrement, r.BetIncrement)
+	}
+	return nil
+}
+
+// WithBetLimits returns the rules with the bet limits in spec, written as
+// min-max with an optional /increment, e.g. "10-500/5". A maximum of 0 means
+// no maximum.
+func (r RuleSet) WithBetLimits(spec string) (RuleSet, error) {
+	limits, increment, hasIncrement := strings.Cut(strings.TrimSpace(spec), "/")
+	minimum, maximum, found := strings.Cut(limits, "-")
+	if !found {
+		return r, fmt.Errorf("%w: expected min-max, got %q", ErrInvalidBetLimits, spec)
+	}
+
+	var err error
+	if r.MinBet, err = strconv.ParseInt(strings.TrimSpace(minimum), 10, 64); err != nil {
+		return r, fmt.Errorf("%w: bad minimum %q", ErrInvalidBetLimits, minimum)
+	}
+	if r.MaxBet, err = strconv.ParseInt(strings.TrimSpace(maximum), 10, 64); err != nil {
+		return r, fmt.Errorf("%w: bad maximum %q", ErrInvalidBetLimits, maximum)
+	}
+	r.BetIncrement = 0
+	if hasIncrement {
+		if r.BetIncrement, err = strconv.ParseInt(strings.TrimSpace(increment), 10, 64); err != nil {
+			return r, fmt.Errorf("%w: bad increment %q", ErrInvalidBetLimits, increment)
+		}
+	}
+
+	if err := r.Validate(); err != nil {
+		return r, fmt.Errorf("%w: %v", ErrInvalidBetLimits, err)
+	}
+	return r, nil
+}
+
+// LimitsSummary returns the table's bet limits the way they're posted at the table, e.g. "$5 - $500"
+func (r RuleSet) LimitsSummary() string {
+	maximum := "no limit"
+	if r.MaxBet > 0 {
+		maximum = fmt.Sprintf("$%d", r.MaxBet)
+	}
+	summary := fmt.Sprintf("$%d - %s", r.MinBet, maximum)
+	if r.BetIncrement > 1 {
+		summary += fmt.Sprintf(" in $%d steps", r.BetIncrement)
+	}
+	return summary
+}
+
+// ShoeSize returns the number of cards in a full shoe
+func (r RuleSet) ShoeSize() int {
+	return r.Decks * 52
+}
+
+// CardsReserved returns the cards the cut card always leaves behind it, enough
+// to finish a round at a full table. Every seat counts towards MaxPlayers, so
+// that's a hand for each seat, one of them split as far as the rules allow,
+// and the dealer's hand, at ReserveCardsPerHand cards each.
+func (r RuleSet) CardsReserved() int {
+	hands := r.MaxPlayers + (r.MaxSplitHands - 1) + 1
+	return hands * ReserveCardsPerHand
+}
+
+// ReshuffleThreshold returns the number of remaining cards at which the shoe is reshuffled
+func (r RuleSet) ReshuffleThreshold() int {
+	return r.ShoeSize() - int(float64(r.ShoeSize())*r.Penetration)
+}
+
+// DealerShouldHit returns true if the dealer must draw another card
+func (r RuleSet) DealerShouldHit(cards []*entities.Card) bool {
+	score := GetBestScore(cards)
+	if score < 17 {
+		return true
+	}
+	return score == 17 && r.DealerHitsSoft17 && IsSoftHand(cards)
+}
+
+// Summary returns a short human-readable description of the rules
+func (r RuleSet) Summary() string {
+	parts := []string{fmt.Sprintf("%d decks", r.Decks)}
+	if r.DealerHitsSoft17 {
+		parts = append(parts, "Dealer hits soft 17")
+	} else {
+		parts = append(parts, "Dealer stands on soft 17")
+	}
+	parts = append(parts, fmt.Sprintf("Blackjack pays %s", r.BlackjackPayout))
+	if r.DoubleAfterSplit {
+		parts = append(parts, "Double after split")
+	}
+	if r.NoHoleCard {
+		parts = append(parts, "No hole card")
+	}
+	if r.DealerPeeks {
+		parts = append(parts, "Dealer peeks")
+	}
+	if r.MaxSplitHands > 2 {
+		parts = append(parts, fmt.Sprintf("Split to %d hands", r.MaxSplitHands))
+	}
+	if r.BurnCard {
+		parts = append(parts, "Burn card")
+	}
+	if r.ResplitAces {
+		parts = append(parts, "Re-split aces")
+	}
+	switch r.Surrender {
+	case SurrenderLate:
+		parts = append(parts, "Late surrender")
+	case SurrenderEarly:
+		parts = append(parts, "Early surrender")
+	}
+	parts = append(parts, "Bets "+r.LimitsSummary())
+	return strings.Join(parts, " · ")
+}
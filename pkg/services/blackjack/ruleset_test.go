@@ -0,0 +1,106 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayoutRatioWinnings(t *testing.T) {
+	assert.Equal(t, int64(15), PayoutThreeToTwo.Winnings(10))
+	assert.Equal(t, int64(7), PayoutThreeToTwo.Winnings(5)) // Rounds down like the table does
+	assert.Equal(t, int64(12), PayoutSixToFive.Winnings(10))
+	assert.Equal(t, int64(0), PayoutRatio{}.Winnings(10))
+}
+
+func TestDealerShouldHitSoft17(t *testing.T) {
+	soft17 := []*entities.Card{
+		{Rank: entities.Ace, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+	hard17 := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Seven, Suit: entities.Hearts},
+	}
+
+	s17 := DefaultRuleSet()
+	h17 := DefaultRuleSet()
+	h17.DealerHitsSoft17 = true
+
+	assert.False(t, s17.DealerShouldHit(soft17))
+	assert.True(t, h17.DealerShouldHit(soft17))
+	assert.False(t, s17.DealerShouldHit(hard17))
+	assert.False(t, h17.DealerShouldHit(hard17))
+}
+
+func TestRuleSetByName(t *testing.T) {
+	for _, name := range []string{RuleSetHouse, RuleSetVegasStrip, RuleSetAtlanticCity, RuleSetEuropean} {
+		rules, err := RuleSetByName(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name, rules.Name)
+		assert.NoError(t, rules.Validate())
+	}
+
+	_, err := RuleSetByName("tijuana")
+	assert.ErrorIs(t, err, ErrUnknownRuleSet)
+}
+
+func TestShouldReshuffle(t *testing.T) {
+	rules := DefaultRuleSet()
+
+	assert.False(t, ShouldReshuffle(NewBlackjackDeck(rules.Decks, nil), rules))
+	assert.True(t, ShouldReshuffle(entities.NewDeck(), rules))
+	assert.True(t, ShouldReshuffle(NewBlackjackDeck(8, nil), rules), "a shoe with the wrong deck count should be replaced")
+}
+
+func TestCheckBet(t *testing.T) {
+	rules := DefaultRuleSet()
+
+	assert.NoError(t, rules.CheckBet(5))
+	assert.NoError(t, rules.CheckBet(500))
+	assert.ErrorIs(t, rules.CheckBet(0), ErrInvalidBet)
+	assert.ErrorIs(t, rules.CheckBet(1), ErrBetBelowMinimum)
+	assert.ErrorIs(t, rules.CheckBet(505), ErrBetAboveMaximum)
+	assert.ErrorIs(t, rules.CheckBet(12), ErrBetIncrement)
+
+	// A table without limits takes any amount
+	assert.NoError(t, RuleSet{}.CheckBet(12345))
+}
+
+func TestWithBetLimits(t *testing.T) {
+	rules, err := DefaultRuleSet().WithBetLimits("25-1000/25")
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), rules.MinBet)
+	assert.Equal(t, int64(1000), rules.MaxBet)
+	assert.Equal(t, int64(25), rules.BetIncrement)
+	assert.Equal(t, "$25 - $1000 in $25 steps", rules.LimitsSummary())
+
+	rules, err = DefaultRuleSet().WithBetLimits("1-0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), rules.BetIncrement)
+	assert.Equal(t, "$1 - no limit", rules.LimitsSummary())
+
+	for _, spec := range []string{"", "25", "a-100", "100-10", "10-100/3", "10-100/x"} {
+		_, err := DefaultRuleSet().WithBetLimits(spec)
+		assert.ErrorIs(t, err, ErrInvalidBetLimits, spec)
+	}
+}
+
+func TestPlaceBetEnforcesTableLimits(t *testing.T) {
+	g := newBettingGame()
+	// A third seat keeps the round from dealing once both bets are in
+	g.Players["player3"] = NewHand()
+	g.PlayerOrder = append(g.PlayerOrder, "player3")
+
+	assert.ErrorIs(t, g.PlaceBet("player1", 1000), ErrBetAboveMaximum)
+	assert.ErrorIs(t, g.PlaceBet("player1", 7), ErrBetIncrement)
+	assert.Empty(t, g.Bets)
+	require.NoError(t, g.PlaceBet("player1", 15))
+
+	// Rounds played back keep the bets they were dealt with
+	g.replaying = true
+	require.NoError(t, g.PlaceBet("player2", 7))
+	assert.Equal(t, map[string]int64{"player1": 15, "player2": 7}, g.Bets)
+}
@@ -0,0 +1,185 @@
+package blackjack
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+var ErrMaxSeatsReached = errors.New("player already plays the most seats allowed")
+
+// MaxSeatsPerPlayer is how many seats (boxes) one player may play at a table
+const MaxSeatsPerPlayer = 3
+
+// seatSeparator joins a player's ID and a seat number into the hand ID of an extra seat
+const seatSeparator = "#"
+
+// SeatID returns the hand ID of a player's seat, numbered from 1. The first
+// seat's hand is the player's own ID, so a player with one seat is played
+// exactly as before.
+func SeatID(playerID string, seat int) string {
+	if seat <= 1 {
+		return playerID
+	}
+	return fmt.Sprintf("%s%s%d", playerID, seatSeparator, seat)
+}
+
+// parseSeatID splits the hand ID of an extra seat into its player and seat
+// number, or returns false if the ID isn't one
+func parseSeatID(seatID string) (string, int, bool) {
+	index := strings.LastIndex(seatID, seatSeparator)
+	if index <= 0 {
+		return "", 0, false
+	}
+	seat, err := strconv.Atoi(seatID[index+len(seatSeparator):])
+	if err != nil || seat < 2 {
+		return "", 0, false
+	}
+	return seatID[:index], seat, true
+}
+
+// AddSeat sits a player who's already at the table down in another seat,
+// which takes its own bet and is played as its own hand. It returns the new
+// seat's hand ID. Every seat counts towards the table's MaxPlayers.
+func (g *Game) AddSeat(playerID string) (string, error) {
+	if g.State != entities.StateWaiting {
+		return "", ErrGameInProgress
+	}
+	if _, exists := g.Players[playerID]; !exists {
+		return "", ErrPlayerNotFound
+	}
+
+	seats := g.SeatsForPlayer(playerID)
+	if len(seats) >= MaxSeatsPerPlayer {
+		return "", ErrMaxSeatsReached
+	}
+	if len(g.Players) >= g.Rules.MaxPlayers {
+		return "", ErrMaxPlayersReached
+	}
+
+	seatID := SeatID(playerID, len(seats)+1)
+	g.addSeat(playerID, seatID)
+	return seatID, nil
+}
+
+// addSeat gives a player the hand for an extra seat
+func (g *Game) addSeat(playerID, seatID string) {
+	hand := NewHand()
+	hand.SetOwnerID(playerID)
+	g.Players[seatID] = hand
+}
+
+// IsSeat returns true if the hand was dealt to a seat, rather than split off another hand
+func (g *Game) IsSeat(handID string) bool {
+	hand, exists := g.Players[handID]
+	return exists && hand.GetParentHandID() == ""
+}
+
+// HandSeat returns the seat a hand is played from, following split hands
+// back to the seat they were split from
+func (g *Game) HandSeat(handID string) string {
+	// A hand can't be split more times than there are hands
+	for range g.Players {
+		hand, exists := g.Players[handID]
+		if !exists {
+			break
+		}
+		parentID := hand.GetParentHandID()
+		if parentID == "" {
+			break
+		}
+		handID = parentID
+	}
+	return handID
+}
+
+// SeatNumber returns the number of the player's seat a hand is played from, starting at 1
+func (g *Game) SeatNumber(handID string) int {
+	seatID := g.HandSeat(handID)
+	if playerID, seat, ok := parseSeatID(seatID); ok && g.HandOwner(seatID) == playerID {
+		return seat
+	}
+	return 1
+}
+
+// SeatsForPlayer returns the hand IDs of a player's seats in turn order
+func (g *Game) SeatsForPlayer(playerID string) []string {
+	var seats []string
+	for _, handID := range g.HandIDsInOrder() {
+		if g.IsSeat(handID) && g.HandOwner(handID) == playerID {
+			seats = append(seats, handID)
+		}
+	}
+	return seats
+}
+
+// PlayerIDs returns the IDs of the players at the table in turn order, once
+// each however many seats they play
+func (g *Game) PlayerIDs() []string {
+	var playerIDs []string
+	seen := make(map[string]bool)
+	for _, handID := range g.HandIDsInOrder() {
+		playerID := g.HandOwner(handID)
+		if !seen[playerID] {
+			seen[playerID] = true
+			playerIDs = append(playerIDs, playerID)
+		}
+	}
+	return playerIDs
+}
+
+// seatOrder returns the seats in the order they're played, players in no
+// particular order but each player's seats side by side in seat order
+func (g *Game) seatOrder() []string {
+	seats := make(map[string][]string)
+	for handID := range g.Players {
+		if g.IsSeat(handID) {
+			playerID := g.HandOwner(handID)
+			seats[playerID] = append(seats[playerID], handID)
+		}
+	}
+
+	order := make([]string, 0, len(g.Players))
+	for _, playerSeats := range seats {
+		sort.Slice(playerSeats, func(i, j int) bool {
+			return g.SeatNumber(playerSeats[i]) < g.SeatNumber(playerSeats[j])
+		})
+		order = append(order, playerSeats...)
+	}
+	return order
+}
+
+// BettingSeat returns the seat a player's main bet goes on: the seat whose
+// turn it is to bet, when it's one of theirs
+func (g *Game) BettingSeat(playerID string) (string, error) {
+	if len(g.PlayerOrder) == 0 || g.CurrentBettingPlayer >= len(g.PlayerOrder) {
+		if _, exists := g.Players[playerID]; !exists {
+			return "", ErrPlayerNotFound
+		}
+		return playerID, nil
+	}
+
+	seatID := g.PlayerOrder[g.CurrentBettingPlayer]
+	if seatID == playerID || g.HandOwner(seatID) == playerID {
+		return seatID, nil
+	}
+	if _, exists := g.Players[playerID]; !exists && len(g.SeatsForPlayer(playerID)) == 0 {
+		return "", ErrPlayerNotFound
+	}
+	return "", ErrNotPlayerTurn
+}
+
+// handsFromSeat returns the IDs of the seat's hand and every hand split from it
+func (g *Game) handsFromSeat(seatID string) []string {
+	var handIDs []string
+	for _, handID := range g.HandIDsInOrder() {
+		if g.HandSeat(handID) == seatID {
+			handIDs = append(handIDs, handID)
+		}
+	}
+	return handIDs
+}
@@ -0,0 +1,157 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSeat(t *testing.T) {
+	rules := DefaultRuleSet()
+	rules.MaxPlayers = 4
+	g := NewGame("test-channel", nil, rules, nil)
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.AddPlayer("player2"))
+
+	seatID, err := g.AddSeat("player1")
+	require.NoError(t, err)
+	assert.Equal(t, "player1#2", seatID)
+	assert.Equal(t, "player1", g.HandOwner(seatID))
+	assert.Equal(t, 2, g.SeatNumber(seatID))
+
+	_, err = g.AddSeat("player3")
+	assert.ErrorIs(t, err, ErrPlayerNotFound)
+
+	// Every seat takes a place at the table
+	seatID, err = g.AddSeat("player2")
+	require.NoError(t, err)
+	assert.Equal(t, "player2#2", seatID)
+	_, err = g.AddSeat("player1")
+	assert.ErrorIs(t, err, ErrMaxPlayersReached)
+
+	g.Rules.MaxPlayers = 7
+	_, err = g.AddSeat("player1")
+	require.NoError(t, err)
+	_, err = g.AddSeat("player1")
+	assert.ErrorIs(t, err, ErrMaxSeatsReached)
+
+	// A player's seats are played side by side in seat order
+	require.NoError(t, g.Start())
+	require.Len(t, g.PlayerOrder, 5)
+	for i, handID := range g.PlayerOrder {
+		if g.SeatNumber(handID) > 1 {
+			assert.Equal(t, SeatID(g.HandOwner(handID), g.SeatNumber(handID)-1), g.PlayerOrder[i-1])
+		}
+	}
+	assert.Equal(t, []string{"player1", "player1#2", "player1#3"}, g.SeatsForPlayer("player1"))
+	assert.ElementsMatch(t, []string{"player1", "player2"}, g.PlayerIDs())
+
+	_, err = g.AddSeat("player2")
+	assert.ErrorIs(t, err, ErrGameInProgress)
+}
+
+func TestMultiSeatRoundPaysEachPlayerOnce(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	repo := game.NewMemoryRepository()
+	g := newStackedGame(t, repo, []string{"player1", "player1#2", "player2"},
+		// First card to each seat, then the dealer's up-card
+		&entities.Card{Rank: entities.Ten, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Spades},
+		&entities.Card{Rank: entities.Ten, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Nine, Suit: entities.Diamonds},
+		// Second cards: player1's seats make 19 and 17, player2 makes 20, the dealer 18
+		&entities.Card{Rank: entities.Nine, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.Seven, Suit: entities.Spades},
+		&entities.Card{Rank: entities.King, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Nine, Suit: entities.Clubs},
+	)
+
+	// Side bets go on the first seat that hasn't made its main bet
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, "player1", 5, wallet))
+	assert.NotNil(t, g.GetSideBet("player1", SideBetTwentyOnePlusThree))
+
+	// player1 bets both of their seats before it's player2's turn
+	_, err := g.PlaceBetWithWalletUpdate(ctx, "player1", 10, wallet)
+	require.NoError(t, err)
+	assert.ErrorIs(t, g.ValidateBet("player2"), ErrNotPlayerTurn)
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, "player1", 5, wallet))
+	assert.NotNil(t, g.GetSideBet("player1#2", SideBetTwentyOnePlusThree))
+	_, err = g.PlaceBetWithWalletUpdate(ctx, "player1", 20, wallet)
+	require.NoError(t, err)
+	_, err = g.PlaceBetWithWalletUpdate(ctx, "player2", 30, wallet)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"player1": 10, "player1#2": 20, "player2": 30}, g.Bets)
+	assert.Equal(t, int64(40), wallet.removed["player1"])
+	assert.Zero(t, wallet.removed["player1#2"])
+
+	for g.State != entities.StateComplete {
+		switch g.State {
+		case StateSpecialBets:
+			handID, err := g.GetCurrentSpecialBetsPlayerID()
+			require.NoError(t, err)
+			require.NoError(t, g.DeclineSpecialBet(g.HandOwner(handID)))
+		case entities.StatePlaying:
+			handID, err := g.GetCurrentTurnPlayerID()
+			require.NoError(t, err)
+			require.NoError(t, g.Stand(g.HandOwner(handID)))
+			_, err = g.CompleteGameIfDone(ctx, wallet)
+			require.NoError(t, err)
+		case entities.StateDealer:
+			_, err := g.CompleteGameIfDone(ctx, wallet)
+			require.NoError(t, err)
+		default:
+			t.Fatalf("unexpected state %s", g.State)
+		}
+	}
+	require.True(t, g.PayoutsProcessed)
+
+	// player1 won one seat and lost the other, and is paid for both at once
+	assert.Equal(t, map[string]int64{"player1": 20, "player2": 60}, g.CalculatePayouts())
+	assert.Equal(t, int64(20), wallet.added["player1"])
+	assert.Equal(t, int64(60), wallet.added["player2"])
+	payouts := make(map[string]int)
+	for _, event := range g.Events {
+		if event.Type == EventPayout {
+			payouts[event.PlayerID]++
+		}
+	}
+	assert.Equal(t, map[string]int{"player1": 1, "player2": 1}, payouts)
+
+	results, err := g.GetResults()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "player1", results[1].PlayerID)
+	assert.Equal(t, "player1#2", results[1].HandID)
+	assert.False(t, results[1].IsSplit)
+
+	// The round plays back with the same seats
+	rebuilt, err := LoadRound(ctx, repo, "test-channel", g.ID, g.Rules)
+	require.NoError(t, err)
+	assert.Equal(t, g.PlayerOrder, rebuilt.PlayerOrder)
+	assert.Equal(t, g.Bets, rebuilt.Bets)
+	assert.Equal(t, g.SideBets, rebuilt.SideBets)
+	assert.Equal(t, "player1", rebuilt.HandOwner("player1#2"))
+}
+
+func TestSitOutExtraSeat(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	g := newStackedGame(t, game.NewMemoryRepository(), []string{"player1", "player1#2", "player2"})
+
+	_, err := g.PlaceBetWithWalletUpdate(ctx, "player1", 10, wallet)
+	require.NoError(t, err)
+
+	// Only the seat that ran out of time sits out, the player stays at the table
+	playerID, err := g.TimeOutTurn(ctx, wallet)
+	require.NoError(t, err)
+	assert.Equal(t, "player1", playerID)
+	assert.NotContains(t, g.Players, "player1#2")
+	assert.Equal(t, []string{"player1", "player2"}, g.PlayerOrder)
+	assert.Equal(t, map[string]int64{"player1": 10}, RoundStakes(g.Events))
+}
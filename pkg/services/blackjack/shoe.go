@@ -0,0 +1,97 @@
+package blackjack
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"math/rand/v2"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+const (
+	CutCardSpread       = 13 // The cut card lands up to a quarter deck either side of the penetration
+	ReserveCardsPerHand = 4  // Cards left behind the cut card for each hand a round can deal
+)
+
+// NewShoe creates a shoe for a table: shuffled, with the cut card placed near the
+// table's penetration and, if the table burns a card, the top card discarded
+func NewShoe(rules RuleSet, shuffler entities.Shuffler) *entities.Deck {
+	deck := NewBlackjackDeck(rules.Decks, shuffler)
+	deck.CutCard = cutCardPosition(len(deck.Cards), rules, deck.Seed)
+
+	if rules.BurnCard {
+		deck.Draw()
+	}
+	return deck
+}
+
+// cutCardPosition picks a random cut card position around the penetration, so
+// players can't know exactly when the shoe ends. It follows from the shoe's
+// seed, so a seeded shoe is cut in the same place every time. The cut card
+// never leaves fewer than the table's reserve behind it.
+func cutCardPosition(shoeSize int, rules RuleSet, seed entities.Seed) int {
+	r := rand.New(rand.NewPCG(binary.LittleEndian.Uint64(seed[:8]), binary.LittleEndian.Uint64(seed[8:16])))
+	position := int(float64(shoeSize)*rules.Penetration) + r.IntN(2*CutCardSpread+1) - CutCardSpread
+	return max(1, min(position, shoeSize-rules.CardsReserved()))
+}
+
+// ShouldReshuffle checks if the shoe has to be replaced before the next round:
+// the cut card came out, or the shoe doesn't match the table's deck count.
+// Shoes without a cut card fall back to the table's penetration.
+func ShouldReshuffle(deck *entities.Deck, rules RuleSet) bool {
+	if deck == nil || len(deck.Cards) > rules.ShoeSize() {
+		return true
+	}
+	if deck.CutCard > 0 {
+		return len(deck.Cards)+deck.Dealt != rules.ShoeSize() || deck.Dealt >= deck.CutCard
+	}
+	return len(deck.Cards) < rules.ReshuffleThreshold()
+}
+
+// reshuffle replaces the table's shoe with a new one
+func (g *Game) reshuffle() {
+	g.Deck = NewShoe(g.Rules, g.shuffler)
+	g.shuffled = true
+	g.emit(Event{Type: EventShoeShuffled, Seed: g.Deck.Seed.String()})
+}
+
+// prepareShoe makes sure the shoe is good for a whole round before dealing.
+// Shoes are reshuffled here, between rounds. The only other new shoe is the
+// one drawCard brings out if a round runs through the reserve.
+func (g *Game) prepareShoe() {
+	// A provably fair table also replaces any shoe it didn't commit to, and
+	// brings out the next one as soon as players have seeded it
+	if ShouldReshuffle(g.Deck, g.Rules) || !g.shoeCommitted() || g.clientSeedsWaiting() {
+		log.Printf("Reshuffling the shoe for channel %s", g.ChannelID)
+		g.reshuffle()
+	}
+}
+
+// drawCard deals the next card from the shoe. The cut card leaves enough cards
+// for a full table, but a round with a lot of splits and small cards can still
+// run the shoe out. The new shoe is logged and kept with the round, since the
+// round's shoe seed and position alone no longer deal it again.
+func (g *Game) drawCard() *entities.Card {
+	card := g.Deck.Draw()
+	if card == nil {
+		log.Printf("Warning: shoe for channel %s ran out mid-round, bringing out a new shoe", g.ChannelID)
+		g.emit(Event{Type: EventShoeRanOut, Seed: g.Deck.Seed.String()})
+		g.reshuffle()
+		g.NewShoeSeed = g.Deck.Seed
+		card = g.Deck.Draw()
+	}
+	return card
+}
+
+// saveDeck records the shoe's state, so a restart picks up where the shoe left off
+func (g *Game) saveDeck() error {
+	if g.repo == nil {
+		return nil
+	}
+	if err := g.repo.SaveDeck(context.Background(), g.ChannelID, g.Deck); err != nil {
+		log.Printf("Error saving deck: %v", err)
+		return ErrFailedToSaveDeck
+	}
+	return nil
+}
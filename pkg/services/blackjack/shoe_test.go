@@ -0,0 +1,154 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShoePlacesCutCardAndBurns(t *testing.T) {
+	rules := DefaultRuleSet()
+	penetration := int(float64(rules.ShoeSize()) * rules.Penetration)
+
+	for i := 0; i < 50; i++ {
+		shoe := NewShoe(rules, nil)
+		assert.Equal(t, 1, shoe.Dealt, "the burn card is discarded")
+		assert.Len(t, shoe.Cards, rules.ShoeSize()-1)
+		assert.InDelta(t, penetration, shoe.CutCard, CutCardSpread)
+	}
+
+	rules.BurnCard = false
+	assert.Equal(t, 0, NewShoe(rules, nil).Dealt)
+
+	// A deep cut still leaves a full table's reserve behind the cut card
+	rules.Penetration = 0.95
+	for i := 0; i < 50; i++ {
+		assert.LessOrEqual(t, NewShoe(rules, nil).CutCard, rules.ShoeSize()-rules.CardsReserved())
+	}
+}
+
+func TestRulesKeepAReserveBehindTheCutCard(t *testing.T) {
+	for _, name := range []string{RuleSetHouse, RuleSetVegasStrip, RuleSetAtlanticCity, RuleSetEuropean} {
+		rules, err := RuleSetByName(name)
+		require.NoError(t, err)
+		assert.NoError(t, rules.Validate(), name)
+	}
+
+	// Seven seats, one of them split four ways, and the dealer
+	rules := DefaultRuleSet()
+	assert.Equal(t, 11*ReserveCardsPerHand, rules.CardsReserved())
+
+	// A single deck cut at 75% can't finish a round at a full table
+	rules.Decks = 1
+	assert.ErrorIs(t, rules.Validate(), ErrInvalidRuleSet)
+	rules.MaxPlayers = 2
+	rules.Penetration = 0.5
+	assert.NoError(t, rules.Validate())
+}
+
+func TestShoeRunningOutMidRoundIsRecorded(t *testing.T) {
+	ctx := context.Background()
+	repo := game.NewMemoryRepository()
+	g := dealRound(t, repo, entities.NewSeededShuffler(entities.Seed{7}))
+	for g.State != entities.StatePlaying {
+		switch g.State {
+		case StateSplitting:
+			require.NoError(t, g.DeclineSplit(g.HandOwner(g.GetCurrentSplittingPlayerID())))
+		case StateInsurance, StateSpecialBets:
+			handID, err := g.GetCurrentSpecialBetsPlayerID()
+			require.NoError(t, err)
+			require.NoError(t, g.DeclineSpecialBet(g.HandOwner(handID)))
+		default:
+			t.Fatalf("unexpected state %s", g.State)
+		}
+	}
+	seed := g.ShoeSeed
+
+	// The shoe runs dry in the middle of the round
+	g.Deck.Cards = nil
+	handID, err := g.GetCurrentTurnPlayerID()
+	require.NoError(t, err)
+	require.NoError(t, g.Hit(g.HandOwner(handID)))
+	for g.State == entities.StatePlaying {
+		handID, err := g.GetCurrentTurnPlayerID()
+		require.NoError(t, err)
+		require.NoError(t, g.Stand(g.HandOwner(handID)))
+	}
+	_, err = g.CompleteGameIfDone(ctx, newStubWalletService())
+	require.NoError(t, err)
+	require.True(t, g.PayoutsProcessed)
+
+	// The new shoe is in the round's log and its record, next to the one it was dealt from
+	assert.Equal(t, seed, g.ShoeSeed)
+	assert.False(t, g.NewShoeSeed.IsZero())
+	assert.NotEqual(t, seed, g.NewShoeSeed)
+	var ranOut bool
+	for _, event := range g.Events {
+		if event.Type == EventShoeRanOut {
+			ranOut = true
+			assert.Equal(t, seed.String(), event.Seed)
+		}
+	}
+	assert.True(t, ranOut)
+
+	results, err := repo.GetChannelResults(ctx, "test-channel", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	details, ok := results[0].Details.(BlackjackGameDetails)
+	require.True(t, ok)
+	assert.Equal(t, seed.String(), details.ShoeSeed)
+	assert.Equal(t, g.NewShoeSeed.String(), details.NewShoeSeed)
+
+	// The round still plays back from its log
+	_, err = LoadRound(ctx, repo, g.ChannelID, g.ID, DefaultRuleSet())
+	assert.NoError(t, err)
+}
+
+func TestShouldReshuffleAtCutCard(t *testing.T) {
+	rules := DefaultRuleSet()
+	shoe := NewShoe(rules, nil)
+	assert.False(t, ShouldReshuffle(shoe, rules))
+
+	for shoe.Dealt < shoe.CutCard-1 {
+		shoe.Draw()
+	}
+	assert.False(t, ShouldReshuffle(shoe, rules))
+	shoe.Draw()
+	assert.True(t, ShouldReshuffle(shoe, rules), "the cut card came out")
+
+	// A shoe for another table size is always replaced
+	assert.True(t, ShouldReshuffle(NewShoe(VegasStripRules(), nil), rules))
+}
+
+func TestShoeOnlyReshufflesBetweenRounds(t *testing.T) {
+	repo := game.NewMemoryRepository()
+	g := dealRound(t, repo, nil)
+
+	// Pretend the cut card came out during the deal
+	g.Deck.CutCard = g.Deck.Dealt
+	g.State = entities.StatePlaying
+	g.CurrentTurn = 0
+	for _, playerID := range g.PlayerOrder {
+		g.Players[playerID].Status = StatusPlaying
+	}
+	seed := g.Deck.Seed
+
+	require.NoError(t, g.Hit(g.PlayerOrder[0]))
+	assert.Equal(t, seed, g.Deck.Seed, "the round finishes with the same shoe")
+
+	require.NoError(t, g.PlayDealer())
+	saved, err := repo.GetDeck(context.Background(), "test-channel")
+	require.NoError(t, err)
+	assert.Equal(t, g.Deck.Dealt, saved.Dealt, "the cards drawn mid-round are saved")
+	assert.Equal(t, g.Deck.CutCard, saved.CutCard)
+
+	// The next round brings out a new shoe
+	next := dealRound(t, repo, nil)
+	assert.NotEqual(t, seed, next.ShoeSeed)
+	assert.Equal(t, 1, next.ShoePosition)
+	assert.True(t, next.WasShuffled())
+}
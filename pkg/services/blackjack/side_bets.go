@@ -0,0 +1,384 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// Side bet errors
+var (
+	ErrUnknownSideBet       = errors.New("unknown side bet")
+	ErrSideBetAlreadyPlaced = errors.New("side bet already placed")
+	ErrSideBetClosed        = errors.New("side bet isn't open")
+	ErrSideBetTooSmall      = errors.New("side bet is under the table minimum")
+	ErrSideBetTooLarge      = errors.New("side bet is over the table maximum")
+	ErrInvalidPayTable      = errors.New("invalid pay table")
+)
+
+// SideBetWindow is when in a round a side bet can be placed
+type SideBetWindow string
+
+const (
+	SideBetWindowBetting   SideBetWindow = "betting"   // While the table is betting, before the player's main bet
+	SideBetWindowInsurance SideBetWindow = "insurance" // On the hand's turn while the dealer shows an Ace, before the peek
+)
+
+// SideBetJudgement is which cards a side bet waits for before it's judged
+type SideBetJudgement string
+
+const (
+	JudgedOnDeal       SideBetJudgement = "deal"        // The player's first two cards and the dealer's up-card
+	JudgedOnHoleCard   SideBetJudgement = "hole_card"   // The player's first two cards and the dealer's first two cards
+	JudgedOnDealerHand SideBetJudgement = "dealer_hand" // The player's first two cards and the dealer's finished hand
+)
+
+// SideBet is a kind of side bet the table offers. The game takes bets while
+// its window is open, checks them against its limits and judges them with
+// Evaluate as soon as the cards it's judged on are out, so a new side bet only
+// needs an evaluator and a pay table.
+type SideBet interface {
+	// Name identifies the side bet in events and records, e.g. "perfect_pairs"
+	Name() string
+	// Label is what the side bet is called at the table, e.g. "Perfect Pairs"
+	Label() string
+	// Window is when the side bet can be placed
+	Window() SideBetWindow
+	// JudgedOn is which cards the side bet waits for
+	JudgedOn() SideBetJudgement
+	// Limits returns the smallest and largest bet taken next to a main bet of
+	// the given size, a maximum of 0 for no limit
+	Limits(mainBet int64) (min, max int64)
+	// Describe returns an outcome from Evaluate the way Tuco calls it, e.g. "Colored Pair"
+	Describe(outcome string) string
+	// Evaluate judges a bet on the player's first two cards and the dealer's
+	// cards it's judged on. It returns the outcome and the amount returned,
+	// stake included, or 0 if the bet lost.
+	Evaluate(playerCards, dealerCards []*entities.Card, amount int64) (string, int64)
+}
+
+// SideBetRegistry holds the side bets a table offers, in the order they're shown
+type SideBetRegistry struct {
+	sideBets []SideBet
+}
+
+// NewSideBetRegistry creates a registry offering the given side bets
+func NewSideBetRegistry(sideBets ...SideBet) *SideBetRegistry {
+	registry := &SideBetRegistry{}
+	for _, sideBet := range sideBets {
+		registry.Register(sideBet)
+	}
+	return registry
+}
+
+// DefaultSideBetRegistry returns the side bets Tuco offers, with his pay tables
+func DefaultSideBetRegistry() *SideBetRegistry {
+	return NewSideBetRegistry(
+		Insurance{Pays: DefaultInsurancePays},
+		TwentyOnePlusThree{Pays: DefaultTwentyOnePlusThreePays()},
+		PerfectPairs{Pays: DefaultPerfectPairsPays()},
+	)
+}
+
+// Register offers a side bet, replacing the one with the same name, e.g. to
+// change its pay table
+func (r *SideBetRegistry) Register(sideBet SideBet) {
+	for i, offered := range r.sideBets {
+		if offered.Name() == sideBet.Name() {
+			r.sideBets[i] = sideBet
+			return
+		}
+	}
+	r.sideBets = append(r.sideBets, sideBet)
+}
+
+// Lookup returns the side bet with the given name, or false if it isn't offered
+func (r *SideBetRegistry) Lookup(name string) (SideBet, bool) {
+	for _, offered := range r.sideBets {
+		if offered.Name() == name {
+			return offered, true
+		}
+	}
+	return nil, false
+}
+
+// InWindow returns the side bets placed in the given window, in the order they're shown
+func (r *SideBetRegistry) InWindow(window SideBetWindow) []SideBet {
+	var sideBets []SideBet
+	for _, offered := range r.sideBets {
+		if offered.Window() == window {
+			sideBets = append(sideBets, offered)
+		}
+	}
+	return sideBets
+}
+
+// PlacedSideBet is a side bet a player has down on one of their hands
+type PlacedSideBet struct {
+	Type    string `json:"type"`
+	Amount  int64  `json:"amount"`
+	Outcome string `json:"outcome,omitempty"` // How the bet was judged, empty until its cards are out
+	Payout  int64  `json:"payout,omitempty"`  // Amount returned, stake included, 0 if it lost
+	Paid    bool   `json:"paid,omitempty"`    // Whether the bet has been settled with the wallet
+}
+
+// Settled returns true once the bet has been judged
+func (b *PlacedSideBet) Settled() bool {
+	return b.Outcome != ""
+}
+
+// parsePayTable reads a pay table as comma separated name=pays pairs, e.g.
+// "perfect=25,mixed=6". Names are looked up with the given function and any
+// name left out pays nothing.
+func parsePayTable(spec string, lookup func(name string) (string, bool)) (map[string]int64, error) {
+	pays := make(map[string]int64)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: expected name=pays, got %q", ErrInvalidPayTable, entry)
+		}
+		key, known := lookup(strings.ToLower(strings.TrimSpace(name)))
+		if !known {
+			return nil, fmt.Errorf("%w: unknown hand %q", ErrInvalidPayTable, name)
+		}
+		amount, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("%w: bad payout %q", ErrInvalidPayTable, value)
+		}
+		pays[key] = amount
+	}
+	if len(pays) == 0 {
+		return nil, fmt.Errorf("%w: nothing pays", ErrInvalidPayTable)
+	}
+	return pays, nil
+}
+
+// SideBetRegistry returns the side bets the table offers
+func (g *Game) SideBetRegistry() *SideBetRegistry {
+	return g.registry
+}
+
+// SetSideBetRegistry changes the side bets the table offers. Bets already
+// placed are judged by the side bet of the same name in the new registry.
+func (g *Game) SetSideBetRegistry(registry *SideBetRegistry) {
+	g.registry = registry
+}
+
+// DescribeSideBet returns how a judged side bet came out, e.g. "Perfect Pairs: Colored Pair"
+func (g *Game) DescribeSideBet(placed *PlacedSideBet) string {
+	sideBet, offered := g.registry.Lookup(placed.Type)
+	if !offered {
+		return fmt.Sprintf("%s: %s", placed.Type, placed.Outcome)
+	}
+	return fmt.Sprintf("%s: %s", sideBet.Label(), sideBet.Describe(placed.Outcome))
+}
+
+// GetSideBet returns a hand's side bet of the given kind, or nil if there isn't one
+func (g *Game) GetSideBet(handID, name string) *PlacedSideBet {
+	for _, placed := range g.SideBets[handID] {
+		if placed.Type == name {
+			return placed
+		}
+	}
+	return nil
+}
+
+// sideBetHand returns the hand a player's side bet placed in the given window
+// goes on, or an error if the window isn't open for them
+func (g *Game) sideBetHand(window SideBetWindow, playerID string) (string, error) {
+	switch window {
+	case SideBetWindowBetting:
+		if g.State != entities.StateBetting {
+			return "", ErrInvalidAction
+		}
+		seats := g.SeatsForPlayer(playerID)
+		if len(seats) == 0 {
+			return "", ErrPlayerNotFound
+		}
+		// Side bets on a seat close once its main bet is made
+		for _, seatID := range seats {
+			if _, hasBet := g.Bets[seatID]; !hasBet {
+				return seatID, nil
+			}
+		}
+		return "", ErrSideBetClosed
+	case SideBetWindowInsurance:
+		if !g.isTakingSpecialBets() {
+			return "", ErrInvalidAction
+		}
+		handID, err := g.GetCurrentSpecialBetsPlayerID()
+		if err != nil {
+			return "", err
+		}
+		if g.HandOwner(handID) != playerID {
+			return "", ErrNotPlayerTurn
+		}
+		if !g.IsEligibleForInsurance() {
+			return "", ErrSideBetClosed
+		}
+		return handID, nil
+	}
+	return "", fmt.Errorf("%w: unknown window %q", ErrSideBetClosed, window)
+}
+
+// PlaceSideBet places a side bet for a player while its window is open. Bets
+// placed while the table is betting go on the player's first seat that hasn't
+// made its main bet, the others on the hand whose turn it is, which then moves on.
+func (g *Game) PlaceSideBet(ctx context.Context, name, playerID string, amount int64, walletService WalletService) error {
+	sideBet, offered := g.registry.Lookup(name)
+	if !offered {
+		return fmt.Errorf("%w: %s", ErrUnknownSideBet, name)
+	}
+	handID, err := g.sideBetHand(sideBet.Window(), playerID)
+	if err != nil {
+		return err
+	}
+	if g.GetSideBet(handID, name) != nil {
+		return ErrSideBetAlreadyPlaced
+	}
+
+	minBet, maxBet := sideBet.Limits(g.Bets[handID])
+	if amount <= 0 {
+		return ErrInvalidBet
+	}
+	if amount < minBet {
+		return fmt.Errorf("%w of $%d", ErrSideBetTooSmall, minBet)
+	}
+	if maxBet > 0 && amount > maxBet {
+		return fmt.Errorf("%w of $%d", ErrSideBetTooLarge, maxBet)
+	}
+
+	wallet, _, err := walletService.EnsureFundsWithLoan(ctx, playerID, amount, walletService.GetStandardLoanIncrement())
+	if err != nil {
+		return fmt.Errorf("error ensuring funds: %w", err)
+	}
+	if wallet.Balance < amount {
+		return ErrInsufficientFundsForAction
+	}
+	transactionType := entities.TransactionTypeSideBet
+	if name == SideBetInsurance {
+		transactionType = entities.TransactionTypeInsurance
+	}
+	if err := g.stake(ctx, walletService, playerID, handID, amount, transactionType, sideBet.Label()+" side bet"); err != nil {
+		return fmt.Errorf("error updating wallet: %w", err)
+	}
+
+	g.SideBets[handID] = append(g.SideBets[handID], &PlacedSideBet{Type: name, Amount: amount})
+	g.emit(Event{Type: EventSideBetPlaced, PlayerID: playerID, HandID: handID, Amount: amount, SideBet: name})
+	log.Printf("Player %s placed a $%d %s side bet on hand %s in channel %s", playerID, amount, sideBet.Label(), handID, g.ChannelID)
+
+	// A bet placed on the hand's turn is its decision for the turn
+	if sideBet.Window() == SideBetWindowInsurance {
+		return g.AdvanceSpecialBetsTurn()
+	}
+	return nil
+}
+
+// sideBetDealerCards returns the dealer's cards a side bet is judged on, or
+// false if they aren't all out yet
+func (g *Game) sideBetDealerCards(judged SideBetJudgement) ([]*entities.Card, bool) {
+	cards := g.Dealer.Cards
+	dealerFinished := g.State == entities.StateComplete || (g.State == entities.StateDealer && g.CheckAllPlayersBust())
+
+	switch judged {
+	case JudgedOnDeal:
+		if len(cards) < 1 {
+			return nil, false
+		}
+		return cards[:1], true
+	case JudgedOnHoleCard:
+		// The hole card is known once the dealer peeks or turns it over
+		if !g.DealerPeeked && g.State != entities.StateDealer && g.State != entities.StateComplete {
+			return nil, false
+		}
+		if len(cards) > 2 {
+			cards = cards[:2]
+		}
+		return cards, true
+	case JudgedOnDealerHand:
+		return cards, dealerFinished
+	}
+	return nil, false
+}
+
+// settleSideBets judges every side bet whose cards are out. The game calls it
+// after the deal, the peek and the dealer's turn, and the winnings are paid by
+// PaySideBets.
+func (g *Game) settleSideBets() {
+	for _, handID := range g.PlayerOrder {
+		hand := g.Players[handID]
+		if hand == nil || len(hand.Cards) < 2 {
+			continue
+		}
+		for _, placed := range g.SideBets[handID] {
+			sideBet, offered := g.registry.Lookup(placed.Type)
+			if placed.Settled() || !offered {
+				continue
+			}
+			dealerCards, ready := g.sideBetDealerCards(sideBet.JudgedOn())
+			if !ready {
+				continue
+			}
+			placed.Outcome, placed.Payout = sideBet.Evaluate(hand.Cards[:2], dealerCards, placed.Amount)
+			log.Printf("Hand %s's %s side bet came out %s and returns $%d", handID, sideBet.Label(), placed.Outcome, placed.Payout)
+		}
+	}
+}
+
+// PaySideBets pays the judged side bets through the wallet service. It's safe
+// to call more than once, a side bet is only ever paid once.
+func (g *Game) PaySideBets(ctx context.Context, walletService WalletService) error {
+	for _, handID := range g.PlayerOrder {
+		for _, placed := range g.SideBets[handID] {
+			if err := g.paySideBet(ctx, handID, placed, walletService); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// paySideBet settles a hand's judged side bet with the wallet of the player who owns it
+func (g *Game) paySideBet(ctx context.Context, handID string, placed *PlacedSideBet, walletService WalletService) error {
+	if !placed.Settled() || placed.Paid {
+		return nil
+	}
+
+	playerID := g.HandOwner(handID)
+	// The stake is settled, it's the table's now
+	g.captureStake(ctx, walletService, playerID, placed.Amount)
+	if placed.Payout > 0 {
+		description := "Side bet winnings"
+		if sideBet, offered := g.registry.Lookup(placed.Type); offered {
+			description = sideBet.Label() + " side bet winnings"
+		}
+		if err := walletService.AddFunds(ctx, playerID, g.ChannelID, placed.Payout, entities.TransactionTypePayout, g.reference(handID), description); err != nil {
+			log.Printf("Error paying %s side bet for player %s: %v", placed.Type, playerID, err)
+			return err
+		}
+	}
+	placed.Paid = true
+	g.emit(Event{Type: EventSideBetSettled, PlayerID: playerID, HandID: handID, Amount: placed.Payout, SideBet: placed.Type})
+	return nil
+}
+
+// sideBetMetadata returns a hand's side bets for its game record, keyed by the
+// side bet's name, e.g. "perfect_pairs_bet"
+func (g *Game) sideBetMetadata(handID string) map[string]interface{} {
+	metadata := make(map[string]interface{})
+	for _, placed := range g.SideBets[handID] {
+		metadata[placed.Type+"_bet"] = placed.Amount
+		metadata[placed.Type+"_outcome"] = placed.Outcome
+		metadata[placed.Type+"_payout"] = placed.Payout
+	}
+	return metadata
+}
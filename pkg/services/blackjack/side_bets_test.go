@@ -0,0 +1,143 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBettingGame returns a game waiting on bets from player1 and player2, with
+// the given cards stacked on top of the shoe in the order they're dealt
+func newBettingGame(cards ...*entities.Card) *Game {
+	rules := DefaultRuleSet()
+	g := NewGame("test-channel", nil, rules, nil)
+	for _, playerID := range []string{"player1", "player2"} {
+		g.Players[playerID] = NewHand()
+	}
+	g.PlayerOrder = []string{"player1", "player2"}
+	g.State = entities.StateBetting
+	g.Deck = &entities.Deck{Cards: cards, Dealt: rules.ShoeSize() - len(cards), CutCard: rules.ShoeSize()}
+	return g
+}
+
+func TestSideBetsSettleOnTheDeal(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	g := newBettingGame(
+		&entities.Card{Rank: entities.Seven, Suit: entities.Hearts}, &entities.Card{Rank: entities.Eight, Suit: entities.Hearts},
+		&entities.Card{Rank: entities.King, Suit: entities.Spades}, &entities.Card{Rank: entities.King, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Nine, Suit: entities.Hearts}, &entities.Card{Rank: entities.Five, Suit: entities.Clubs},
+	)
+	g.SideBetRegistry().Register(PerfectPairs{Pays: PairPayTable{PairColored: 10}})
+
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, "player1", 5, wallet))
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player1", 5, wallet))
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, "player2", 10, wallet))
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player2", 10, wallet))
+	assert.Equal(t, map[string]int64{"player1": 10, "player2": 20}, wallet.removed)
+
+	g.Bets["player1"] = 10
+	g.Bets["player2"] = 10
+	require.NoError(t, g.StartDealing())
+
+	// 7-8 of hearts under a nine of hearts is a straight flush, paying 40 to 1
+	assert.Equal(t, &PlacedSideBet{Type: SideBetTwentyOnePlusThree, Amount: 5, Outcome: string(PokerHandStraightFlush), Payout: 205},
+		g.GetSideBet("player1", SideBetTwentyOnePlusThree))
+	assert.Equal(t, string(PairNone), g.GetSideBet("player1", SideBetPerfectPairs).Outcome)
+	assert.Equal(t, string(PokerHandNone), g.GetSideBet("player2", SideBetTwentyOnePlusThree).Outcome)
+	// Two black kings are a colored pair, paying 10 to 1 on this table
+	assert.Equal(t, &PlacedSideBet{Type: SideBetPerfectPairs, Amount: 10, Outcome: string(PairColored), Payout: 110},
+		g.GetSideBet("player2", SideBetPerfectPairs))
+
+	require.NoError(t, g.PaySideBets(ctx, wallet))
+	require.NoError(t, g.PaySideBets(ctx, wallet))
+	assert.Equal(t, map[string]int64{"player1": 205, "player2": 110}, wallet.added)
+
+	settled := 0
+	for _, event := range g.Events {
+		if event.Type == EventSideBetSettled {
+			settled++
+		}
+	}
+	assert.Equal(t, 4, settled)
+
+	assert.Equal(t, map[string]interface{}{
+		"twenty_one_plus_three_bet":     int64(5),
+		"twenty_one_plus_three_outcome": string(PokerHandStraightFlush),
+		"twenty_one_plus_three_payout":  int64(205),
+		"perfect_pairs_bet":             int64(5),
+		"perfect_pairs_outcome":         string(PairNone),
+		"perfect_pairs_payout":          int64(0),
+	}, g.sideBetMetadata("player1"))
+}
+
+func TestPlaceSideBetRules(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	g := newBettingGame()
+	g.Bets["player2"] = 10
+	g.SideBetRegistry().Register(PerfectPairs{Pays: DefaultPerfectPairsPays(), MinBet: 5, MaxBet: 25})
+
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, "lucky_ladies", "player1", 5, wallet), ErrUnknownSideBet)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player3", 5, wallet), ErrPlayerNotFound)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player1", 0, wallet), ErrInvalidBet)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player1", 2, wallet), ErrSideBetTooSmall)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player1", 50, wallet), ErrSideBetTooLarge)
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player2", 5, wallet), ErrSideBetClosed)
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player1", 5, wallet))
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player1", 5, wallet), ErrSideBetAlreadyPlaced)
+
+	g.State = entities.StatePlaying
+	g.Bets = map[string]int64{}
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, "player2", 5, wallet), ErrInvalidAction)
+	// Insurance is only taken on the hand's turn while the dealer shows an Ace
+	assert.ErrorIs(t, g.PlaceSideBet(ctx, SideBetInsurance, "player2", 5, wallet), ErrInvalidAction)
+	assert.Equal(t, map[string]int64{"player1": 5}, wallet.removed)
+}
+
+func TestSideBetsAreRecordedAndReplayed(t *testing.T) {
+	ctx := context.Background()
+	wallet := newStubWalletService()
+	repo := game.NewMemoryRepository()
+	g := NewGame("test-channel", repo, DefaultRuleSet(), entities.NewSeededShuffler(entities.Seed{7}))
+	for _, playerID := range []string{"player1", "player2"} {
+		require.NoError(t, g.AddPlayer(playerID))
+	}
+	require.NoError(t, g.Start())
+	for _, playerID := range g.PlayerOrder {
+		require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, playerID, 5, wallet))
+		require.NoError(t, g.PlaceSideBet(ctx, SideBetPerfectPairs, playerID, 5, wallet))
+		_, err := g.PlaceBetWithWalletUpdate(ctx, playerID, 10, wallet)
+		require.NoError(t, err)
+	}
+
+	// The last bet deals the round and pays the side bets straight away
+	for _, playerID := range g.PlayerOrder {
+		for _, sideBet := range g.SideBets[playerID] {
+			assert.True(t, sideBet.Paid, "%s %s", playerID, sideBet.Type)
+		}
+	}
+	playOut(t, g)
+
+	results, err := repo.GetChannelResults(ctx, "test-channel", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	recorded := 0
+	for _, result := range results[0].PlayerResults {
+		if bet, ok := result.Metadata["perfect_pairs_bet"]; ok {
+			recorded++
+			assert.Equal(t, int64(5), bet)
+			assert.Contains(t, result.Metadata, "perfect_pairs_outcome")
+			assert.Contains(t, result.Metadata, "twenty_one_plus_three_payout")
+		}
+	}
+	assert.Equal(t, 2, recorded)
+
+	rebuilt, err := LoadRound(ctx, repo, "test-channel", g.ID, DefaultRuleSet())
+	require.NoError(t, err)
+	assert.Equal(t, g.SideBets, rebuilt.SideBets)
+}
@@ -0,0 +1,228 @@
+package blackjack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+)
+
+// ErrInvalidSnapshot is returned when a saved game can't be restored
+var ErrInvalidSnapshot = errors.New("invalid game snapshot")
+
+// gameSnapshot is a game as it's saved after every state change
+type gameSnapshot struct {
+	Game     *Game `json:"game"`
+	Shuffled bool  `json:"shuffled"`
+}
+
+// Snapshot encodes the game's state, so the round can be picked back up after a restart
+func (g *Game) Snapshot() ([]byte, error) {
+	return json.Marshal(gameSnapshot{Game: g, Shuffled: g.shuffled})
+}
+
+// RestoreGame rebuilds a game from its snapshot, using the repository and
+// shuffler for the rest of the round
+func RestoreGame(data []byte, repo game.Repository, shuffler entities.Shuffler) (*Game, error) {
+	g := NewGame("", repo, RuleSet{}, shuffler)
+	snapshot := gameSnapshot{Game: g}
+
+	// Keep numbers as they were written, so bet amounts in hand metadata come back as int64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if g.ChannelID == "" || g.ID == "" {
+		return nil, fmt.Errorf("%w: missing channel or round ID", ErrInvalidSnapshot)
+	}
+	if err := g.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if g.Deck == nil || g.Dealer == nil {
+		return nil, fmt.Errorf("%w: missing shoe or dealer hand", ErrInvalidSnapshot)
+	}
+
+	for handID, hand := range g.Players {
+		if hand == nil {
+			return nil, fmt.Errorf("%w: missing hand %s", ErrInvalidSnapshot, handID)
+		}
+		restoreMetadata(hand)
+	}
+	restoreMetadata(g.Dealer)
+	if g.Bets == nil {
+		g.Bets = make(map[string]int64)
+	}
+	if g.SideBets == nil {
+		g.SideBets = make(map[string][]*PlacedSideBet)
+	}
+	g.shuffled = snapshot.Shuffled
+
+	// Events logged after the snapshot mean the bot stopped part way through a
+	// change, and the wallet may not match the snapshot any more
+	if repo != nil {
+		records, err := repo.GetRoundEvents(context.Background(), g.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > len(g.Events) {
+			return nil, fmt.Errorf("%w: %d events logged after the snapshot", ErrInvalidSnapshot, len(records)-len(g.Events))
+		}
+	}
+
+	return g, nil
+}
+
+// restoreMetadata turns the numbers decoded from a snapshot back into the types
+// the hand's metadata helpers expect. Every number the game stores is an int64.
+func restoreMetadata(hand *Hand) {
+	if hand.Metadata == nil {
+		hand.Metadata = make(map[string]interface{})
+	}
+	for key, value := range hand.Metadata {
+		number, ok := value.(json.Number)
+		if !ok {
+			continue
+		}
+		if intVal, err := number.Int64(); err == nil {
+			hand.Metadata[key] = intVal
+		} else if floatVal, err := number.Float64(); err == nil {
+			hand.Metadata[key] = floatVal
+		}
+	}
+}
+
+// RoundStakes works out from a round's events the chips each player put on the
+// table and hasn't had settled yet. Players who were already paid or refunded
+// are left out.
+func RoundStakes(events []Event) map[string]int64 {
+	stakes := make(map[string]int64)
+	bets := make(map[string]int64)     // Main bet per seat, taken back if it's cancelled
+	sideBets := make(map[string]int64) // Side bets per hand and kind, off the table once they're paid
+	backBets := make(map[string]int64) // Back bets per spectator and seat, off the table once they're paid
+	settled := make(map[string]bool)
+
+	for _, event := range events {
+		switch event.Type {
+		case EventBetPlaced:
+			bets[event.HandID] = event.Amount
+			stakes[event.PlayerID] += event.Amount
+		case EventBetCancelled:
+			stakes[event.PlayerID] -= bets[event.HandID]
+			delete(bets, event.HandID)
+		case EventSideBetPlaced:
+			sideBets[event.HandID+"/"+event.SideBet] = event.Amount
+			stakes[event.PlayerID] += event.Amount
+		case EventSideBetSettled, EventSideBetVoided:
+			stakes[event.PlayerID] -= sideBets[event.HandID+"/"+event.SideBet]
+			delete(sideBets, event.HandID+"/"+event.SideBet)
+		case EventBackBetPlaced:
+			backBets[event.PlayerID+"/"+event.HandID] = event.Amount
+			stakes[event.PlayerID] += event.Amount
+		case EventBackBetSettled:
+			stakes[event.PlayerID] -= backBets[event.PlayerID+"/"+event.HandID]
+			delete(backBets, event.PlayerID+"/"+event.HandID)
+		case EventInsurance, EventSplit, EventDoubleDown:
+			stakes[event.PlayerID] += event.Amount
+		case EventPayout, EventRefund:
+			settled[event.PlayerID] = true
+		case EventRoundComplete:
+			return map[string]int64{}
+		}
+	}
+
+	for playerID, stake := range stakes {
+		if stake <= 0 || settled[playerID] {
+			delete(stakes, playerID)
+		}
+	}
+	return stakes
+}
+
+// RefundRound gives every player back their stakes in a round that can't be
+// finished, going by the round's event log. Stakes the wallet service still
+// holds are released, anything else is paid back. Each refund is added to the
+// log, so running it again never refunds a player twice. It returns the amount
+// refunded to each player.
+func RefundRound(ctx context.Context, repo game.Repository, walletService WalletService, channelID, roundID string) (map[string]int64, error) {
+	records, err := repo.GetRoundEvents(ctx, roundID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := EventsFromRecords(records)
+	if err != nil {
+		return nil, err
+	}
+	stakes := RoundStakes(events)
+
+	// Refund in a stable order, so a failure part way through is easy to follow in the logs
+	playerIDs := make([]string, 0, len(stakes))
+	for playerID := range stakes {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Strings(playerIDs)
+
+	refunded := make(map[string]int64)
+	logRefund := func(playerID string, amount int64) {
+		log.Printf("Refunded $%d to player %s for interrupted round %s", amount, playerID, roundID)
+		refunded[playerID] += amount
+
+		event := Event{Sequence: len(events), Type: EventRefund, Time: time.Now(), PlayerID: playerID, Amount: amount}
+		events = append(events, event)
+		record, err := event.toRecord(roundID, channelID)
+		if err == nil {
+			err = repo.AppendEvent(ctx, record)
+		}
+		if err != nil {
+			log.Printf("Error saving refund event for round %s: %v", roundID, err)
+		}
+	}
+
+	for _, playerID := range playerIDs {
+		amount := stakes[playerID]
+		released, err := walletService.ReleaseHold(ctx, playerID, roundID)
+		if err != nil {
+			log.Printf("Error releasing the stakes held from player %s for round %s: %v", playerID, roundID, err)
+			return refunded, err
+		}
+
+		// Stakes taken before the wallet held them are paid back
+		if unheld := amount - released; unheld > 0 {
+			description := fmt.Sprintf("Blackjack refund: round %s was interrupted by a restart", roundID)
+			if err := walletService.AddFunds(ctx, playerID, channelID, unheld, entities.TransactionTypePushRefund, roundID, description); err != nil {
+				log.Printf("Error refunding $%d to player %s for round %s: %v", unheld, playerID, roundID, err)
+				return refunded, err
+			}
+			released += unheld
+		}
+		logRefund(playerID, released)
+	}
+
+	// Anything still held that the log doesn't know about goes back too
+	released, err := walletService.ReleaseRoundHolds(ctx, roundID)
+	if err != nil {
+		log.Printf("Error releasing the stakes held for round %s: %v", roundID, err)
+		return refunded, err
+	}
+	leftover := make([]string, 0, len(released))
+	for playerID := range released {
+		leftover = append(leftover, playerID)
+	}
+	sort.Strings(leftover)
+	for _, playerID := range leftover {
+		logRefund(playerID, released[playerID])
+	}
+
+	// The stakes are back with their players, the table's escrow is settled
+	if err := settleTable(ctx, walletService, channelID); err != nil {
+		return refunded, err
+	}
+	return refunded, nil
+}
@@ -0,0 +1,108 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestoresRound(t *testing.T) {
+	repo := game.NewMemoryRepository()
+	g := dealRound(t, repo, entities.NewSeededShuffler(entities.Seed{3}))
+	hand := g.Players[g.PlayerOrder[0]]
+	hand.SetDoubleDownBet(10)
+	g.SideBets[g.PlayerOrder[0]] = []*PlacedSideBet{{Type: SideBetInsurance, Amount: 5}}
+
+	data, err := g.Snapshot()
+	require.NoError(t, err)
+	restored, err := RestoreGame(data, repo, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, g.ID, restored.ID)
+	assert.Equal(t, g.State, restored.State)
+	assert.Equal(t, g.Rules, restored.Rules)
+	assert.Equal(t, g.Bets, restored.Bets)
+	assert.Equal(t, g.SideBets, restored.SideBets)
+	assert.Equal(t, g.PlayerOrder, restored.PlayerOrder)
+	assert.Equal(t, g.Deck, restored.Deck)
+	assert.Equal(t, g.WasShuffled(), restored.WasShuffled())
+	assert.Len(t, restored.Events, len(g.Events))
+	for handID, hand := range g.Players {
+		assert.Equal(t, hand, restored.Players[handID])
+	}
+
+	// Bet amounts in metadata come back as int64, not float64
+	restoredHand := restored.Players[g.PlayerOrder[0]]
+	assert.Equal(t, int64(10), restoredHand.GetDoubleDownBet())
+
+	// Both play the rest of the round the same way
+	g.repo = nil
+	playOut(t, g)
+	playOut(t, restored)
+	assert.Equal(t, g.Dealer.Cards, restored.Dealer.Cards)
+	for handID, hand := range g.Players {
+		assert.Equal(t, hand.Cards, restored.Players[handID].Cards)
+	}
+}
+
+func TestRestoreGameRejectsStaleSnapshot(t *testing.T) {
+	repo := game.NewMemoryRepository()
+	g := dealRound(t, repo, entities.NewSeededShuffler(entities.Seed{3}))
+	data, err := g.Snapshot()
+	require.NoError(t, err)
+
+	// The round moved on after the snapshot was taken
+	playOut(t, g)
+
+	_, err = RestoreGame(data, repo, nil)
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+
+	_, err = RestoreGame([]byte("not a game"), repo, nil)
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+}
+
+func TestRefundRound(t *testing.T) {
+	ctx := context.Background()
+	repo := game.NewMemoryRepository()
+	g := dealRound(t, repo, entities.NewSeededShuffler(entities.Seed{3}))
+	wallet := newStubWalletService()
+
+	refunded, err := RefundRound(ctx, repo, wallet, g.ChannelID, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"player1": 10, "player2": 10}, refunded)
+	assert.Equal(t, refunded, wallet.added)
+
+	// The refunds are in the log, so nobody is refunded twice
+	refunded, err = RefundRound(ctx, repo, wallet, g.ChannelID, g.ID)
+	require.NoError(t, err)
+	assert.Empty(t, refunded)
+	assert.Equal(t, map[string]int64{"player1": 10, "player2": 10}, wallet.added)
+}
+
+func TestRoundStakes(t *testing.T) {
+	events := []Event{
+		{Type: EventBetPlaced, PlayerID: "player1", Amount: 10},
+		{Type: EventBetCancelled, PlayerID: "player1"},
+		{Type: EventBetPlaced, PlayerID: "player1", Amount: 25},
+		{Type: EventBetPlaced, PlayerID: "player2", Amount: 10},
+		{Type: EventBetPlaced, PlayerID: "player3", Amount: 5},
+		{Type: EventSideBetPlaced, PlayerID: "player1", HandID: "player1", Amount: 5, SideBet: SideBetTwentyOnePlusThree},
+		{Type: EventSideBetPlaced, PlayerID: "player2", HandID: "player2", Amount: 5, SideBet: SideBetTwentyOnePlusThree},
+		{Type: EventSideBetSettled, PlayerID: "player1", HandID: "player1", Amount: 0, SideBet: SideBetTwentyOnePlusThree},
+		{Type: EventSplit, PlayerID: "player1", Amount: 25},
+		{Type: EventInsurance, PlayerID: "player2", Amount: 5},
+		{Type: EventSideBetPlaced, PlayerID: "player1", HandID: "hand-2", Amount: 10, SideBet: SideBetInsurance},
+		{Type: EventSideBetSettled, PlayerID: "player1", HandID: "hand-2", Amount: 0, SideBet: SideBetInsurance},
+		{Type: EventDoubleDown, PlayerID: "player2", Amount: 10},
+		{Type: EventPayout, PlayerID: "player3", Amount: 10},
+	}
+	assert.Equal(t, map[string]int64{"player1": 50, "player2": 30}, RoundStakes(events))
+
+	// A finished round has nothing left to refund
+	events = append(events, Event{Type: EventRoundComplete})
+	assert.Empty(t, RoundStakes(events))
+}
@@ -6,39 +6,59 @@ import (
 	"log"
 
 	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/google/uuid"
 )
 
 // IsEligibleForDoubleDown checks if a player is eligible for double down
 func (g *Game) IsEligibleForDoubleDown(playerID string) bool {
 	// Check if player has exactly 2 cards and hasn't already acted
 	hand, exists := g.Players[playerID]
-	if !exists || len(hand.Cards) != 2 || hand.IsDoubledDown() {
+	if !exists || len(hand.Cards) != 2 || hand.IsDoubledDown() || hand.Status != StatusPlaying {
 		return false
 	}
 
-	// Can't double down if already split
-	if hand.IsSplit() {
+	// Split hands may only double down if the table allows it
+	if hand.IsSplit() && !g.Rules.DoubleAfterSplit {
 		return false
 	}
 
 	return true
 }
 
-// IsEligibleForSplit checks if a player is eligible for split
+// IsEligibleForSplit checks if a hand is eligible for split
 func (g *Game) IsEligibleForSplit(playerID string) bool {
-	// Check if player has exactly 2 cards of the same rank
+	// Check if the hand has exactly 2 cards and is still in play
 	hand, exists := g.Players[playerID]
-	if !exists || len(hand.Cards) != 2 {
+	if !exists || len(hand.Cards) != 2 || hand.Status != StatusPlaying {
 		return false
 	}
 
-	// Can't split if already doubled down or split
-	if hand.IsDoubledDown() || hand.IsSplit() {
+	// Can't split if already doubled down or surrendered
+	if hand.IsDoubledDown() || hand.IsSurrendered() {
 		return false
 	}
 
-	// Check if the two cards have the same rank
-	return hand.Cards[0].Rank == hand.Cards[1].Rank
+	// Split aces can only be split again if the table allows it
+	if hand.IsSplitAces() && !g.Rules.ResplitAces {
+		return false
+	}
+
+	// Respect the table's limit on the number of hands split from one seat
+	if len(g.handsFromSeat(g.HandSeat(playerID))) >= g.Rules.MaxSplitHands {
+		return false
+	}
+
+	return g.isSplittablePair(hand.Cards[0], hand.Cards[1])
+}
+
+// isSplittablePair checks if two cards form a pair that can be split under the table rules
+func (g *Game) isSplittablePair(first, second *entities.Card) bool {
+	if first.Rank == second.Rank {
+		return true
+	}
+
+	// Some tables let any two ten-value cards be split, e.g. a King and a Queen
+	return g.Rules.SplitAnyTens && GetCardValue(first) == 10 && GetCardValue(second) == 10
 }
 
 // IsEligibleForInsurance checks if a player is eligible for insurance
@@ -48,9 +68,61 @@ func (g *Game) IsEligibleForInsurance() bool {
 		return false
 	}
 
+	// Once the dealer has peeked without finding blackjack, insurance can only lose
+	if g.DealerPeeked {
+		return false
+	}
+
 	return g.Dealer.Cards[0].Rank == entities.Ace
 }
 
+// IsEligibleForSurrender checks if a player may surrender their hand
+func (g *Game) IsEligibleForSurrender(playerID string) bool {
+	if !g.Rules.AllowsSurrender() {
+		return false
+	}
+
+	// Only an untouched two-card hand can be surrendered
+	hand, exists := g.Players[playerID]
+	if !exists || len(hand.Cards) != 2 || hand.Status != StatusPlaying {
+		return false
+	}
+
+	// Can't surrender a split or doubled hand, and nobody surrenders a blackjack
+	if hand.IsSplit() || hand.IsDoubledDown() || hand.IsSurrendered() || IsBlackjack(hand.Cards) {
+		return false
+	}
+
+	return true
+}
+
+// IsEligibleForEarlySurrender checks if a player may surrender before the dealer peeks
+func (g *Game) IsEligibleForEarlySurrender(playerID string) bool {
+	return g.Rules.Surrender == SurrenderEarly && g.IsEligibleForSurrender(playerID)
+}
+
+// hasInsuranceOptions checks if a hand has anything to decide before the dealer peeks
+func (g *Game) hasInsuranceOptions(playerID string) bool {
+	return g.IsEligibleForInsurance() || g.IsEligibleForEarlySurrender(playerID)
+}
+
+// hasSpecialBetOptions checks if a hand has anything to decide in the current phase
+func (g *Game) hasSpecialBetOptions(playerID string) bool {
+	if g.State == StateInsurance {
+		return g.hasInsuranceOptions(playerID)
+	}
+
+	return g.IsEligibleForDoubleDown(playerID) ||
+		g.IsEligibleForSplit(playerID) ||
+		g.IsEligibleForInsurance() ||
+		g.IsEligibleForSurrender(playerID)
+}
+
+// isTakingSpecialBets returns true while players are deciding on insurance or special bets
+func (g *Game) isTakingSpecialBets() bool {
+	return g.State == StateInsurance || g.State == StateSpecialBets
+}
+
 // DoubleDown performs a double down action for a player
 func (g *Game) DoubleDown(ctx context.Context, playerID string, walletService WalletService) error {
 	// Validate game state
@@ -58,27 +130,27 @@ func (g *Game) DoubleDown(ctx context.Context, playerID string, walletService Wa
 		return ErrInvalidAction
 	}
 
-	// Validate player turn
-	currentPlayerID, err := g.GetCurrentSpecialBetsPlayerID()
+	// Validate player turn, the current hand must belong to this player
+	handID, err := g.GetCurrentSpecialBetsPlayerID()
 	if err != nil {
 		return err
 	}
-	if currentPlayerID != playerID {
+	if g.HandOwner(handID) != playerID {
 		return ErrNotPlayerTurn
 	}
 
 	// Check if player is eligible for double down
-	if !g.IsEligibleForDoubleDown(playerID) {
+	if !g.IsEligibleForDoubleDown(handID) {
 		return ErrNotEligibleForDoubleDown
 	}
 
 	// Get the player's hand and bet amount
-	hand, exists := g.Players[playerID]
+	hand, exists := g.Players[handID]
 	if !exists {
 		return ErrPlayerNotFound
 	}
 
-	betAmount, exists := g.Bets[playerID]
+	betAmount, exists := g.Bets[handID]
 	if !exists {
 		return errors.New("no bet found for player")
 	}
@@ -89,8 +161,8 @@ func (g *Game) DoubleDown(ctx context.Context, playerID string, walletService Wa
 		return err
 	}
 
-	// Remove funds for the double down bet
-	err = walletService.RemoveFunds(ctx, playerID, betAmount, "Double down bet")
+	// Hold the double down bet until the round is paid
+	err = g.stake(ctx, walletService, playerID, handID, betAmount, entities.TransactionTypeBet, "Double down bet")
 	if err != nil {
 		return err
 	}
@@ -100,15 +172,8 @@ func (g *Game) DoubleDown(ctx context.Context, playerID string, walletService Wa
 	hand.SetDoubleDownBet(betAmount)
 
 	// Deal one more card to the player
-	newCard := g.Deck.Draw()
-	if newCard == nil {
-		// Reshuffle if deck is empty
-		g.Deck = entities.NewDeck()
-		g.Deck.Shuffle()
-		g.shuffled = true
-		newCard = g.Deck.Draw()
-	}
-	err = hand.AddCard(newCard)
+	g.emit(Event{Type: EventDoubleDown, PlayerID: playerID, HandID: handID, Amount: betAmount})
+	err = g.dealCard(handID, hand)
 	if err != nil {
 		// If there's an error adding the card, it's likely not a bust error
 		// since we're adding just one card to a valid hand
@@ -128,30 +193,32 @@ func (g *Game) DoubleDown(ctx context.Context, playerID string, walletService Wa
 	return g.AdvanceSpecialBetsTurn()
 }
 
-// Split performs a split action for a player
+// Split splits the current hand of a player into two hands. The player may keep
+// re-splitting new pairs up to the table's MaxSplitHands.
 func (g *Game) Split(ctx context.Context, playerID string, walletService WalletService) error {
 	// Validate game state
 	if g.State != StateSplitting {
 		return ErrInvalidAction
 	}
 
-	// Validate player turn
-	if g.GetCurrentSplittingPlayerID() != playerID {
+	// Validate player turn, the current hand must belong to this player
+	handID := g.GetCurrentSplittingPlayerID()
+	if handID == "" || g.HandOwner(handID) != playerID {
 		return ErrNotPlayerTurn
 	}
 
-	// Check if player is eligible for split
-	if !g.IsEligibleForSplit(playerID) {
+	// Check if the hand is eligible for split
+	if !g.IsEligibleForSplit(handID) {
 		return ErrNotEligibleForSplit
 	}
 
-	// Get the player's hand and bet amount
-	hand, exists := g.Players[playerID]
+	// Get the hand and bet amount
+	hand, exists := g.Players[handID]
 	if !exists {
 		return ErrPlayerNotFound
 	}
 
-	betAmount, exists := g.Bets[playerID]
+	betAmount, exists := g.Bets[handID]
 	if !exists {
 		return errors.New("no bet found for player")
 	}
@@ -162,121 +229,111 @@ func (g *Game) Split(ctx context.Context, playerID string, walletService WalletS
 		return err
 	}
 
-	// Remove funds for the split bet
-	err = walletService.RemoveFunds(ctx, playerID, betAmount, "Split bet")
+	// Hold the split bet on the new hand until the round is paid
+	splitHandID := g.newHandID()
+	err = g.stake(ctx, walletService, playerID, splitHandID, betAmount, entities.TransactionTypeBet, "Split bet")
 	if err != nil {
 		return err
 	}
 
 	// Create a new hand for the split
-	splitHandID := playerID + "_split"
 	splitHand := NewHand()
 
-	// Mark both hands as split
+	// Mark both hands as split and owned by the player
 	hand.SetSplit(true)
+	hand.SetOwnerID(playerID)
 	splitHand.SetSplit(true)
+	splitHand.SetOwnerID(playerID)
 
 	// Set parent-child relationship
-	splitHand.SetParentHandID(playerID)
+	splitHand.SetParentHandID(handID)
+	hand.SetSplitHandID(splitHandID)
 
 	// Move the second card to the split hand
 	secondCard := hand.Cards[1]
 	hand.Cards = hand.Cards[:1]
 	splitHand.AddCard(secondCard)
 
-	// Deal one more card to each hand
-	newCardForOriginal := g.Deck.Draw()
-	if newCardForOriginal == nil {
-		// Reshuffle if deck is empty
-		g.Deck = entities.NewDeck()
-		g.Deck.Shuffle()
-		g.shuffled = true
-		newCardForOriginal = g.Deck.Draw()
-	}
-	hand.AddCard(newCardForOriginal)
-
-	newCardForSplit := g.Deck.Draw()
-	if newCardForSplit == nil {
-		// Reshuffle if deck is empty
-		g.Deck = entities.NewDeck()
-		g.Deck.Shuffle()
-		g.shuffled = true
-		newCardForSplit = g.Deck.Draw()
-	}
-	splitHand.AddCard(newCardForSplit)
-
 	// Add the split hand to the game
 	g.Players[splitHandID] = splitHand
+	g.emit(Event{Type: EventSplit, PlayerID: playerID, HandID: handID, NewHandID: splitHandID, Amount: betAmount})
 
 	// Add the bet for the split hand
 	g.Bets[splitHandID] = betAmount
 
-	// Update the player order to include the split hand right after the original hand
-	// This ensures the player plays both hands in sequence
+	// Deal one more card to each hand
+	if err := g.dealCard(handID, hand); err != nil {
+		return err
+	}
+	if err := g.dealCard(splitHandID, splitHand); err != nil {
+		return err
+	}
+
+	// Update the player order to include the split hand right after the hand it came from
+	// This ensures the player plays all of their hands in sequence
 	newPlayerOrder := make([]string, 0, len(g.PlayerOrder)+1)
 	for _, id := range g.PlayerOrder {
 		newPlayerOrder = append(newPlayerOrder, id)
-		// Insert the split hand right after the original hand
-		if id == playerID {
+		if id == handID {
 			newPlayerOrder = append(newPlayerOrder, splitHandID)
 		}
 	}
 	g.PlayerOrder = newPlayerOrder
 
-	// Advance to the next player's turn
+	// Stay on this hand if it paired up again and can be re-split
+	if g.IsEligibleForSplit(handID) {
+		return nil
+	}
+
+	// Advance to the next hand, which may be the new split hand
 	return g.AdvanceSplittingTurn()
 }
 
-// PlaceInsurance performs an insurance bet for a player
-func (g *Game) PlaceInsurance(ctx context.Context, playerID string, walletService WalletService) error {
+// newHandID returns a unique ID for a new hand
+func (g *Game) newHandID() string {
+	// A split being played back keeps the hand ID it was given
+	if len(g.replayHandIDs) > 0 {
+		handID := g.replayHandIDs[0]
+		g.replayHandIDs = g.replayHandIDs[1:]
+		return handID
+	}
+	return "hand-" + uuid.New().String()
+}
+
+// Surrender gives up a player's hand. Half of the bet is refunded through the
+// wallet service when the round is settled. With late surrender the refund is
+// forfeited if the dealer turns out to have blackjack.
+func (g *Game) Surrender(playerID string) error {
 	// Validate game state
-	if g.State != StateSpecialBets {
+	if !g.isTakingSpecialBets() {
 		return ErrInvalidAction
 	}
 
-	// Validate player turn
-	currentPlayerID, err := g.GetCurrentSpecialBetsPlayerID()
+	// Validate player turn, the current hand must belong to this player
+	handID, err := g.GetCurrentSpecialBetsPlayerID()
 	if err != nil {
 		return err
 	}
-	if currentPlayerID != playerID {
+	if g.HandOwner(handID) != playerID {
 		return ErrNotPlayerTurn
 	}
 
-	// Check if player is eligible for insurance
-	if !g.IsEligibleForInsurance() {
-		return ErrNotEligibleForInsurance
+	// Check if player is eligible for surrender, only early surrender comes before the peek
+	if !g.IsEligibleForSurrender(handID) || (g.State == StateInsurance && !g.IsEligibleForEarlySurrender(handID)) {
+		return ErrNotEligibleForSurrender
 	}
 
-	// Get the player's hand and bet amount
-	hand, exists := g.Players[playerID]
+	hand, exists := g.Players[handID]
 	if !exists {
 		return ErrPlayerNotFound
 	}
 
-	betAmount, exists := g.Bets[playerID]
-	if !exists {
-		return errors.New("no bet found for player")
-	}
-
-	// Insurance bet is half the original bet
-	insuranceAmount := betAmount / 2
-
-	// Ensure player has enough funds for the insurance bet
-	_, _, err = walletService.EnsureFundsWithLoan(ctx, playerID, insuranceAmount, walletService.GetStandardLoanIncrement())
-	if err != nil {
+	// Mark the hand as surrendered, the player takes no further action
+	hand.SetSurrendered(true)
+	if err := hand.Stand(); err != nil {
 		return err
 	}
-
-	// Remove funds for the insurance bet
-	err = walletService.RemoveFunds(ctx, playerID, insuranceAmount, "Insurance bet")
-	if err != nil {
-		return err
-	}
-
-	// Mark the hand as having insurance and store the bet amount
-	hand.SetInsurance(true)
-	hand.SetInsuranceBet(insuranceAmount)
+	g.emit(Event{Type: EventSurrender, PlayerID: playerID, HandID: handID})
 
 	// Advance to the next player's turn
 	return g.AdvanceSpecialBetsTurn()
@@ -285,27 +342,28 @@ func (g *Game) PlaceInsurance(ctx context.Context, playerID string, walletServic
 // DeclineSpecialBet allows a player to decline any special betting options
 func (g *Game) DeclineSpecialBet(playerID string) error {
 	// Validate game state
-	if g.State != StateSpecialBets {
+	if !g.isTakingSpecialBets() {
 		return ErrInvalidAction
 	}
 
-	// Validate player turn
-	currentPlayerID, err := g.GetCurrentSpecialBetsPlayerID()
+	// Validate player turn, the current hand must belong to this player
+	handID, err := g.GetCurrentSpecialBetsPlayerID()
 	if err != nil {
 		return err
 	}
-	if currentPlayerID != playerID {
+	if g.HandOwner(handID) != playerID {
 		return ErrNotPlayerTurn
 	}
 
 	// Simply advance to the next player's turn
+	g.emit(Event{Type: EventDeclined, PlayerID: playerID, HandID: handID})
 	return g.AdvanceSpecialBetsTurn()
 }
 
-// AdvanceSpecialBetsTurn advances to the next player's turn for special bets
+// AdvanceSpecialBetsTurn advances to the next player's turn for insurance or special bets
 func (g *Game) AdvanceSpecialBetsTurn() error {
 	// Validate game state
-	if g.State != StateSpecialBets {
+	if !g.isTakingSpecialBets() {
 		return ErrInvalidAction
 	}
 
@@ -317,25 +375,16 @@ func (g *Game) AdvanceSpecialBetsTurn() error {
 
 	// Check if we've gone through all players
 	if g.CurrentSpecialBetsTurn >= len(playerIDs) {
+		// Insurance is settled, so the dealer can check for blackjack
+		if g.State == StateInsurance {
+			log.Printf("All players have had a chance at insurance, dealer peeks for blackjack")
+			return g.peekForBlackjack()
+		}
+
 		// All players have had a chance to make special bets
 		// Always transition to playing phase after special bets
 		log.Printf("All players have had a chance at special bets, transitioning to PLAYING state")
-		g.State = entities.StatePlaying
-		g.CurrentTurn = 0
-		
-		// Skip players who are already done (bust or stand)
-		// This ensures players who doubled down and automatically stood are skipped
-		for g.CheckPlayerDone(g.PlayerOrder[g.CurrentTurn]) && !g.CheckAllPlayersDone() {
-			log.Printf("Skipping player %s who is already done", g.PlayerOrder[g.CurrentTurn])
-			g.CurrentTurn = (g.CurrentTurn + 1) % len(g.PlayerOrder)
-		}
-		
-		// If all players are done after skipping, transition to dealer phase
-		if g.CheckAllPlayersDone() {
-			log.Printf("All players are done after special bets, transitioning to dealer phase")
-			g.State = entities.StateDealer
-		}
-		
+		g.beginPlayerTurns()
 		return nil
 	}
 
@@ -343,18 +392,16 @@ func (g *Game) AdvanceSpecialBetsTurn() error {
 	currentPlayerID := playerIDs[g.CurrentSpecialBetsTurn]
 
 	// If the player isn't eligible for any special bets, skip to the next player
-	if !g.IsEligibleForDoubleDown(currentPlayerID) &&
-		!g.IsEligibleForSplit(currentPlayerID) &&
-		!g.IsEligibleForInsurance() {
+	if !g.hasSpecialBetOptions(currentPlayerID) {
 		return g.AdvanceSpecialBetsTurn()
 	}
 
 	return nil
 }
 
-// GetCurrentSpecialBetsPlayerID returns the ID of the player whose turn it is to make special bets
+// GetCurrentSpecialBetsPlayerID returns the ID of the player whose turn it is to make insurance or special bets
 func (g *Game) GetCurrentSpecialBetsPlayerID() (string, error) {
-	if g.State != StateSpecialBets {
+	if !g.isTakingSpecialBets() {
 		return "", ErrInvalidAction
 	}
 
@@ -367,25 +414,9 @@ func (g *Game) GetCurrentSpecialBetsPlayerID() (string, error) {
 	return playerIDs[g.CurrentSpecialBetsTurn], nil
 }
 
-// getPlayerIDsInOrder returns the player IDs in the order they should take their turns
+// getPlayerIDsInOrder returns the hand IDs in the order they should take their turns
 func (g *Game) getPlayerIDsInOrder() []string {
-	// If we have a predefined player order, use that
-	if len(g.PlayerOrder) > 0 {
-		return g.PlayerOrder
-	}
-
-	// Otherwise, get the player IDs from the Players map
-	playerIDs := make([]string, 0, len(g.Players))
-	for playerID := range g.Players {
-		// Skip split hands when determining turn order
-		hand := g.Players[playerID]
-		if hand.IsSplit() && hand.GetParentHandID() != "" {
-			continue
-		}
-		playerIDs = append(playerIDs, playerID)
-	}
-
-	return playerIDs
+	return g.HandIDsInOrder()
 }
 
 // GetCurrentSplittingPlayerID returns the ID of the player whose turn it is to split
@@ -409,14 +440,20 @@ func (g *Game) AdvanceSplittingTurn() error {
 
 	// Check if we've gone through all players
 	if g.CurrentSpecialBetsTurn >= len(g.PlayerOrder) {
+		// Split aces only get the one card they were dealt
+		if !g.Rules.HitSplitAces {
+			for _, hand := range g.Players {
+				if hand.IsSplitAces() && hand.Status == StatusPlaying {
+					hand.Stand()
+				}
+			}
+		}
+
 		// All players have had a chance to split, move to special bets or playing state
 		if g.AnyPlayerEligibleForSpecialBets() {
-			g.State = StateSpecialBets
-			g.CurrentSpecialBetsTurn = 0
-		} else {
-			g.State = entities.StatePlaying
-			g.CurrentTurn = 0
+			return g.beginSpecialBets()
 		}
+		g.beginPlayerTurns()
 		return nil
 	}
 
@@ -439,10 +476,12 @@ func (g *Game) DeclineSplit(playerID string) error {
 	}
 
 	// Validate player turn
-	if g.GetCurrentSplittingPlayerID() != playerID {
+	handID := g.GetCurrentSplittingPlayerID()
+	if handID == "" || g.HandOwner(handID) != playerID {
 		return ErrNotPlayerTurn
 	}
 
 	// Simply advance to the next player's turn
+	g.emit(Event{Type: EventSplitDeclined, PlayerID: playerID, HandID: handID})
 	return g.AdvanceSplittingTurn()
 }
@@ -0,0 +1,257 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	walletService "github.com/fadedpez/tucoramirez/pkg/services/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDealtGame returns a game in the given state with the initial cards
+// dealt to player1 and the dealer
+func newDealtGame(rules RuleSet, state entities.GameState, playerCards, dealerCards []*entities.Card) *Game {
+	g := NewGame("test-channel", nil, rules, nil)
+	g.Players["player1"] = &Hand{Cards: playerCards, Status: StatusPlaying, Metadata: map[string]interface{}{}}
+	g.Dealer = &Hand{Cards: dealerCards, Status: StatusPlaying, Metadata: map[string]interface{}{}}
+	g.Bets["player1"] = 100
+	g.PlayerOrder = []string{"player1"}
+	g.State = state
+	return g
+}
+
+func TestSurrender(t *testing.T) {
+	hard16 := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}
+	dealer20 := []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Queen, Suit: entities.Diamonds},
+	}
+	dealerBlackjack := []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Ace, Suit: entities.Diamonds},
+	}
+
+	lateRules := DefaultRuleSet()
+	lateRules.Surrender = SurrenderLate
+	earlyRules := DefaultRuleSet()
+	earlyRules.Surrender = SurrenderEarly
+
+	tests := []struct {
+		name       string
+		rules      RuleSet
+		dealer     []*entities.Card
+		wantResult entities.StringResult
+		wantPayout int64
+	}{
+		{"late surrender refunds half", lateRules, dealer20, entities.StringResultSurrender, 50},
+		{"late surrender loses to dealer blackjack", lateRules, dealerBlackjack, entities.StringResultLose, 0},
+		{"early surrender refunds half against blackjack", earlyRules, dealerBlackjack, entities.StringResultSurrender, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newDealtGame(tt.rules, StateSpecialBets, hard16, tt.dealer)
+			require.True(t, g.IsEligibleForSurrender("player1"))
+			require.NoError(t, g.Surrender("player1"))
+
+			assert.True(t, g.Players["player1"].IsSurrendered())
+			assert.Equal(t, StatusStand, g.Players["player1"].Status)
+
+			g.State = entities.StateComplete
+			results, err := g.GetResults()
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.wantResult, results[0].Result)
+			assert.Equal(t, tt.wantPayout, results[0].Payout)
+		})
+	}
+}
+
+func TestSurrenderIsGivenBackNotWon(t *testing.T) {
+	ctx := context.Background()
+	service := walletService.NewService(walletRepo.NewMemoryRepository())
+	rules := DefaultRuleSet()
+	rules.Surrender = SurrenderLate
+
+	g := newDealtGame(rules, StateSpecialBets, []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}, []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Queen, Suit: entities.Diamonds},
+	})
+	_, _, err := service.GetOrCreateWallet(ctx, "player1")
+	require.NoError(t, err)
+	require.NoError(t, g.stake(ctx, service, "player1", "player1", 100, entities.TransactionTypeBet, "Blackjack bet"))
+	require.NoError(t, g.Surrender("player1"))
+	g.State = entities.StateComplete
+	require.NoError(t, g.ProcessPayouts(ctx, service))
+
+	// Half the bet comes back as a refund, the player didn't win anything
+	transactions, err := service.GetRecentTransactions(ctx, "player1", 1)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, entities.TransactionTypePushRefund, transactions[0].Type)
+	assert.Equal(t, int64(50), transactions[0].Amount)
+	assert.Equal(t, "Blackjack surrender", transactions[0].Description)
+}
+
+func TestSurrenderNotAllowed(t *testing.T) {
+	rules := DefaultRuleSet()
+	rules.Surrender = SurrenderNone
+
+	g := newDealtGame(rules, StateSpecialBets, []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Six, Suit: entities.Hearts},
+	}, []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Queen, Suit: entities.Diamonds},
+	})
+
+	assert.False(t, g.IsEligibleForSurrender("player1"))
+	assert.ErrorIs(t, g.Surrender("player1"), ErrNotEligibleForSurrender)
+}
+
+// stubWalletService is a wallet service that always has funds
+type stubWalletService struct {
+	added   map[string]int64
+	removed map[string]int64
+}
+
+func newStubWalletService() *stubWalletService {
+	return &stubWalletService{added: make(map[string]int64), removed: make(map[string]int64)}
+}
+
+func (w *stubWalletService) GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error) {
+	return &entities.Wallet{UserID: userID, Balance: 1000}, false, nil
+}
+
+func (w *stubWalletService) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	w.added[userID] += amount
+	return nil
+}
+
+func (w *stubWalletService) EnsureFundsWithLoan(ctx context.Context, userID string, requiredAmount int64, loanAmount int64) (*entities.Wallet, bool, error) {
+	return &entities.Wallet{UserID: userID, Balance: 1000}, false, nil
+}
+
+func (w *stubWalletService) GetStandardLoanIncrement() int64 {
+	return 100
+}
+
+func (w *stubWalletService) HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	w.removed[userID] += amount
+	return nil
+}
+
+func (w *stubWalletService) CaptureHold(ctx context.Context, userID, roundID string, amount int64) error {
+	return nil
+}
+
+func (w *stubWalletService) CaptureRoundHolds(ctx context.Context, roundID string) error {
+	return nil
+}
+
+func (w *stubWalletService) ReleaseHold(ctx context.Context, userID, roundID string) (int64, error) {
+	return 0, nil
+}
+
+func (w *stubWalletService) ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error {
+	w.removed[userID] -= amount
+	return nil
+}
+
+func (w *stubWalletService) ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+
+func (w *stubWalletService) SettleTable(ctx context.Context, tableID string) error {
+	return nil
+}
+
+func (w *stubWalletService) CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error) {
+	return 0, nil
+}
+
+// newSplittingGame returns a game waiting on player1's split decision, with the
+// given cards stacked on top of the deck
+func newSplittingGame(rules RuleSet, playerCards []*entities.Card, deckCards ...*entities.Card) *Game {
+	g := NewGame("test-channel", nil, rules, nil)
+	g.Players["player1"] = &Hand{Cards: playerCards, Status: StatusPlaying, Metadata: map[string]interface{}{}}
+	g.Dealer = &Hand{Cards: []*entities.Card{
+		{Rank: entities.Ten, Suit: entities.Clubs},
+		{Rank: entities.Seven, Suit: entities.Diamonds},
+	}, Status: StatusPlaying, Metadata: map[string]interface{}{}}
+	g.Bets["player1"] = 10
+	g.PlayerOrder = []string{"player1"}
+	g.Deck = &entities.Deck{Cards: deckCards}
+	g.State = StateSplitting
+	return g
+}
+
+func TestResplit(t *testing.T) {
+	eight := func(suit entities.Suit) *entities.Card { return &entities.Card{Rank: entities.Eight, Suit: suit} }
+	rules := DefaultRuleSet()
+	rules.MaxSplitHands = 3
+	wallet := newStubWalletService()
+
+	// The first split deals another eight to the original hand, so it can split again
+	g := newSplittingGame(rules, []*entities.Card{eight(entities.Spades), eight(entities.Hearts)},
+		eight(entities.Clubs), &entities.Card{Rank: entities.Two, Suit: entities.Clubs},
+		&entities.Card{Rank: entities.Three, Suit: entities.Clubs}, &entities.Card{Rank: entities.Four, Suit: entities.Clubs},
+		eight(entities.Diamonds))
+
+	require.NoError(t, g.Split(context.Background(), "player1", wallet))
+	assert.Equal(t, StateSplitting, g.State)
+	assert.Equal(t, "player1", g.GetCurrentSplittingPlayerID(), "original hand paired up again and should stay in turn")
+
+	require.NoError(t, g.Split(context.Background(), "player1", wallet))
+
+	hands := g.HandsForPlayer("player1")
+	require.Len(t, hands, 3)
+	for _, handID := range hands {
+		assert.Equal(t, "player1", g.HandOwner(handID))
+		assert.Equal(t, int64(10), g.Bets[handID])
+		assert.NotContains(t, handID, "_split")
+	}
+	assert.Equal(t, int64(20), wallet.removed["player1"])
+
+	// Three hands is the table limit, so the game moves on even though the third hand holds a pair
+	assert.NotEqual(t, StateSplitting, g.State)
+	assert.False(t, g.IsEligibleForSplit(hands[2]))
+}
+
+func TestSplitAcesGetOneCard(t *testing.T) {
+	rules := DefaultRuleSet()
+	g := newSplittingGame(rules, []*entities.Card{
+		{Rank: entities.Ace, Suit: entities.Spades},
+		{Rank: entities.Ace, Suit: entities.Hearts},
+	}, &entities.Card{Rank: entities.Five, Suit: entities.Clubs}, &entities.Card{Rank: entities.Nine, Suit: entities.Clubs})
+
+	require.NoError(t, g.Split(context.Background(), "player1", newStubWalletService()))
+
+	for _, handID := range g.HandsForPlayer("player1") {
+		hand := g.Players[handID]
+		assert.Len(t, hand.Cards, 2)
+		assert.Equal(t, StatusStand, hand.Status, "split aces only get one card")
+	}
+}
+
+func TestSplitAnyTens(t *testing.T) {
+	cards := []*entities.Card{
+		{Rank: entities.King, Suit: entities.Spades},
+		{Rank: entities.Queen, Suit: entities.Hearts},
+	}
+
+	rules := DefaultRuleSet()
+	assert.False(t, newSplittingGame(rules, cards).IsEligibleForSplit("player1"))
+
+	rules.SplitAnyTens = true
+	assert.True(t, newSplittingGame(rules, cards).IsEligibleForSplit("player1"))
+}
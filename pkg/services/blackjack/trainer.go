@@ -0,0 +1,240 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+)
+
+var (
+	ErrQuestionPending = errors.New("a count question is waiting for an answer")
+	ErrNoQuestion      = errors.New("no count question to answer")
+)
+
+// CountQuestion is what the counting trainer asks for
+type CountQuestion string
+
+const (
+	QuestionRunningCount CountQuestion = "running"
+	QuestionTrueCount    CountQuestion = "true"
+)
+
+const (
+	TrainerHands           = 3   // Hands dealt each round besides the dealer's
+	TrainerMinRoundsAsked  = 2   // Fewest rounds dealt before the trainer asks for the count
+	TrainerMaxRoundsAsked  = 5   // Most rounds dealt before the trainer asks for the count
+	TrueCountTolerance     = 0.5 // How far a true count answer can be off, nobody divides exactly at the table
+	TrainerRecentAnswers   = 20  // Answers counted in a player's recent accuracy
+	trainerHitProbability  = 0.4 // Chance a hand takes another card, so rounds aren't all the same size
+	trainerMaxCardsPerHand = 4
+)
+
+// CountTrainer deals a shoe round after round and now and then asks the
+// player for the count. It's single-player and never touches a wallet.
+type CountTrainer struct {
+	PlayerID string
+	System   CountSystem
+	Rules    RuleSet
+	Deck     *entities.Deck
+	Running  int                // The running count of the cards dealt from the shoe
+	Round    [][]*entities.Card // The last round's hands, the dealer's last
+	Question CountQuestion      // What the trainer is waiting to hear, empty between questions
+	Rounds   int                // Rounds dealt this session
+	Asked    int                // Questions answered this session
+	Correct  int                // Of those, the ones the player got right
+
+	repo             game.Repository
+	shuffler         entities.Shuffler
+	roundsToQuestion int
+	shuffled         bool // Whether a new shoe came out during the round being dealt
+}
+
+// CountAnswer is how a player's answer was judged
+type CountAnswer struct {
+	Question CountQuestion
+	Answer   float64
+	Count    float64 // What the count really was
+	Correct  bool
+}
+
+// NewCountTrainer creates a trainer with a fresh shoe for the rules. Answers
+// are saved to the repository if there is one.
+func NewCountTrainer(playerID string, system CountSystem, rules RuleSet, repo game.Repository, shuffler entities.Shuffler) *CountTrainer {
+	if shuffler == nil {
+		shuffler = entities.NewCryptoShuffler()
+	}
+	// The drill deals its own hands and never splits, so the cut card only has to leave enough for those
+	rules.MaxPlayers = TrainerHands
+	rules.MaxSplitHands = 1
+	t := &CountTrainer{
+		PlayerID: playerID,
+		System:   system,
+		Rules:    rules,
+		repo:     repo,
+		shuffler: shuffler,
+	}
+	t.reshuffle()
+	t.roundsToQuestion = t.nextQuestionIn()
+	return t
+}
+
+// reshuffle brings out a fresh shoe, which starts the count over
+func (t *CountTrainer) reshuffle() {
+	t.Deck = NewShoe(t.Rules, t.shuffler)
+	t.shuffled = true
+	// A burned card is never seen, so it isn't counted
+	t.Running = t.System.InitialCount(t.Rules.Decks)
+}
+
+// nextQuestionIn picks how many rounds go by before the next question
+func (t *CountTrainer) nextQuestionIn() int {
+	return TrainerMinRoundsAsked + rand.IntN(TrainerMaxRoundsAsked-TrainerMinRoundsAsked+1)
+}
+
+// draw deals the next card face up and counts it. A shoe that runs out
+// mid-round is replaced, and the count starts over with the new shoe.
+func (t *CountTrainer) draw() *entities.Card {
+	card := t.Deck.Draw()
+	if card == nil {
+		t.reshuffle()
+		card = t.Deck.Draw()
+	}
+	t.Running += t.System.Tag(card)
+	return card
+}
+
+// Deal deals the next round and returns true if the shoe was reshuffled for
+// it. Once enough rounds have gone by it sets a question, and no more rounds
+// are dealt until it's answered.
+func (t *CountTrainer) Deal() (bool, error) {
+	if t.Question != "" {
+		return false, ErrQuestionPending
+	}
+
+	t.shuffled = false
+	if ShouldReshuffle(t.Deck, t.Rules) {
+		t.reshuffle()
+	}
+
+	hands := make([][]*entities.Card, TrainerHands+1)
+	for deal := 0; deal < 2; deal++ {
+		for i := range hands {
+			hands[i] = append(hands[i], t.draw())
+		}
+	}
+	for i := range hands {
+		for len(hands[i]) < trainerMaxCardsPerHand && GetBestScore(hands[i]) < 21 && rand.Float64() < trainerHitProbability {
+			hands[i] = append(hands[i], t.draw())
+		}
+	}
+	t.Round = hands
+	t.Rounds++
+
+	t.roundsToQuestion--
+	if t.roundsToQuestion <= 0 {
+		t.Question = QuestionRunningCount
+		// Balanced systems are also asked for the true count
+		if t.System.Balanced() && rand.IntN(2) == 0 {
+			t.Question = QuestionTrueCount
+		}
+		t.roundsToQuestion = t.nextQuestionIn()
+	}
+	return t.shuffled, nil
+}
+
+// DecksLeft returns the decks left in the shoe, the way a player eyes the discard tray
+func (t *CountTrainer) DecksLeft() float64 {
+	return float64(len(t.Deck.Cards)) / 52
+}
+
+// TrueCount returns the true count of the shoe
+func (t *CountTrainer) TrueCount() float64 {
+	return t.System.TrueCount(t.Running, len(t.Deck.Cards))
+}
+
+// Answer judges the player's answer to the question and saves it. Running
+// counts have to be exact, true counts within TrueCountTolerance.
+func (t *CountTrainer) Answer(ctx context.Context, answer float64) (*CountAnswer, error) {
+	if t.Question == "" {
+		return nil, ErrNoQuestion
+	}
+
+	result := &CountAnswer{Question: t.Question, Answer: answer}
+	switch t.Question {
+	case QuestionTrueCount:
+		result.Count = t.TrueCount()
+		result.Correct = math.Abs(answer-result.Count) <= TrueCountTolerance
+	default:
+		result.Count = float64(t.Running)
+		result.Correct = answer == result.Count
+	}
+
+	t.Question = ""
+	t.Asked++
+	if result.Correct {
+		t.Correct++
+	}
+
+	if t.repo != nil {
+		err := t.repo.SaveCountDrill(ctx, &game.CountDrillRecord{
+			PlayerID:   t.PlayerID,
+			System:     t.System.Name,
+			Question:   string(result.Question),
+			Answer:     result.Answer,
+			Count:      result.Count,
+			Correct:    result.Correct,
+			AnsweredAt: time.Now(),
+		})
+		if err != nil {
+			// The answer still counts for the session
+			log.Printf("Error saving count drill for player %s: %v", t.PlayerID, err)
+		}
+	}
+	return result, nil
+}
+
+// CountAccuracy is how often a player got the count right
+type CountAccuracy struct {
+	Answered int
+	Correct  int
+}
+
+// Rate returns the share of answers that were right, from 0 to 1
+func (a CountAccuracy) Rate() float64 {
+	if a.Answered == 0 {
+		return 0
+	}
+	return float64(a.Correct) / float64(a.Answered)
+}
+
+// CountDrillAccuracy works out a player's accuracy with a count system from
+// their saved answers, over all of them and over the last TrainerRecentAnswers
+func CountDrillAccuracy(records []*game.CountDrillRecord, system string) (all CountAccuracy, recent CountAccuracy) {
+	var answers []bool
+	for _, record := range records {
+		if record.System == system {
+			answers = append(answers, record.Correct)
+		}
+	}
+
+	for i, correct := range answers {
+		all.Answered++
+		recentAnswer := i >= len(answers)-TrainerRecentAnswers
+		if recentAnswer {
+			recent.Answered++
+		}
+		if correct {
+			all.Correct++
+			if recentAnswer {
+				recent.Correct++
+			}
+		}
+	}
+	return all, recent
+}
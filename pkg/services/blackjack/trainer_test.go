@@ -0,0 +1,113 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dealUntilAsked deals rounds until the trainer asks for the count
+func dealUntilAsked(t *testing.T, trainer *CountTrainer) {
+	for rounds := 0; trainer.Question == ""; rounds++ {
+		require.Less(t, rounds, TrainerMaxRoundsAsked, "trainer never asked for the count")
+		_, err := trainer.Deal()
+		require.NoError(t, err)
+	}
+}
+
+func TestCountTrainer(t *testing.T) {
+	ctx := context.Background()
+	repo := game.NewMemoryRepository()
+	trainer := NewCountTrainer("player1", KO, DefaultRuleSet(), repo, entities.NewSeededShuffler(entities.Seed{1}))
+
+	dealUntilAsked(t, trainer)
+	assert.Len(t, trainer.Round, TrainerHands+1)
+	// KO is unbalanced, so it's only ever asked for the running count
+	assert.Equal(t, QuestionRunningCount, trainer.Question)
+
+	// The count is what the dealt cards add up to
+	dealt := trainer.Rules.ShoeSize() - len(trainer.Deck.Cards)
+	shoe := entities.NewSeededShuffler(entities.Seed{1})
+	fresh := NewShoe(trainer.Rules, shoe)
+	assert.Equal(t, KO.RunningCount(fresh.Cards[:dealt-fresh.Dealt], trainer.Rules.Decks), trainer.Running)
+
+	// No more cards until the question is answered
+	_, err := trainer.Deal()
+	assert.ErrorIs(t, err, ErrQuestionPending)
+
+	answer, err := trainer.Answer(ctx, float64(trainer.Running+1))
+	require.NoError(t, err)
+	assert.False(t, answer.Correct)
+	assert.Equal(t, float64(trainer.Running), answer.Count)
+
+	_, err = trainer.Answer(ctx, 0)
+	assert.ErrorIs(t, err, ErrNoQuestion)
+
+	dealUntilAsked(t, trainer)
+	answer, err = trainer.Answer(ctx, float64(trainer.Running))
+	require.NoError(t, err)
+	assert.True(t, answer.Correct)
+	assert.Equal(t, 2, trainer.Asked)
+	assert.Equal(t, 1, trainer.Correct)
+
+	// Both answers are kept for accuracy over time
+	records, err := repo.GetCountDrills(ctx, "player1")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, CountSystemKO, records[0].System)
+	all, recent := CountDrillAccuracy(records, CountSystemKO)
+	assert.Equal(t, CountAccuracy{Answered: 2, Correct: 1}, all)
+	assert.Equal(t, all, recent)
+	assert.InDelta(t, 0.5, all.Rate(), 0.001)
+}
+
+func TestCountTrainerTrueCountTolerance(t *testing.T) {
+	trainer := NewCountTrainer("player1", HiLo, DefaultRuleSet(), nil, nil)
+	trainer.Running = 6
+	trainer.Deck.Cards = trainer.Deck.Cards[:78]
+
+	trainer.Question = QuestionTrueCount
+	answer, err := trainer.Answer(context.Background(), 3.5)
+	require.NoError(t, err)
+	assert.True(t, answer.Correct)
+	assert.InDelta(t, 4.0, answer.Count, 0.001)
+
+	trainer.Question = QuestionTrueCount
+	answer, err = trainer.Answer(context.Background(), 3)
+	require.NoError(t, err)
+	assert.False(t, answer.Correct)
+}
+
+func TestCountTrainerReshuffles(t *testing.T) {
+	trainer := NewCountTrainer("player1", HiLo, DefaultRuleSet(), nil, nil)
+	trainer.Deck.Dealt = trainer.Deck.CutCard
+	trainer.Running = 12
+
+	reshuffled, err := trainer.Deal()
+	require.NoError(t, err)
+	assert.True(t, reshuffled)
+
+	// The count starts over with the new shoe
+	var dealt []*entities.Card
+	for _, hand := range trainer.Round {
+		dealt = append(dealt, hand...)
+	}
+	assert.Equal(t, HiLo.RunningCount(dealt, trainer.Rules.Decks), trainer.Running)
+}
+
+func TestCountDrillAccuracy(t *testing.T) {
+	var records []*game.CountDrillRecord
+	for i := 0; i < TrainerRecentAnswers+10; i++ {
+		// Wrong for the first ten, right after that
+		records = append(records, &game.CountDrillRecord{System: CountSystemHiLo, Correct: i >= 10})
+	}
+	records = append(records, &game.CountDrillRecord{System: CountSystemOmegaII})
+
+	all, recent := CountDrillAccuracy(records, CountSystemHiLo)
+	assert.Equal(t, CountAccuracy{Answered: TrainerRecentAnswers + 10, Correct: TrainerRecentAnswers}, all)
+	assert.Equal(t, CountAccuracy{Answered: TrainerRecentAnswers, Correct: TrainerRecentAnswers}, recent)
+}
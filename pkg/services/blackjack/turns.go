@@ -0,0 +1,216 @@
+package blackjack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+var (
+	ErrInvalidTurnTimers = errors.New("invalid turn timers")
+	ErrNoTurnToTimeOut   = errors.New("no player turn to time out")
+)
+
+// TurnTimers sets how long a player gets to act in each phase before the
+// default is played for them. A zero duration gives the phase no time limit.
+type TurnTimers struct {
+	Betting     time.Duration // Default: sit the round out
+	Splitting   time.Duration // Default: keep the pair together
+	SpecialBets time.Duration // Insurance, surrender and doubling down. Default: decline
+	Playing     time.Duration // Default: stand
+}
+
+// DefaultTurnTimers returns the turn limits Tuco plays with
+func DefaultTurnTimers() TurnTimers {
+	return TurnTimers{
+		Betting:     60 * time.Second,
+		Splitting:   30 * time.Second,
+		SpecialBets: 30 * time.Second,
+		Playing:     45 * time.Second,
+	}
+}
+
+// ParseTurnTimers reads turn timers as comma separated phase=duration pairs,
+// e.g. "betting=90s,playing=1m". Phases left out keep their default and a
+// duration of 0 turns the phase's timer off.
+func ParseTurnTimers(spec string) (TurnTimers, error) {
+	timers := DefaultTurnTimers()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		phase, value, found := strings.Cut(entry, "=")
+		if !found {
+			return TurnTimers{}, fmt.Errorf("%w: expected phase=duration, got %q", ErrInvalidTurnTimers, entry)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || duration < 0 {
+			return TurnTimers{}, fmt.Errorf("%w: bad duration %q", ErrInvalidTurnTimers, value)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(phase)) {
+		case "betting":
+			timers.Betting = duration
+		case "splitting":
+			timers.Splitting = duration
+		case "special_bets":
+			timers.SpecialBets = duration
+		case "playing":
+			timers.Playing = duration
+		default:
+			return TurnTimers{}, fmt.Errorf("%w: unknown phase %q", ErrInvalidTurnTimers, phase)
+		}
+	}
+	return timers, nil
+}
+
+// forState returns how long a turn lasts in a game state
+func (t TurnTimers) forState(state entities.GameState) time.Duration {
+	switch state {
+	case entities.StateBetting:
+		return t.Betting
+	case StateSplitting:
+		return t.Splitting
+	case StateInsurance, StateSpecialBets:
+		return t.SpecialBets
+	case entities.StatePlaying:
+		return t.Playing
+	}
+	return 0
+}
+
+// CurrentTurnHandID returns the hand that has to act next, or an empty string
+// if the game isn't waiting on a player
+func (g *Game) CurrentTurnHandID() string {
+	switch g.State {
+	case entities.StateBetting:
+		if g.CurrentBettingPlayer < len(g.PlayerOrder) {
+			return g.PlayerOrder[g.CurrentBettingPlayer]
+		}
+	case StateSplitting:
+		return g.GetCurrentSplittingPlayerID()
+	case StateInsurance, StateSpecialBets:
+		handID, err := g.GetCurrentSpecialBetsPlayerID()
+		if err == nil {
+			return handID
+		}
+	case entities.StatePlaying:
+		handID, err := g.GetCurrentTurnPlayerID()
+		if err == nil {
+			return handID
+		}
+	}
+	return ""
+}
+
+// RefreshTurnDeadline starts the turn clock again if anything happened since
+// it was last started, so each decision gets the phase's full time
+func (g *Game) RefreshTurnDeadline(now time.Time) {
+	handID := g.CurrentTurnHandID()
+	if handID == g.turnHandID && len(g.Events) == g.turnEvents && g.State == g.turnState {
+		return
+	}
+	g.turnHandID = handID
+	g.turnEvents = len(g.Events)
+	g.turnState = g.State
+	g.turnStarted = now
+
+	timeout := g.Timers.forState(g.State)
+	if handID == "" || timeout == 0 {
+		g.TurnDeadline = time.Time{}
+		return
+	}
+	g.TurnDeadline = now.Add(timeout)
+}
+
+// TurnExpired returns true if the current player ran out of time
+func (g *Game) TurnExpired(now time.Time) bool {
+	return !g.TurnDeadline.IsZero() && !now.Before(g.TurnDeadline)
+}
+
+// TimeOutTurn plays the default for the player whose turn it is: they sit out
+// the round if they haven't bet, keep their pair, decline special bets or
+// stand. It returns the player who timed out.
+func (g *Game) TimeOutTurn(ctx context.Context, walletService WalletService) (string, error) {
+	handID := g.CurrentTurnHandID()
+	if handID == "" {
+		return "", ErrNoTurnToTimeOut
+	}
+	playerID := g.HandOwner(handID)
+	g.TurnDeadline = time.Time{}
+
+	log.Printf("Player %s timed out in %s in channel %s", playerID, g.State, g.ChannelID)
+	g.emit(Event{Type: EventTimedOut, PlayerID: playerID, HandID: handID})
+
+	switch g.State {
+	case entities.StateBetting:
+		return playerID, g.SitOut(ctx, handID, walletService)
+	case StateSplitting:
+		return playerID, g.DeclineSplit(playerID)
+	case StateInsurance, StateSpecialBets:
+		return playerID, g.DeclineSpecialBet(playerID)
+	case entities.StatePlaying:
+		return playerID, g.Stand(playerID)
+	}
+	return playerID, ErrNoTurnToTimeOut
+}
+
+// SitOut takes a seat that hasn't bet out of the round, a player's first seat
+// by their own ID, and gives back any side bets placed on it. The remaining
+// players are dealt in once they've all bet, and a round nobody bet on simply
+// ends.
+func (g *Game) SitOut(ctx context.Context, seatID string, walletService WalletService) error {
+	if g.State != entities.StateBetting {
+		return ErrInvalidAction
+	}
+	if _, exists := g.Players[seatID]; !exists {
+		return ErrPlayerNotFound
+	}
+	if _, hasBet := g.Bets[seatID]; hasBet {
+		return ErrPlayerAlreadyBet
+	}
+
+	playerID := g.HandOwner(seatID)
+	for _, placed := range g.SideBets[seatID] {
+		if err := walletService.ReleaseFromHold(ctx, playerID, g.ID, placed.Amount); err != nil {
+			return fmt.Errorf("error giving back %s side bet: %w", placed.Type, err)
+		}
+		g.emit(Event{Type: EventSideBetVoided, PlayerID: playerID, HandID: seatID, Amount: placed.Amount, SideBet: placed.Type})
+		log.Printf("Gave player %s back their $%d %s side bet on seat %s in channel %s", playerID, placed.Amount, placed.Type, seatID, g.ChannelID)
+	}
+	delete(g.SideBets, seatID)
+
+	g.emit(Event{Type: EventSatOut, PlayerID: playerID, HandID: seatID})
+	delete(g.Players, seatID)
+	for index, seatedID := range g.PlayerOrder {
+		if seatedID != seatID {
+			continue
+		}
+		g.PlayerOrder = append(g.PlayerOrder[:index], g.PlayerOrder[index+1:]...)
+		if index < g.CurrentBettingPlayer {
+			g.CurrentBettingPlayer--
+		}
+		break
+	}
+	if g.CurrentBettingPlayer >= len(g.PlayerOrder) {
+		g.CurrentBettingPlayer = 0
+	}
+
+	if len(g.Players) == 0 {
+		log.Printf("Nobody is left to bet in channel %s, ending the round", g.ChannelID)
+		g.State = entities.StateComplete
+		g.PayoutsProcessed = true
+		g.emit(Event{Type: EventRoundComplete})
+		return nil
+	}
+	if g.CheckAllBetsPlaced() {
+		return g.Start()
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package blackjack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	walletService "github.com/fadedpez/tucoramirez/pkg/services/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTurnTimers(t *testing.T) {
+	timers, err := ParseTurnTimers("betting=90s, playing=0")
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timers.Betting)
+	assert.Equal(t, DefaultTurnTimers().Splitting, timers.Splitting)
+	assert.Zero(t, timers.Playing)
+
+	for _, spec := range []string{"betting", "betting=soon", "napping=10s", "playing=-5s"} {
+		_, err := ParseTurnTimers(spec)
+		assert.ErrorIs(t, err, ErrInvalidTurnTimers, "spec %q", spec)
+	}
+}
+
+func TestTurnDeadlineRestartsOnEveryDecision(t *testing.T) {
+	g := NewGame("test-channel", game.NewMemoryRepository(), DefaultRuleSet(), nil)
+	g.Timers = DefaultTurnTimers()
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.AddPlayer("player2"))
+	require.NoError(t, g.Start())
+
+	start := time.Now()
+	g.RefreshTurnDeadline(start)
+	assert.Equal(t, start.Add(g.Timers.Betting), g.TurnDeadline)
+
+	// Nothing happened, the clock keeps running
+	g.RefreshTurnDeadline(start.Add(10 * time.Second))
+	assert.Equal(t, start.Add(g.Timers.Betting), g.TurnDeadline)
+	assert.False(t, g.TurnExpired(start.Add(59*time.Second)))
+	assert.True(t, g.TurnExpired(start.Add(60*time.Second)))
+
+	// The next player gets the full time
+	require.NoError(t, g.PlaceBet(g.PlayerOrder[0], 10))
+	later := start.Add(30 * time.Second)
+	g.RefreshTurnDeadline(later)
+	assert.Equal(t, later.Add(g.Timers.Betting), g.TurnDeadline)
+
+	// A phase without a limit never times out
+	require.NoError(t, g.SitOut(context.Background(), g.PlayerOrder[1], newStubWalletService()))
+	g.Timers = TurnTimers{}
+	g.RefreshTurnDeadline(later)
+	assert.True(t, g.TurnDeadline.IsZero())
+	assert.False(t, g.TurnExpired(later.Add(time.Hour)))
+}
+
+func TestTimeOutTurnPlaysDefaults(t *testing.T) {
+	repo := game.NewMemoryRepository()
+	g := NewGame("test-channel", repo, DefaultRuleSet(), entities.NewSeededShuffler(entities.Seed{3}))
+	for _, playerID := range []string{"player1", "player2", "player3"} {
+		require.NoError(t, g.AddPlayer(playerID))
+	}
+	require.NoError(t, g.Start())
+
+	// The first player to bet sits the round out, the others are dealt in
+	sleeper := g.PlayerOrder[0]
+	playerID, err := g.TimeOutTurn(context.Background(), newStubWalletService())
+	require.NoError(t, err)
+	assert.Equal(t, sleeper, playerID)
+	assert.NotContains(t, g.Players, sleeper)
+	assert.NotContains(t, g.PlayerOrder, sleeper)
+	for _, playerID := range append([]string(nil), g.PlayerOrder...) {
+		require.NoError(t, g.PlaceBet(playerID, 10))
+	}
+	require.NotEqual(t, entities.StateBetting, g.State)
+
+	// Every other phase times out until the round is over
+	for g.CurrentTurnHandID() != "" {
+		state := g.State
+		handID := g.CurrentTurnHandID()
+		_, err := g.TimeOutTurn(context.Background(), newStubWalletService())
+		require.NoError(t, err, "timing out in %s", state)
+		if state == entities.StatePlaying {
+			assert.Equal(t, StatusStand, g.Players[handID].Status)
+		}
+	}
+	assert.Contains(t, []entities.GameState{entities.StateDealer, entities.StateComplete}, g.State)
+
+	// The round can still be rebuilt from its events
+	rebuilt, err := RebuildGame(g.ChannelID, g.ID, g.Rules, g.Events)
+	require.NoError(t, err)
+	assert.Equal(t, g.PlayerOrder, rebuilt.PlayerOrder)
+	assert.Equal(t, g.State, rebuilt.State)
+}
+
+func TestEveryoneSittingOutEndsRound(t *testing.T) {
+	g := NewGame("test-channel", nil, DefaultRuleSet(), nil)
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.Start())
+
+	require.NoError(t, g.SitOut(context.Background(), "player1", newStubWalletService()))
+	assert.Equal(t, entities.StateComplete, g.State)
+	assert.True(t, g.PayoutsProcessed)
+
+	_, err := g.TimeOutTurn(context.Background(), newStubWalletService())
+	assert.ErrorIs(t, err, ErrNoTurnToTimeOut)
+}
+
+func TestTimingOutGivesBackSideBets(t *testing.T) {
+	ctx := context.Background()
+	service := walletService.NewService(walletRepo.NewMemoryRepository())
+	g := NewGame("test-channel", game.NewMemoryRepository(), DefaultRuleSet(), nil)
+	require.NoError(t, g.AddPlayer("player1"))
+	require.NoError(t, g.AddPlayer("player2"))
+	require.NoError(t, g.Start())
+
+	// A player who put down a side bet and fell asleep gets it back
+	sleeper, waiting := g.PlayerOrder[0], g.PlayerOrder[1]
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, sleeper, 5, service))
+	require.NoError(t, g.PlaceSideBet(ctx, SideBetTwentyOnePlusThree, waiting, 5, service))
+	_, err := g.TimeOutTurn(ctx, service)
+	require.NoError(t, err)
+	assert.NotContains(t, g.SideBets, sleeper)
+	balance, err := service.GetBalance(ctx, sleeper)
+	require.NoError(t, err)
+	assert.Equal(t, walletService.StartingBalance, balance)
+	assert.Equal(t, map[string]int64{waiting: 5}, RoundStakes(g.Events))
+
+	// Nobody is left, so nothing is held for the round
+	_, err = g.TimeOutTurn(ctx, service)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StateComplete, g.State)
+	holds, err := service.GetOpenHolds(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, holds)
+	balance, err = service.GetBalance(ctx, waiting)
+	require.NoError(t, err)
+	assert.Equal(t, walletService.StartingBalance, balance)
+	assert.Empty(t, RoundStakes(g.Events))
+}
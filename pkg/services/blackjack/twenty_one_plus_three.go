@@ -0,0 +1,152 @@
+package blackjack
+
+import (
+	"sort"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// SideBetTwentyOnePlusThree names the 21+3 side bet in events and records
+const SideBetTwentyOnePlusThree = "twenty_one_plus_three"
+
+// PokerHand is the three card poker hand a 21+3 side bet is judged on
+type PokerHand string
+
+const (
+	PokerHandNone          PokerHand = "none"
+	PokerHandFlush         PokerHand = "flush"
+	PokerHandStraight      PokerHand = "straight"
+	PokerHandThreeOfAKind  PokerHand = "three_of_a_kind"
+	PokerHandStraightFlush PokerHand = "straight_flush"
+	PokerHandSuitedTrips   PokerHand = "suited_trips"
+)
+
+// String returns the hand as Tuco calls it at the table
+func (p PokerHand) String() string {
+	switch p {
+	case PokerHandFlush:
+		return "Flush"
+	case PokerHandStraight:
+		return "Straight"
+	case PokerHandThreeOfAKind:
+		return "Three of a Kind"
+	case PokerHandStraightFlush:
+		return "Straight Flush"
+	case PokerHandSuitedTrips:
+		return "Suited Trips"
+	}
+	return "Nothing"
+}
+
+// PokerPayTable maps each winning poker hand to what it pays to one
+type PokerPayTable map[PokerHand]int64
+
+// DefaultTwentyOnePlusThreePays returns the 21+3 pay table Tuco uses
+func DefaultTwentyOnePlusThreePays() PokerPayTable {
+	return PokerPayTable{
+		PokerHandSuitedTrips:   100,
+		PokerHandStraightFlush: 40,
+		PokerHandThreeOfAKind:  30,
+		PokerHandStraight:      10,
+		PokerHandFlush:         5,
+	}
+}
+
+// pokerRank returns a card's rank for straights, with the Ace high
+func pokerRank(card *entities.Card) int {
+	switch card.Rank {
+	case entities.Ace:
+		return 14
+	case entities.King:
+		return 13
+	case entities.Queen:
+		return 12
+	case entities.Jack:
+		return 11
+	}
+	return GetCardValue(card)
+}
+
+// EvaluatePokerHand judges three cards as a poker hand. An Ace plays high or
+// low in a straight, so A-2-3 and Q-K-A both count.
+func EvaluatePokerHand(cards []*entities.Card) PokerHand {
+	if len(cards) != 3 {
+		return PokerHandNone
+	}
+
+	ranks := make([]int, 0, len(cards))
+	for _, card := range cards {
+		ranks = append(ranks, pokerRank(card))
+	}
+	sort.Ints(ranks)
+
+	flush := cards[0].Suit == cards[1].Suit && cards[1].Suit == cards[2].Suit
+	trips := ranks[0] == ranks[2]
+	straight := (ranks[1] == ranks[0]+1 && ranks[2] == ranks[1]+1) ||
+		(ranks[0] == 2 && ranks[1] == 3 && ranks[2] == 14)
+
+	switch {
+	case trips && flush:
+		return PokerHandSuitedTrips
+	case straight && flush:
+		return PokerHandStraightFlush
+	case trips:
+		return PokerHandThreeOfAKind
+	case straight:
+		return PokerHandStraight
+	case flush:
+		return PokerHandFlush
+	}
+	return PokerHandNone
+}
+
+// TwentyOnePlusThree is the 21+3 side bet, a three card poker hand made of the
+// player's first two cards and the dealer's up-card
+type TwentyOnePlusThree struct {
+	Pays   PokerPayTable // What each poker hand pays to one
+	MinBet int64         // Smallest side bet taken
+	MaxBet int64         // Largest side bet taken, 0 for no limit
+}
+
+// Name identifies 21+3 in events and records
+func (t TwentyOnePlusThree) Name() string {
+	return SideBetTwentyOnePlusThree
+}
+
+// Label is what 21+3 is called at the table
+func (t TwentyOnePlusThree) Label() string {
+	return "21+3"
+}
+
+// Describe returns the poker hand a 21+3 bet made
+func (t TwentyOnePlusThree) Describe(outcome string) string {
+	return PokerHand(outcome).String()
+}
+
+// Window has 21+3 placed before the main bet
+func (t TwentyOnePlusThree) Window() SideBetWindow {
+	return SideBetWindowBetting
+}
+
+// JudgedOn has 21+3 judged as soon as the cards are dealt
+func (t TwentyOnePlusThree) JudgedOn() SideBetJudgement {
+	return JudgedOnDeal
+}
+
+// Limits returns the table's 21+3 limits, whatever the main bet
+func (t TwentyOnePlusThree) Limits(mainBet int64) (int64, int64) {
+	return t.MinBet, t.MaxBet
+}
+
+// Evaluate judges a 21+3 bet on the poker hand the three cards make
+func (t TwentyOnePlusThree) Evaluate(playerCards, dealerCards []*entities.Card, amount int64) (string, int64) {
+	if len(playerCards) < 2 || len(dealerCards) < 1 {
+		return string(PokerHandNone), 0
+	}
+
+	pokerHand := EvaluatePokerHand([]*entities.Card{playerCards[0], playerCards[1], dealerCards[0]})
+	if pays, wins := t.Pays[pokerHand]; wins {
+		return string(pokerHand), amount + amount*pays
+	}
+	return string(pokerHand), 0
+}
@@ -0,0 +1,51 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluatePokerHand(t *testing.T) {
+	card := func(rank entities.Rank, suit entities.Suit) *entities.Card {
+		return &entities.Card{Rank: rank, Suit: suit}
+	}
+
+	tests := []struct {
+		name  string
+		cards []*entities.Card
+		want  PokerHand
+	}{
+		{"suited trips", []*entities.Card{card(entities.Seven, entities.Hearts), card(entities.Seven, entities.Hearts), card(entities.Seven, entities.Hearts)}, PokerHandSuitedTrips},
+		{"straight flush", []*entities.Card{card(entities.Nine, entities.Clubs), card(entities.Jack, entities.Clubs), card(entities.Ten, entities.Clubs)}, PokerHandStraightFlush},
+		{"three of a kind", []*entities.Card{card(entities.King, entities.Hearts), card(entities.King, entities.Spades), card(entities.King, entities.Hearts)}, PokerHandThreeOfAKind},
+		{"straight", []*entities.Card{card(entities.Four, entities.Hearts), card(entities.Two, entities.Spades), card(entities.Three, entities.Diamonds)}, PokerHandStraight},
+		{"ace low straight", []*entities.Card{card(entities.Ace, entities.Hearts), card(entities.Two, entities.Spades), card(entities.Three, entities.Diamonds)}, PokerHandStraight},
+		{"ace high straight", []*entities.Card{card(entities.Queen, entities.Hearts), card(entities.Ace, entities.Spades), card(entities.King, entities.Diamonds)}, PokerHandStraight},
+		{"no wrap around", []*entities.Card{card(entities.King, entities.Hearts), card(entities.Ace, entities.Spades), card(entities.Two, entities.Diamonds)}, PokerHandNone},
+		{"flush", []*entities.Card{card(entities.Two, entities.Diamonds), card(entities.Nine, entities.Diamonds), card(entities.King, entities.Diamonds)}, PokerHandFlush},
+		{"ten and king aren't a pair", []*entities.Card{card(entities.Ten, entities.Hearts), card(entities.King, entities.Spades), card(entities.Ten, entities.Clubs)}, PokerHandNone},
+		{"nothing", []*entities.Card{card(entities.Two, entities.Hearts), card(entities.Eight, entities.Spades), card(entities.King, entities.Clubs)}, PokerHandNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EvaluatePokerHand(tt.cards))
+		})
+	}
+}
+
+func TestTwentyOnePlusThreeEvaluate(t *testing.T) {
+	bet := TwentyOnePlusThree{Pays: DefaultTwentyOnePlusThreePays()}
+	playerCards := []*entities.Card{{Rank: entities.Seven, Suit: entities.Hearts}, {Rank: entities.Eight, Suit: entities.Hearts}}
+
+	// The hole card never counts, only the up-card
+	outcome, payout := bet.Evaluate(playerCards, []*entities.Card{{Rank: entities.Nine, Suit: entities.Hearts}, {Rank: entities.Seven, Suit: entities.Hearts}}, 5)
+	assert.Equal(t, string(PokerHandStraightFlush), outcome)
+	assert.Equal(t, int64(205), payout)
+
+	outcome, payout = bet.Evaluate(playerCards, []*entities.Card{{Rank: entities.King, Suit: entities.Clubs}}, 5)
+	assert.Equal(t, string(PokerHandNone), outcome)
+	assert.Equal(t, int64(0), payout)
+}
@@ -0,0 +1,256 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+// PlayNoDecision is the deciding play of a hand nobody got to play: a
+// blackjack, or a hand the dealer's blackjack ended
+const PlayNoDecision = "no_decision"
+
+// deciding plays in the order they're reported
+var decidingPlays = []string{
+	string(blackjack.EventStand),
+	string(blackjack.EventHit),
+	string(blackjack.EventDoubleDown),
+	string(blackjack.EventSplit),
+	string(blackjack.EventSurrender),
+	PlayNoDecision,
+}
+
+// Report is how a simulation came out. Amounts are in dollars, and edges and
+// EVs are per dollar of initial bet, so a house edge of 0.005 is half a percent.
+type Report struct {
+	RuleSet  string `json:"rule_set"`
+	Rules    string `json:"rules"`
+	Strategy string `json:"strategy"`
+	Seats    int    `json:"seats"`
+	Rounds   int    `json:"rounds"`
+
+	Hands          int     `json:"hands"`            // Hands played, split hands included
+	InitialBets    int64   `json:"initial_bets"`     // Main bets placed before any doubling or splitting
+	TotalWagered   int64   `json:"total_wagered"`    // Main bets with the doubles and splits added
+	Net            int64   `json:"net"`              // What the players won on the main game, negative if the house won
+	HouseEdge      float64 `json:"house_edge"`       // The house's take per dollar of initial bet
+	Variance       float64 `json:"variance"`         // Variance of a seat's result each round, in initial bets
+	StdDev         float64 `json:"std_dev"`          // Standard deviation of a seat's result each round, in initial bets
+	PlayerBustRate float64 `json:"player_bust_rate"` // Share of hands that busted
+	DealerBustRate float64 `json:"dealer_bust_rate"` // Share of rounds the dealer busted in
+
+	Actions  map[string]*ActionStats  `json:"actions"`   // Seats' rounds by the play they turned on
+	SideBets map[string]*SideBetStats `json:"side_bets"` // Side bets by name, insurance included
+	// Everything the players won from the house through the wallet, side bets included
+	WalletNet int64 `json:"wallet_net"`
+
+	// Running totals
+	seatRounds  int
+	mean, m2    float64 // Welford's running mean and sum of squares of a seat's result
+	playerBusts int
+	dealerBusts int
+}
+
+// ActionStats is how the seats' rounds that turned on one play came out
+type ActionStats struct {
+	Rounds      int     `json:"rounds"`
+	InitialBets int64   `json:"initial_bets"`
+	Net         int64   `json:"net"`
+	EV          float64 `json:"ev"` // The player's return per dollar of initial bet
+}
+
+// SideBetStats is how a side bet came out
+type SideBetStats struct {
+	Bets      int            `json:"bets"`
+	Wagered   int64          `json:"wagered"`
+	Returned  int64          `json:"returned"`   // Paid back, stakes included
+	HouseEdge float64        `json:"house_edge"` // The house's take per dollar wagered
+	Outcomes  map[string]int `json:"outcomes"`   // Bets by how they were judged
+}
+
+func newReport(config Config) *Report {
+	return &Report{
+		RuleSet:  config.Rules.Name,
+		Rules:    config.Rules.Summary(),
+		Strategy: string(config.Strategy),
+		Seats:    config.Seats,
+		Actions:  make(map[string]*ActionStats),
+		SideBets: make(map[string]*SideBetStats),
+	}
+}
+
+// addRound adds a finished round to the totals
+func (r *Report) addRound(g *blackjack.Game) error {
+	results, err := g.GetResults()
+	if err != nil {
+		return err
+	}
+
+	r.Rounds++
+	if g.Dealer.Status == blackjack.StatusBust {
+		r.dealerBusts++
+	}
+
+	seatNets := make(map[string]int64)
+	splitSeats := make(map[string]bool)
+	for _, result := range results {
+		r.Hands++
+		r.TotalWagered += result.Bet
+		seat := g.HandSeat(result.HandID)
+		seatNets[seat] += result.Payout - result.Bet
+		if result.IsSplit {
+			splitSeats[seat] = true
+		}
+		if hand := g.Players[result.HandID]; hand != nil && hand.Status == blackjack.StatusBust {
+			r.playerBusts++
+		}
+	}
+
+	for _, seat := range g.PlayerOrder {
+		// Split hands are counted with the seat they came from
+		initialBet := g.Bets[seat]
+		if initialBet == 0 || !g.IsSeat(seat) {
+			continue
+		}
+		net := seatNets[seat]
+		r.InitialBets += initialBet
+		r.Net += net
+
+		// Welford's online variance of the seat's result in initial bets
+		r.seatRounds++
+		units := float64(net) / float64(initialBet)
+		delta := units - r.mean
+		r.mean += delta / float64(r.seatRounds)
+		r.m2 += delta * (units - r.mean)
+
+		play := decidingPlay(g, seat, splitSeats[seat])
+		stats := r.Actions[play]
+		if stats == nil {
+			stats = &ActionStats{}
+			r.Actions[play] = stats
+		}
+		stats.Rounds++
+		stats.InitialBets += initialBet
+		stats.Net += net
+	}
+
+	for _, handID := range g.PlayerOrder {
+		for _, placed := range g.SideBets[handID] {
+			stats := r.SideBets[placed.Type]
+			if stats == nil {
+				stats = &SideBetStats{Outcomes: make(map[string]int)}
+				r.SideBets[placed.Type] = stats
+			}
+			stats.Bets++
+			stats.Wagered += placed.Amount
+			stats.Returned += placed.Payout
+			stats.Outcomes[placed.Outcome]++
+		}
+	}
+	return nil
+}
+
+// decidingPlay names the play a seat's round turned on: a split if the pair
+// was split, otherwise the first double, surrender, hit or stand
+func decidingPlay(g *blackjack.Game, seat string, split bool) string {
+	if split {
+		return string(blackjack.EventSplit)
+	}
+	for _, action := range g.HandActions(seat) {
+		switch blackjack.EventType(action) {
+		case blackjack.EventDoubleDown, blackjack.EventSurrender, blackjack.EventHit, blackjack.EventStand:
+			return action
+		}
+	}
+	return PlayNoDecision
+}
+
+// finish works out the rates and edges from the totals
+func (r *Report) finish(w *wallet) {
+	r.WalletNet = w.net()
+	if r.InitialBets > 0 {
+		r.HouseEdge = -float64(r.Net) / float64(r.InitialBets)
+	}
+	if r.seatRounds > 1 {
+		r.Variance = r.m2 / float64(r.seatRounds-1)
+		r.StdDev = math.Sqrt(r.Variance)
+	}
+	if r.Hands > 0 {
+		r.PlayerBustRate = float64(r.playerBusts) / float64(r.Hands)
+	}
+	if r.Rounds > 0 {
+		r.DealerBustRate = float64(r.dealerBusts) / float64(r.Rounds)
+	}
+	for _, stats := range r.Actions {
+		if stats.InitialBets > 0 {
+			stats.EV = float64(stats.Net) / float64(stats.InitialBets)
+		}
+	}
+	for _, stats := range r.SideBets {
+		if stats.Wagered > 0 {
+			stats.HouseEdge = float64(stats.Wagered-stats.Returned) / float64(stats.Wagered)
+		}
+	}
+}
+
+// WriteText writes the report as a table for people to read
+func (r *Report) WriteText(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Rules:\t%s (%s)\n", r.RuleSet, r.Rules)
+	fmt.Fprintf(w, "Strategy:\t%s, %d seat(s)\n", r.Strategy, r.Seats)
+	fmt.Fprintf(w, "Rounds:\t%d (%d hands)\n", r.Rounds, r.Hands)
+	fmt.Fprintf(w, "Initial bets:\t$%d ($%d with doubles and splits)\n", r.InitialBets, r.TotalWagered)
+	fmt.Fprintf(w, "Player net:\t$%d\n", r.Net)
+	fmt.Fprintf(w, "House edge:\t%.3f%%\n", r.HouseEdge*100)
+	fmt.Fprintf(w, "Variance:\t%.4f (std dev %.4f)\n", r.Variance, r.StdDev)
+	fmt.Fprintf(w, "Player bust rate:\t%.2f%%\n", r.PlayerBustRate*100)
+	fmt.Fprintf(w, "Dealer bust rate:\t%.2f%%\n", r.DealerBustRate*100)
+	fmt.Fprintf(w, "Wallet net:\t$%d (side bets included)\n", r.WalletNet)
+
+	fmt.Fprintf(w, "\nDeciding play\tRounds\tShare\tEV\n")
+	for _, play := range decidingPlays {
+		stats, played := r.Actions[play]
+		if !played {
+			continue
+		}
+		share := 0.0
+		if r.seatRounds > 0 {
+			share = float64(stats.Rounds) / float64(r.seatRounds)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.2f%%\t%+.4f\n", play, stats.Rounds, share*100, stats.EV)
+	}
+
+	if len(r.SideBets) > 0 {
+		names := make([]string, 0, len(r.SideBets))
+		for name := range r.SideBets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintf(w, "\nSide bet\tBets\tWagered\tReturned\tHouse edge\n")
+		for _, name := range names {
+			stats := r.SideBets[name]
+			fmt.Fprintf(w, "%s\t%d\t$%d\t$%d\t%.3f%%\n", name, stats.Bets, stats.Wagered, stats.Returned, stats.HouseEdge*100)
+		}
+		for _, name := range names {
+			outcomes := make([]string, 0, len(r.SideBets[name].Outcomes))
+			for outcome := range r.SideBets[name].Outcomes {
+				outcomes = append(outcomes, outcome)
+			}
+			sort.Strings(outcomes)
+
+			fmt.Fprintf(w, "\n%s outcome\tBets\tShare\n", name)
+			for _, outcome := range outcomes {
+				count := r.SideBets[name].Outcomes[outcome]
+				fmt.Fprintf(w, "%s\t%d\t%.3f%%\n", outcome, count, float64(count)/float64(r.SideBets[name].Bets)*100)
+			}
+		}
+	}
+
+	return w.Flush()
+}
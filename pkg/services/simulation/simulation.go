@@ -0,0 +1,179 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/repositories/game"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid simulation config")
+	ErrRoundStuck    = errors.New("round stopped waiting on nobody")
+)
+
+// ChannelID is the table every simulated round is dealt at, so the rounds share a shoe
+const ChannelID = "simulation"
+
+// maxStepsPerRound stops a round that never finishes, which can only be a bug
+const maxStepsPerRound = 1000
+
+// Config is what to simulate
+type Config struct {
+	Rules         blackjack.RuleSet
+	Strategy      blackjack.BotPersonality   // How every seat bets and plays
+	Seats         int                        // Seats at the table, all playing the strategy
+	Rounds        int                        // Rounds to deal
+	SideBets      []string                   // Side bets every seat places each round, by name
+	SideBetAmount int64                      // Stake on each side bet, 0 for its minimum or the table's
+	Registry      *blackjack.SideBetRegistry // Side bets offered, nil for Tuco's
+	Shuffler      entities.Shuffler          // Shuffles every shoe, nil for a random one
+}
+
+// Validate checks the config can be simulated
+func (c Config) Validate() error {
+	if err := c.Rules.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if _, err := blackjack.ParseBotPersonality(string(c.Strategy)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if c.Seats < 1 || c.Seats > c.Rules.MaxPlayers {
+		return fmt.Errorf("%w: seats must be between 1 and %d", ErrInvalidConfig, c.Rules.MaxPlayers)
+	}
+	if c.Rounds < 1 {
+		return fmt.Errorf("%w: rounds must be at least 1", ErrInvalidConfig)
+	}
+	if c.SideBetAmount < 0 {
+		return fmt.Errorf("%w: side bet amount can't be negative", ErrInvalidConfig)
+	}
+
+	registry := c.registry()
+	for _, name := range c.SideBets {
+		sideBet, offered := registry.Lookup(name)
+		if !offered {
+			return fmt.Errorf("%w: %w: %s", ErrInvalidConfig, blackjack.ErrUnknownSideBet, name)
+		}
+		// Side bets decided on the hand's turn are left to the strategy
+		if sideBet.Window() != blackjack.SideBetWindowBetting {
+			return fmt.Errorf("%w: %s isn't placed with the bets, the strategy decides it", ErrInvalidConfig, name)
+		}
+	}
+	return nil
+}
+
+// registry returns the side bets the table offers
+func (c Config) registry() *blackjack.SideBetRegistry {
+	if c.Registry == nil {
+		return blackjack.DefaultSideBetRegistry()
+	}
+	return c.Registry
+}
+
+// shoeRepository keeps the shoe between rounds like the memory repository,
+// but drops the event log and game records so millions of rounds fit in memory
+type shoeRepository struct {
+	*game.MemoryRepository
+}
+
+func (shoeRepository) AppendEvent(ctx context.Context, event *game.EventRecord) error {
+	return nil
+}
+
+func (shoeRepository) SaveGameResult(ctx context.Context, result *entities.GameResult) error {
+	return nil
+}
+
+// Run deals the configured rounds and reports how they went
+func Run(ctx context.Context, config Config) (*Report, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	repo := shoeRepository{game.NewMemoryRepository()}
+	wallets := newWallet()
+	registry := config.registry()
+	shuffler := config.Shuffler
+	if shuffler == nil {
+		shuffler = entities.NewCryptoShuffler()
+	}
+
+	seats := make([]string, 0, config.Seats)
+	for n := 1; n <= config.Seats; n++ {
+		seats = append(seats, blackjack.NewBotID(config.Strategy, n))
+	}
+
+	report := newReport(config)
+	for round := 0; round < config.Rounds; round++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		g := blackjack.NewGame(ChannelID, repo, config.Rules, shuffler)
+		g.SetSideBetRegistry(registry)
+		for _, seat := range seats {
+			if err := g.AddPlayer(seat); err != nil {
+				return nil, err
+			}
+		}
+		g.PlayerOrder = append([]string(nil), seats...)
+		if err := g.Start(); err != nil {
+			return nil, err
+		}
+
+		if err := playRound(ctx, g, config, wallets); err != nil {
+			return nil, fmt.Errorf("round %d: %w", round+1, err)
+		}
+		if err := report.addRound(g); err != nil {
+			return nil, fmt.Errorf("round %d: %w", round+1, err)
+		}
+	}
+
+	report.finish(wallets)
+	return report, nil
+}
+
+// playRound plays a round from the bets to the payouts
+func playRound(ctx context.Context, g *blackjack.Game, config Config, wallets *wallet) error {
+	registry := config.registry()
+	for _, seat := range g.PlayerOrder {
+		for _, name := range config.SideBets {
+			sideBet, _ := registry.Lookup(name)
+			amount := config.SideBetAmount
+			if amount == 0 {
+				amount, _ = sideBet.Limits(0)
+			}
+			if amount == 0 {
+				amount = g.Rules.MinBet
+			}
+			if err := g.PlaceSideBet(ctx, name, seat, amount, wallets); err != nil {
+				return fmt.Errorf("placing %s: %w", name, err)
+			}
+		}
+	}
+
+	for steps := 0; !g.PayoutsProcessed; steps++ {
+		if steps >= maxStepsPerRound {
+			return fmt.Errorf("%w: still %s after %d steps", ErrRoundStuck, g.State, steps)
+		}
+
+		var err error
+		switch {
+		case g.State == entities.StateComplete:
+			err = g.FinishGame(ctx, wallets)
+		case g.State == entities.StateDealer:
+			_, err = g.CompleteGameIfDone(ctx, wallets)
+		case g.CurrentTurnHandID() != "":
+			_, _, err = g.PlayBotTurn(ctx, wallets)
+		default:
+			return fmt.Errorf("%w in %s", ErrRoundStuck, g.State)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
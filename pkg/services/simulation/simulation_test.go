@@ -0,0 +1,97 @@
+package simulation
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/fadedpez/tucoramirez/pkg/services/blackjack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func newTestConfig(t *testing.T) Config {
+	var seed entities.Seed
+	seed[0] = 17
+	return Config{
+		Rules:    blackjack.DefaultRuleSet(),
+		Strategy: blackjack.BotBasic,
+		Seats:    3,
+		Rounds:   300,
+		SideBets: []string{blackjack.SideBetPerfectPairs},
+		Shuffler: entities.NewSeededShuffler(seed),
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), newTestConfig(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, report.Rounds)
+	assert.GreaterOrEqual(t, report.Hands, 900)
+	assert.Equal(t, int64(900)*blackjack.DefaultRuleSet().MinBet, report.InitialBets)
+	assert.GreaterOrEqual(t, report.TotalWagered, report.InitialBets)
+	assert.Greater(t, report.Variance, 0.0)
+	assert.Greater(t, report.DealerBustRate, 0.0)
+	assert.Less(t, report.DealerBustRate, 1.0)
+
+	// Every seat's round is put down to exactly one play
+	var rounds int
+	var net int64
+	for _, stats := range report.Actions {
+		rounds += stats.Rounds
+		net += stats.Net
+	}
+	assert.Equal(t, 900, rounds)
+	assert.Equal(t, report.Net, net)
+
+	// Everything that went through the wallet is the main game plus the side bets
+	sideBetNet := int64(0)
+	for _, stats := range report.SideBets {
+		sideBetNet += stats.Returned - stats.Wagered
+	}
+	assert.Equal(t, 900, report.SideBets[blackjack.SideBetPerfectPairs].Bets)
+	assert.Equal(t, report.WalletNet, report.Net+sideBetNet)
+
+	var text strings.Builder
+	require.NoError(t, report.WriteText(&text))
+	assert.Contains(t, text.String(), "House edge:")
+	assert.Contains(t, text.String(), blackjack.SideBetPerfectPairs)
+}
+
+func TestRunIsRepeatable(t *testing.T) {
+	first, err := Run(context.Background(), newTestConfig(t))
+	require.NoError(t, err)
+	second, err := Run(context.Background(), newTestConfig(t))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestConfigValidate(t *testing.T) {
+	config := newTestConfig(t)
+	require.NoError(t, config.Validate())
+
+	config.Seats = config.Rules.MaxPlayers + 1
+	assert.ErrorIs(t, config.Validate(), ErrInvalidConfig)
+
+	config = newTestConfig(t)
+	config.Strategy = "cheater"
+	assert.ErrorIs(t, config.Validate(), ErrInvalidConfig)
+
+	// Insurance is left to the strategy
+	config = newTestConfig(t)
+	config.SideBets = []string{blackjack.SideBetInsurance}
+	assert.ErrorIs(t, config.Validate(), ErrInvalidConfig)
+
+	config.SideBets = []string{"lucky_ladies"}
+	assert.ErrorIs(t, config.Validate(), blackjack.ErrUnknownSideBet)
+}
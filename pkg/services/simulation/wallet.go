@@ -0,0 +1,79 @@
+package simulation
+
+import (
+	"context"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// bottomless is the balance every simulated player always has, so no round
+// is ever decided by running out of chips
+const bottomless int64 = 1 << 50
+
+// wallet is a fake wallet service with a bottomless bankroll. It keeps what
+// every player put on the table and got back, so the report can check the
+// payouts add up.
+type wallet struct {
+	staked   int64
+	returned int64
+}
+
+func newWallet() *wallet {
+	return &wallet{}
+}
+
+func (w *wallet) GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error) {
+	return &entities.Wallet{UserID: userID, Balance: bottomless}, false, nil
+}
+
+func (w *wallet) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	w.returned += amount
+	return nil
+}
+
+func (w *wallet) HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	w.staked += amount
+	return nil
+}
+
+func (w *wallet) CaptureHold(ctx context.Context, userID, roundID string, amount int64) error {
+	return nil
+}
+
+func (w *wallet) CaptureRoundHolds(ctx context.Context, roundID string) error {
+	return nil
+}
+
+func (w *wallet) ReleaseHold(ctx context.Context, userID, roundID string) (int64, error) {
+	return 0, nil
+}
+
+func (w *wallet) ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error {
+	w.staked -= amount
+	return nil
+}
+
+func (w *wallet) ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+
+func (w *wallet) SettleTable(ctx context.Context, tableID string) error {
+	return nil
+}
+
+func (w *wallet) CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error) {
+	return 0, nil
+}
+
+func (w *wallet) EnsureFundsWithLoan(ctx context.Context, userID string, requiredAmount int64, loanAmount int64) (*entities.Wallet, bool, error) {
+	return &entities.Wallet{UserID: userID, Balance: bottomless}, false, nil
+}
+
+func (w *wallet) GetStandardLoanIncrement() int64 {
+	return 0
+}
+
+// net returns what the players won from the house, negative if they lost
+func (w *wallet) net() int64 {
+	return w.returned - w.staked
+}
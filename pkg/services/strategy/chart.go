@@ -0,0 +1,197 @@
+package strategy
+
+import (
+	"strings"
+	"sync"
+)
+
+// Play is an entry in a basic strategy chart. Doubling and surrendering are
+// only allowed on some hands, so those entries say what to do otherwise.
+type Play string
+
+const (
+	PlayHit              Play = "H"
+	PlayStand            Play = "S"
+	PlayDoubleOrHit      Play = "Dh"
+	PlayDoubleOrStand    Play = "Ds"
+	PlaySplit            Play = "P"
+	PlaySurrenderOrHit   Play = "Rh"
+	PlaySurrenderOrStand Play = "Rs"
+	PlaySurrenderOrSplit Play = "Rp"
+	PlayNoSplit          Play = "-" // Don't split, play the pair as a total
+)
+
+// resolve turns a chart entry into an action the player is allowed to take
+func (p Play) resolve(allowed Allowed) Action {
+	switch p {
+	case PlayStand:
+		return ActionStand
+	case PlayDoubleOrHit:
+		if allowed.Double {
+			return ActionDouble
+		}
+	case PlayDoubleOrStand:
+		if allowed.Double {
+			return ActionDouble
+		}
+		return ActionStand
+	case PlaySplit:
+		return ActionSplit
+	case PlaySurrenderOrHit:
+		if allowed.Surrender {
+			return ActionSurrender
+		}
+	case PlaySurrenderOrStand:
+		if allowed.Surrender {
+			return ActionSurrender
+		}
+		return ActionStand
+	case PlaySurrenderOrSplit:
+		if allowed.Surrender {
+			return ActionSurrender
+		}
+		return ActionSplit
+	}
+	return ActionHit
+}
+
+// Row is a chart row, one play against each dealer up-card: 2 to 9, a
+// ten-value card and an Ace
+type Row []Play
+
+// row parses a chart row written as space separated plays
+func row(plays string) Row {
+	r := make(Row, 0, 10)
+	for _, play := range strings.Fields(plays) {
+		r = append(r, Play(play))
+	}
+	return r
+}
+
+// Chart holds the basic strategy for one set of table rules
+type Chart struct {
+	Rules Rules
+	Hard  map[int]Row // Hard totals from 5 to 20
+	Soft  map[int]Row // Soft totals from 12 (two Aces that can't be split) to 20
+	Pairs map[int]Row // Pairs by the value of one card, 11 for Aces
+}
+
+// charts caches the chart for each set of rules, charts never change once built
+var charts sync.Map
+
+// ChartFor returns the basic strategy chart for a table, building it the first
+// time it's asked for
+func ChartFor(rules Rules) *Chart {
+	if chart, cached := charts.Load(rules); cached {
+		return chart.(*Chart)
+	}
+	chart, _ := charts.LoadOrStore(rules, NewChart(rules))
+	return chart.(*Chart)
+}
+
+// NewChart builds the basic strategy chart for a table. It starts from the
+// chart for a shoe of four or more decks where the dealer stands on soft 17
+// and adjusts it for one or two decks, H17, no double after split and surrender.
+func NewChart(rules Rules) *Chart {
+	c := &Chart{
+		Rules: rules,
+		Hard:  make(map[int]Row),
+		Soft:  make(map[int]Row),
+		Pairs: make(map[int]Row),
+	}
+
+	for total := 5; total <= 8; total++ {
+		c.Hard[total] = row("H  H  H  H  H  H  H  H  H  H")
+	}
+	c.Hard[9] = row("H  Dh Dh Dh Dh H  H  H  H  H")
+	c.Hard[10] = row("Dh Dh Dh Dh Dh Dh Dh Dh H  H")
+	c.Hard[11] = row("Dh Dh Dh Dh Dh Dh Dh Dh Dh H")
+	c.Hard[12] = row("H  H  S  S  S  H  H  H  H  H")
+	for total := 13; total <= 16; total++ {
+		c.Hard[total] = row("S  S  S  S  S  H  H  H  H  H")
+	}
+	for total := 17; total <= 20; total++ {
+		c.Hard[total] = row("S  S  S  S  S  S  S  S  S  S")
+	}
+
+	c.Soft[12] = row("H  H  H  H  H  H  H  H  H  H")
+	c.Soft[13] = row("H  H  H  Dh Dh H  H  H  H  H")
+	c.Soft[14] = row("H  H  H  Dh Dh H  H  H  H  H")
+	c.Soft[15] = row("H  H  Dh Dh Dh H  H  H  H  H")
+	c.Soft[16] = row("H  H  Dh Dh Dh H  H  H  H  H")
+	c.Soft[17] = row("H  Dh Dh Dh Dh H  H  H  H  H")
+	c.Soft[18] = row("S  Ds Ds Ds Ds S  S  H  H  H")
+	c.Soft[19] = row("S  S  S  S  S  S  S  S  S  S")
+	c.Soft[20] = row("S  S  S  S  S  S  S  S  S  S")
+
+	c.Pairs[2] = row("P  P  P  P  P  P  -  -  -  -")
+	c.Pairs[3] = row("P  P  P  P  P  P  -  -  -  -")
+	c.Pairs[4] = row("-  -  -  P  P  -  -  -  -  -")
+	c.Pairs[5] = row("-  -  -  -  -  -  -  -  -  -")
+	c.Pairs[6] = row("P  P  P  P  P  -  -  -  -  -")
+	c.Pairs[7] = row("P  P  P  P  P  P  -  -  -  -")
+	c.Pairs[8] = row("P  P  P  P  P  P  P  P  P  P")
+	c.Pairs[9] = row("P  P  P  P  P  -  P  P  -  -")
+	c.Pairs[10] = row("-  -  -  -  -  -  -  -  -  -")
+	c.Pairs[11] = row("P  P  P  P  P  P  P  P  P  P")
+
+	const (
+		ten = 8
+		ace = 9
+	)
+
+	// One and two deck games double more
+	if rules.Decks <= 2 {
+		c.Hard[9][0] = PlayDoubleOrHit
+		c.Hard[11][ace] = PlayDoubleOrHit
+	}
+	if rules.Decks == 1 {
+		c.Hard[8][3], c.Hard[8][4] = PlayDoubleOrHit, PlayDoubleOrHit
+		c.Soft[13][2], c.Soft[14][2] = PlayDoubleOrHit, PlayDoubleOrHit
+		c.Soft[17][0] = PlayDoubleOrHit
+		c.Soft[19][4] = PlayDoubleOrStand
+		c.Pairs[7][6] = PlaySplit
+	}
+
+	// The dealer drawing to soft 17 makes the player a little more aggressive
+	if rules.DealerHitsSoft17 {
+		c.Hard[11][ace] = PlayDoubleOrHit
+		c.Soft[18][0] = PlayDoubleOrStand
+		c.Soft[19][4] = PlayDoubleOrStand
+	}
+
+	// Without doubling after a split, the small pairs are worth splitting less often
+	if !rules.DoubleAfterSplit {
+		c.Pairs[2] = row("-  -  P  P  P  P  -  -  -  -")
+		c.Pairs[3] = row("-  -  P  P  P  P  -  -  -  -")
+		c.Pairs[4] = row("-  -  -  -  -  -  -  -  -  -")
+		c.Pairs[6] = row("-  P  P  P  P  -  -  -  -  -")
+	}
+
+	if rules.Surrender || rules.EarlySurrender {
+		c.Hard[15][ten] = PlaySurrenderOrHit
+		c.Hard[16][ten] = PlaySurrenderOrHit
+		c.Hard[16][ace] = PlaySurrenderOrHit
+		if rules.Decks > 2 {
+			c.Hard[16][7] = PlaySurrenderOrHit
+		}
+		if rules.DealerHitsSoft17 {
+			c.Hard[15][ace] = PlaySurrenderOrHit
+			c.Hard[17][ace] = PlaySurrenderOrStand
+			c.Pairs[8][ace] = PlaySurrenderOrSplit
+		}
+	}
+
+	// Giving up before the peek is worth it against an Ace, and a ten, far more often
+	if rules.EarlySurrender {
+		for _, total := range []int{5, 6, 7, 12, 13, 14, 15, 16} {
+			c.Hard[total][ace] = PlaySurrenderOrHit
+		}
+		c.Hard[17][ace] = PlaySurrenderOrStand
+		c.Hard[14][ten] = PlaySurrenderOrHit
+		c.Pairs[8][ace] = PlaySurrenderOrSplit
+		c.Pairs[8][ten] = PlaySurrenderOrSplit
+	}
+
+	return c
+}
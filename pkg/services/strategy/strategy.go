@@ -0,0 +1,129 @@
+package strategy
+
+import (
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+)
+
+// Action is a play basic strategy recommends. The values match the blackjack
+// events for the same decisions.
+type Action string
+
+const (
+	ActionHit       Action = "hit"
+	ActionStand     Action = "stand"
+	ActionDouble    Action = "double_down"
+	ActionSplit     Action = "split"
+	ActionSurrender Action = "surrender"
+)
+
+// String returns the action as Tuco calls it at the table
+func (a Action) String() string {
+	switch a {
+	case ActionHit:
+		return "Hit"
+	case ActionStand:
+		return "Stand"
+	case ActionDouble:
+		return "Double Down"
+	case ActionSplit:
+		return "Split"
+	case ActionSurrender:
+		return "Surrender"
+	}
+	return string(a)
+}
+
+// Rules are the table rules basic strategy depends on
+type Rules struct {
+	Decks            int  // Number of decks in the shoe
+	DealerHitsSoft17 bool // H17 when true, S17 when false
+	DoubleAfterSplit bool // Whether split hands may double down
+	Surrender        bool // Whether late surrender is offered
+	EarlySurrender   bool // Whether surrender is offered before the dealer peeks
+}
+
+// Allowed is what a player may do with a hand right now. Hitting and standing
+// are always allowed.
+type Allowed struct {
+	Double    bool
+	Split     bool
+	Surrender bool
+}
+
+// Total returns a hand's best total and whether it's soft, i.e. counts an Ace as 11
+func Total(cards []*entities.Card) (int, bool) {
+	total, aces := 0, 0
+	for _, card := range cards {
+		value := cardValue(card)
+		if value == 11 {
+			aces++
+			value = 1
+		}
+		total += value
+	}
+	if aces > 0 && total+10 <= 21 {
+		return total + 10, true
+	}
+	return total, false
+}
+
+// cardValue returns a card's blackjack value, with an Ace worth 11
+func cardValue(card *entities.Card) int {
+	switch card.Rank {
+	case entities.Ace:
+		return 11
+	case entities.Two:
+		return 2
+	case entities.Three:
+		return 3
+	case entities.Four:
+		return 4
+	case entities.Five:
+		return 5
+	case entities.Six:
+		return 6
+	case entities.Seven:
+		return 7
+	case entities.Eight:
+		return 8
+	case entities.Nine:
+		return 9
+	}
+	return 10
+}
+
+// column returns the chart column for a dealer up-card: 2-9, then ten-value cards, then the Ace
+func column(upCard *entities.Card) int {
+	value := cardValue(upCard)
+	if value == 11 {
+		return 9
+	}
+	return value - 2
+}
+
+// Advise returns the best play for a hand's cards against the dealer's
+// up-card, out of what the player is allowed to do
+func (c *Chart) Advise(cards []*entities.Card, upCard *entities.Card, allowed Allowed) Action {
+	if len(cards) == 0 || upCard == nil {
+		return ActionStand
+	}
+	col := column(upCard)
+
+	// Pairs are looked up on their own chart while they can still be split
+	if allowed.Split && len(cards) == 2 && cardValue(cards[0]) == cardValue(cards[1]) {
+		if play := c.Pairs[cardValue(cards[0])][col]; play != PlayNoSplit {
+			return play.resolve(allowed)
+		}
+	}
+
+	total, soft := Total(cards)
+	switch {
+	case total >= 21:
+		return ActionStand
+	case soft:
+		return c.Soft[total][col].resolve(allowed)
+	case total < 5:
+		return ActionHit
+	}
+	return c.Hard[total][col].resolve(allowed)
+}
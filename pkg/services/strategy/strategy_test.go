@@ -0,0 +1,112 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func card(rank entities.Rank) *entities.Card {
+	return &entities.Card{Rank: rank, Suit: entities.Spades}
+}
+
+func cards(ranks ...entities.Rank) []*entities.Card {
+	hand := make([]*entities.Card, 0, len(ranks))
+	for _, rank := range ranks {
+		hand = append(hand, card(rank))
+	}
+	return hand
+}
+
+// shoeRules are the rules most shoe games are dealt under: six decks, S17, DAS and late surrender
+var shoeRules = Rules{Decks: 6, DoubleAfterSplit: true, Surrender: true}
+
+func TestTotal(t *testing.T) {
+	total, soft := Total(cards(entities.Ace, entities.Six))
+	assert.Equal(t, 17, total)
+	assert.True(t, soft)
+
+	total, soft = Total(cards(entities.Ace, entities.Six, entities.King))
+	assert.Equal(t, 17, total)
+	assert.False(t, soft)
+
+	total, soft = Total(cards(entities.Ace, entities.Ace))
+	assert.Equal(t, 12, total)
+	assert.True(t, soft)
+}
+
+func TestAdvise(t *testing.T) {
+	all := Allowed{Double: true, Split: true, Surrender: true}
+
+	tests := []struct {
+		name    string
+		rules   Rules
+		hand    []entities.Rank
+		upCard  entities.Rank
+		allowed Allowed
+		want    Action
+	}{
+		{"hard 16 stands against a 6", shoeRules, []entities.Rank{entities.King, entities.Six}, entities.Six, all, ActionStand},
+		{"hard 16 surrenders against a ten", shoeRules, []entities.Rank{entities.King, entities.Six}, entities.Queen, all, ActionSurrender},
+		{"hard 16 hits against a ten without surrender", shoeRules, []entities.Rank{entities.King, entities.Six}, entities.Queen, Allowed{}, ActionHit},
+		{"hard 12 hits against a 3", shoeRules, []entities.Rank{entities.Ten, entities.Two}, entities.Three, all, ActionHit},
+		{"hard 11 doubles against a 10", shoeRules, []entities.Rank{entities.Six, entities.Five}, entities.Ten, all, ActionDouble},
+		{"hard 11 hits against an Ace on a shoe", shoeRules, []entities.Rank{entities.Six, entities.Five}, entities.Ace, all, ActionHit},
+		{"hard 11 doubles against an Ace when the dealer hits soft 17",
+			Rules{Decks: 6, DealerHitsSoft17: true}, []entities.Rank{entities.Six, entities.Five}, entities.Ace, all, ActionDouble},
+		{"hard 11 doubles against an Ace in a double deck game",
+			Rules{Decks: 2}, []entities.Rank{entities.Six, entities.Five}, entities.Ace, all, ActionDouble},
+		{"hard 10 hits once it can't double", shoeRules, []entities.Rank{entities.Four, entities.Three, entities.Three}, entities.Five, Allowed{}, ActionHit},
+		{"soft 18 doubles against a 4", shoeRules, []entities.Rank{entities.Ace, entities.Seven}, entities.Four, all, ActionDouble},
+		{"soft 18 stands against a 4 once it can't double", shoeRules, []entities.Rank{entities.Ace, entities.Four, entities.Three}, entities.Four, Allowed{}, ActionStand},
+		{"soft 18 hits against a 9", shoeRules, []entities.Rank{entities.Ace, entities.Seven}, entities.Nine, all, ActionHit},
+		{"aces split", shoeRules, []entities.Rank{entities.Ace, entities.Ace}, entities.Ace, all, ActionSplit},
+		{"aces hit once they can't split", shoeRules, []entities.Rank{entities.Ace, entities.Ace}, entities.Six, Allowed{Double: true}, ActionHit},
+		{"eights split against a ten", shoeRules, []entities.Rank{entities.Eight, entities.Eight}, entities.King, all, ActionSplit},
+		{"eights surrender against an Ace when the dealer hits soft 17",
+			Rules{Decks: 6, DealerHitsSoft17: true, Surrender: true}, []entities.Rank{entities.Eight, entities.Eight}, entities.Ace, all, ActionSurrender},
+		{"fives double instead of splitting", shoeRules, []entities.Rank{entities.Five, entities.Five}, entities.Six, all, ActionDouble},
+		{"tens stand", shoeRules, []entities.Rank{entities.King, entities.Queen}, entities.Six, all, ActionStand},
+		{"nines stand against a 7", shoeRules, []entities.Rank{entities.Nine, entities.Nine}, entities.Seven, all, ActionStand},
+		{"twos split against a 2 with double after split", shoeRules, []entities.Rank{entities.Two, entities.Two}, entities.Two, all, ActionSplit},
+		{"twos hit against a 2 without double after split",
+			Rules{Decks: 6, Surrender: true}, []entities.Rank{entities.Two, entities.Two}, entities.Two, all, ActionHit},
+		{"hard 14 surrenders against an Ace early", Rules{Decks: 6, EarlySurrender: true},
+			[]entities.Rank{entities.Nine, entities.Five}, entities.Ace, all, ActionSurrender},
+		{"hard 14 hits against an Ace without early surrender", shoeRules, []entities.Rank{entities.Nine, entities.Five}, entities.Ace, all, ActionHit},
+		{"21 stands", shoeRules, []entities.Rank{entities.Seven, entities.Seven, entities.Seven}, entities.Ten, Allowed{}, ActionStand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewChart(tt.rules).Advise(cards(tt.hand...), card(tt.upCard), tt.allowed))
+		})
+	}
+}
+
+func TestNewChartCoversEveryHand(t *testing.T) {
+	for _, rules := range []Rules{
+		shoeRules,
+		{Decks: 1, DealerHitsSoft17: true},
+		{Decks: 2, DoubleAfterSplit: true, EarlySurrender: true},
+	} {
+		chart := NewChart(rules)
+		for total := 5; total <= 20; total++ {
+			assert.Len(t, chart.Hard[total], 10, "hard %d", total)
+		}
+		for total := 12; total <= 20; total++ {
+			assert.Len(t, chart.Soft[total], 10, "soft %d", total)
+		}
+		for value := 2; value <= 11; value++ {
+			assert.Len(t, chart.Pairs[value], 10, "pair of %d", value)
+		}
+	}
+}
+
+func TestChartForCachesCharts(t *testing.T) {
+	chart := ChartFor(shoeRules)
+	assert.Same(t, chart, ChartFor(shoeRules))
+	assert.Equal(t, NewChart(shoeRules), chart)
+	assert.NotSame(t, chart, ChartFor(Rules{Decks: 1, DealerHitsSoft17: true}))
+}
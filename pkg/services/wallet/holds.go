@@ -0,0 +1,221 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCaptureExceedsHold = errors.New("capture is more than the hold")
+	ErrReleaseExceedsHold = errors.New("release is more than the hold")
+)
+
+// tableAccount returns the ledger account for a table's stakes: its escrow,
+// or the house bank away from a table
+func tableAccount(tableID string) entities.LedgerAccount {
+	if tableID == "" {
+		return entities.LedgerAccountHouseBank
+	}
+	return entities.TableEscrowAccount(tableID)
+}
+
+// HoldFunds takes chips out of a player's wallet and holds them for a round
+// at a table, whose escrow they're captured into. They stay the player's
+// until the round captures them, and come back if the round is abandoned. Holding more for the same round adds to the hold. The
+// type says what the stake is for and the reference ties it to its hand,
+// the round itself if it's empty.
+func (s *Service) HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	if amount <= 0 {
+		return ErrNegativeAmount
+	}
+	if !transactionType.IsGame() {
+		return fmt.Errorf("%w: %s", ErrNotAGameType, transactionType)
+	}
+	if referenceID == "" {
+		referenceID = roundID
+	}
+
+	err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		wallet, err := uow.AdjustBalance(ctx, userID, -amount, 0)
+		if err != nil {
+			return err
+		}
+
+		hold, err := uow.GetHold(ctx, userID, roundID)
+		if errors.Is(err, walletRepo.ErrHoldNotFound) {
+			hold = &entities.Hold{UserID: userID, RoundID: roundID, TableID: tableID, CreatedAt: time.Now()}
+		} else if err != nil {
+			return err
+		}
+		hold.Amount += amount
+		if err := uow.SaveHold(ctx, hold); err != nil {
+			return err
+		}
+
+		log.Printf("[WALLET] Holding $%d from user %s for round %s, $%d held", amount, userID, roundID, hold.Amount)
+		return uow.AddTransaction(ctx, &entities.Transaction{
+			ID:           uuid.New().String(),
+			UserID:       userID,
+			Amount:       -amount,
+			Type:         transactionType,
+			ReferenceID:  referenceID,
+			Description:  description,
+			Timestamp:    time.Now(),
+			BalanceAfter: wallet.Balance,
+			Entries:      entities.Transfer(entities.UserAccount(userID), entities.HoldAccount(roundID), amount),
+		})
+	})
+	if errors.Is(err, walletRepo.ErrInsufficientFunds) {
+		return ErrInsufficientFunds
+	}
+	return err
+}
+
+// CaptureHold hands part of a player's hold for a round over to the table,
+// for a stake settled before the round is paid
+func (s *Service) CaptureHold(ctx context.Context, userID, roundID string, amount int64) error {
+	if amount <= 0 {
+		return ErrNegativeAmount
+	}
+
+	return s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		hold, err := uow.GetHold(ctx, userID, roundID)
+		if err != nil {
+			return err
+		}
+		if amount > hold.Amount {
+			return fmt.Errorf("%w: $%d of $%d held", ErrCaptureExceedsHold, amount, hold.Amount)
+		}
+		return captureHold(ctx, uow, hold, amount)
+	})
+}
+
+// CaptureRoundHolds hands everything still held for a round over to the
+// table, once the round is over and about to be paid
+func (s *Service) CaptureRoundHolds(ctx context.Context, roundID string) error {
+	return s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		holds, err := uow.GetRoundHolds(ctx, roundID)
+		if err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			if err := captureHold(ctx, uow, hold, hold.Amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// captureHold moves amount of a hold to the table it was made at
+func captureHold(ctx context.Context, uow walletRepo.UnitOfWork, hold *entities.Hold, amount int64) error {
+	hold.Amount -= amount
+	if err := uow.SaveHold(ctx, hold); err != nil {
+		return err
+	}
+
+	log.Printf("[WALLET] Capturing $%d held from user %s for round %s", amount, hold.UserID, hold.RoundID)
+	return uow.PostEntries(ctx, uuid.New().String(), "Hold captured",
+		entities.Transfer(entities.HoldAccount(hold.RoundID), tableAccount(hold.TableID), amount))
+}
+
+// ReleaseHold gives a player back everything still held for them in a round
+// and returns the amount, 0 if nothing was held
+func (s *Service) ReleaseHold(ctx context.Context, userID, roundID string) (int64, error) {
+	var released int64
+	err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		hold, err := uow.GetHold(ctx, userID, roundID)
+		if errors.Is(err, walletRepo.ErrHoldNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		released = hold.Amount
+		return releaseHold(ctx, uow, hold, hold.Amount)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return released, nil
+}
+
+// ReleaseFromHold gives a player back part of their hold for a round, for a
+// stake taken off the table before the round is played
+func (s *Service) ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error {
+	if amount <= 0 {
+		return ErrNegativeAmount
+	}
+
+	return s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		hold, err := uow.GetHold(ctx, userID, roundID)
+		if err != nil {
+			return err
+		}
+		if amount > hold.Amount {
+			return fmt.Errorf("%w: $%d of $%d held", ErrReleaseExceedsHold, amount, hold.Amount)
+		}
+		return releaseHold(ctx, uow, hold, amount)
+	})
+}
+
+// ReleaseRoundHolds gives every player back what's still held for them in
+// an abandoned round, and returns the amount given back to each
+func (s *Service) ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error) {
+	released := make(map[string]int64)
+	err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		holds, err := uow.GetRoundHolds(ctx, roundID)
+		if err != nil {
+			return err
+		}
+		for _, hold := range holds {
+			released[hold.UserID] = hold.Amount
+			if err := releaseHold(ctx, uow, hold, hold.Amount); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// releaseHold puts amount of a hold back in the player's wallet, all of it
+// closes the hold
+func releaseHold(ctx context.Context, uow walletRepo.UnitOfWork, hold *entities.Hold, amount int64) error {
+	wallet, err := uow.AdjustBalance(ctx, hold.UserID, amount, 0)
+	if err != nil {
+		return err
+	}
+	hold.Amount -= amount
+	if err := uow.SaveHold(ctx, hold); err != nil {
+		return err
+	}
+
+	log.Printf("[WALLET] Releasing $%d held from user %s for round %s", amount, hold.UserID, hold.RoundID)
+	return uow.AddTransaction(ctx, &entities.Transaction{
+		ID:           uuid.New().String(),
+		UserID:       hold.UserID,
+		Amount:       amount,
+		Type:         entities.TransactionTypePushRefund,
+		ReferenceID:  hold.RoundID,
+		Description:  "Held chips given back",
+		Timestamp:    time.Now(),
+		BalanceAfter: wallet.Balance,
+		Entries:      entities.Transfer(entities.HoldAccount(hold.RoundID), entities.UserAccount(hold.UserID), amount),
+	})
+}
+
+// GetOpenHolds returns every hold still waiting on its round
+func (s *Service) GetOpenHolds(ctx context.Context) ([]*entities.Hold, error) {
+	return s.repo.GetOpenHolds(ctx)
+}
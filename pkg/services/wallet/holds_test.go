@@ -0,0 +1,117 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldsAreCapturedOrReleased(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+
+			// A bet and a side bet held for the first round, a bet for the second
+			require.NoError(t, service.HoldFunds(ctx, "player1", "table1", "round1", 30, entities.TransactionTypeBet, "", "Blackjack bet"))
+			require.NoError(t, service.HoldFunds(ctx, "player1", "table1", "round1", 10, entities.TransactionTypeSideBet, "round1/hand1", "Perfect Pairs side bet"))
+			require.NoError(t, service.HoldFunds(ctx, "player1", "table1", "round2", 20, entities.TransactionTypeBet, "", "Blackjack bet"))
+			assert.ErrorIs(t, service.HoldFunds(ctx, "player1", "table1", "round2", 50, entities.TransactionTypeBet, "", "Blackjack bet"), ErrInsufficientFunds)
+
+			balance, err := service.GetBalance(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(40), balance)
+			holds, err := service.GetOpenHolds(ctx)
+			require.NoError(t, err)
+			require.Len(t, holds, 2)
+			assert.Equal(t, entities.Hold{UserID: "player1", RoundID: "round1", TableID: "table1", Amount: 40, CreatedAt: holds[0].CreatedAt}, *holds[0])
+
+			// Held chips are still the player's, the books balance
+			_, err = service.Reconcile(ctx)
+			require.NoError(t, err)
+
+			// The side bet settles on the deal, the rest of the round when it's paid
+			require.NoError(t, service.CaptureHold(ctx, "player1", "round1", 10))
+			assert.ErrorIs(t, service.CaptureHold(ctx, "player1", "round1", 40), ErrCaptureExceedsHold)
+			require.NoError(t, service.CaptureRoundHolds(ctx, "round1"))
+
+			// The second round is abandoned
+			released, err := service.ReleaseRoundHolds(ctx, "round2")
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int64{"player1": 20}, released)
+			released, err = service.ReleaseRoundHolds(ctx, "round2")
+			require.NoError(t, err)
+			assert.Empty(t, released)
+
+			balance, err = service.GetBalance(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(60), balance)
+			holds, err = service.GetOpenHolds(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, holds)
+
+			totals, err := service.repo.GetLedgerTotals(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(40), totals.Balances[entities.TableEscrowAccount("table1")])
+			_, err = service.Reconcile(ctx)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestReleaseHold(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			require.NoError(t, service.HoldFunds(ctx, "player1", "", "round1", 25, entities.TransactionTypeBet, "", "Blackjack bet"))
+
+			released, err := service.ReleaseHold(ctx, "player1", "round1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(25), released)
+
+			// Nothing's left to give back
+			released, err = service.ReleaseHold(ctx, "player1", "round1")
+			require.NoError(t, err)
+			assert.Zero(t, released)
+
+			transactions, err := service.repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypePushRefund, 10)
+			require.NoError(t, err)
+			require.Len(t, transactions, 1)
+			assert.Equal(t, "round1", transactions[0].ReferenceID)
+			assert.Equal(t, StartingBalance, transactions[0].BalanceAfter)
+		})
+	}
+}
+
+func TestReleaseFromHold(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			require.NoError(t, service.HoldFunds(ctx, "player1", "table1", "round1", 25, entities.TransactionTypeBet, "", "Blackjack bet"))
+			require.NoError(t, service.HoldFunds(ctx, "player1", "table1", "round1", 5, entities.TransactionTypeSideBet, "", "21+3 side bet"))
+
+			require.NoError(t, service.ReleaseFromHold(ctx, "player1", "round1", 5))
+			assert.ErrorIs(t, service.ReleaseFromHold(ctx, "player1", "round1", 30), ErrReleaseExceedsHold)
+
+			balance, err := service.GetBalance(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, StartingBalance-25, balance)
+
+			// The bet is still held
+			released, err := service.ReleaseHold(ctx, "player1", "round1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(25), released)
+
+			_, err = service.Reconcile(ctx)
+			require.NoError(t, err)
+		})
+	}
+}
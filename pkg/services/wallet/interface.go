@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"context"
+	"time"
 
 	"github.com/fadedpez/tucoramirez/pkg/entities"
 )
@@ -9,8 +10,8 @@ import (
 //go:generate mockgen -source=$GOFILE -destination=mock/mock.go -package=mock_wallet_service
 type WalletService interface {
 	GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error)
-	AddFunds(ctx context.Context, userID string, amount int64, description string) error
-	RemoveFunds(ctx context.Context, userID string, amount int64, description string) error
+	AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error
+	RemoveFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error
 	EnsureFundsWithLoan(ctx context.Context, userID string, requiredAmount int64, loanAmount int64) (*entities.Wallet, bool, error)
 	ValidateLoan(ctx context.Context, userID string, amount int64) error
 	GiveLoan(ctx context.Context, userID string, amount int64) (*entities.Wallet, bool, error)
@@ -19,4 +20,17 @@ type WalletService interface {
 	GetStandardLoanIncrement() int64
 	CalculateRepaymentAmount(ctx context.Context, userID string) (int64, error)
 	CanRepayLoan(ctx context.Context, userID string) (bool, error)
+	SettleTable(ctx context.Context, tableID string) error
+	Reconcile(ctx context.Context) (*Reconciliation, error)
+	HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error
+	CaptureHold(ctx context.Context, userID, roundID string, amount int64) error
+	CaptureRoundHolds(ctx context.Context, roundID string) error
+	ReleaseHold(ctx context.Context, userID, roundID string) (int64, error)
+	ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error
+	ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error)
+	GetOpenHolds(ctx context.Context) ([]*entities.Hold, error)
+	GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error)
+	AccrueInterest(ctx context.Context, now time.Time) ([]*entities.Loan, error)
+	DueReminders(ctx context.Context, now time.Time) ([]LoanReminder, error)
+	CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error)
 }
\ No newline at end of file
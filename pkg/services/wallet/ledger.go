@@ -0,0 +1,121 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/google/uuid"
+)
+
+var ErrLedgerOutOfBalance = errors.New("ledger doesn't reconcile")
+
+// SettleTable moves what's left in a table's escrow once its round is paid to
+// the house bank. The house's winnings leave a positive balance, a round the
+// house lost leaves a negative one that the bank covers.
+func (s *Service) SettleTable(ctx context.Context, tableID string) error {
+	escrow := entities.TableEscrowAccount(tableID)
+	return s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		balance, err := uow.GetAccountBalance(ctx, escrow)
+		if err != nil {
+			return err
+		}
+		if balance == 0 {
+			return nil
+		}
+
+		log.Printf("[WALLET] Settling table %s: $%d to the house bank", tableID, balance)
+		return uow.PostEntries(ctx, uuid.New().String(), "Table settled",
+			entities.Transfer(escrow, entities.LedgerAccountHouseBank, balance))
+	})
+}
+
+// Reconciliation is the result of checking the ledger against the wallets
+type Reconciliation struct {
+	Issued      int64    // Chips the mint has issued
+	Burned      int64    // Chips burned back to the mint
+	Circulating int64    // Chips in every account but the mint
+	Wallets     int64    // Chips in the wallets
+	Problems    []string // Everything that didn't add up, empty if the ledger reconciles
+}
+
+// Reconcile checks that the chips in circulation are exactly the chips issued
+// minus the chips burned, that every wallet holds what its ledger account
+// says, that every round holds exactly its open holds, and that the loan desk
+// is owed exactly the loans outstanding. It
+// returns ErrLedgerOutOfBalance, with the reconciliation, if anything is off.
+func (s *Service) Reconcile(ctx context.Context) (*Reconciliation, error) {
+	totals, err := s.repo.GetLedgerTotals(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ledger totals: %w", err)
+	}
+	wallets, err := s.repo.GetAllWallets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting wallets: %w", err)
+	}
+	holds, err := s.repo.GetOpenHolds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting holds: %w", err)
+	}
+
+	reconciliation := &Reconciliation{Issued: totals.Issued, Burned: totals.Burned}
+	for account, balance := range totals.Balances {
+		if account != entities.LedgerAccountMint {
+			reconciliation.Circulating += balance
+		}
+	}
+	if reconciliation.Circulating != totals.Issued-totals.Burned {
+		reconciliation.Problems = append(reconciliation.Problems, fmt.Sprintf(
+			"$%d in circulation, but $%d issued and $%d burned", reconciliation.Circulating, totals.Issued, totals.Burned))
+	}
+
+	var loans int64
+	walletUsers := make(map[string]bool)
+	for _, wallet := range wallets {
+		walletUsers[wallet.UserID] = true
+		reconciliation.Wallets += wallet.Balance
+		loans += wallet.LoanAmount
+		if ledgerBalance := totals.Balances[entities.UserAccount(wallet.UserID)]; ledgerBalance != wallet.Balance {
+			reconciliation.Problems = append(reconciliation.Problems, fmt.Sprintf(
+				"wallet %s holds $%d, but its ledger account $%d", wallet.UserID, wallet.Balance, ledgerBalance))
+		}
+	}
+	for account, balance := range totals.Balances {
+		if userID, ok := account.UserID(); ok && !walletUsers[userID] && balance != 0 {
+			reconciliation.Problems = append(reconciliation.Problems, fmt.Sprintf(
+				"ledger account %s holds $%d, but there's no wallet", account, balance))
+		}
+	}
+
+	held := make(map[string]int64)
+	for _, hold := range holds {
+		held[hold.RoundID] += hold.Amount
+	}
+	for account, balance := range totals.Balances {
+		if roundID, ok := account.HoldRoundID(); ok && balance != held[roundID] {
+			reconciliation.Problems = append(reconciliation.Problems, fmt.Sprintf(
+				"round %s's hold account has $%d, but $%d is held for it", roundID, balance, held[roundID]))
+		}
+	}
+	for roundID, amount := range held {
+		if _, posted := totals.Balances[entities.HoldAccount(roundID)]; !posted {
+			reconciliation.Problems = append(reconciliation.Problems, fmt.Sprintf(
+				"round %s's hold account has $0, but $%d is held for it", roundID, amount))
+		}
+	}
+
+	if owed := -totals.Balances[entities.LedgerAccountLoanDesk]; owed != loans {
+		reconciliation.Problems = append(reconciliation.Problems, fmt.Sprintf(
+			"$%d in loans outstanding, but the loan desk is owed $%d", loans, owed))
+	}
+
+	if len(reconciliation.Problems) > 0 {
+		sort.Strings(reconciliation.Problems)
+		return reconciliation, fmt.Errorf("%w: %d problems", ErrLedgerOutOfBalance, len(reconciliation.Problems))
+	}
+	return reconciliation, nil
+}
@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerReconcilesAfterARound(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// player1 borrows to bet, player2 plays with the starting chips
+			_, _, err := service.EnsureFundsWithLoan(ctx, "player1", 150, service.GetStandardLoanIncrement())
+			require.NoError(t, err)
+			require.NoError(t, service.RemoveFunds(ctx, "player1", "table1", 150, entities.TransactionTypeBet, "round1", "Blackjack bet"))
+			_, _, err = service.GetOrCreateWallet(ctx, "player2")
+			require.NoError(t, err)
+			require.NoError(t, service.RemoveFunds(ctx, "player2", "table1", 50, entities.TransactionTypeBet, "round1", "Blackjack bet"))
+
+			// The stakes wait in the table's escrow until the round is paid
+			reconciliation, err := service.Reconcile(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(200), reconciliation.Issued)
+			// The loan is in player1's wallet and owed to the loan desk, it adds no chips
+			assert.Equal(t, int64(200), reconciliation.Circulating)
+			assert.Equal(t, int64(100), reconciliation.Wallets)
+
+			// player1 wins, player2 loses, the house is down $100 on the round
+			require.NoError(t, service.AddFunds(ctx, "player1", "table1", 300, entities.TransactionTypePayout, "round1", "Blackjack winnings"))
+			require.NoError(t, service.SettleTable(ctx, "table1"))
+			require.NoError(t, service.RepayLoan(ctx, "player1", 100))
+
+			reconciliation, err = service.Reconcile(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, reconciliation.Problems)
+			assert.Equal(t, int64(200), reconciliation.Circulating)
+			assert.Equal(t, int64(300), reconciliation.Wallets)
+
+			totals, err := service.repo.GetLedgerTotals(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, totals.Balances[entities.TableEscrowAccount("table1")])
+			assert.Equal(t, int64(-100), totals.Balances[entities.LedgerAccountHouseBank])
+			assert.Zero(t, totals.Balances[entities.LedgerAccountLoanDesk])
+		})
+	}
+}
+
+func TestReconcileFindsChipsOutsideTheLedger(t *testing.T) {
+	ctx := context.Background()
+	repo := walletRepo.NewMemoryRepository()
+	service := NewService(repo)
+	_, _, err := service.GetOrCreateWallet(ctx, "player1")
+	require.NoError(t, err)
+
+	// Saving a wallet directly changes its balance without posting anything
+	require.NoError(t, repo.SaveWallet(ctx, &entities.Wallet{UserID: "player1", Balance: 500, LastUpdated: time.Now()}))
+
+	reconciliation, err := service.Reconcile(ctx)
+	assert.ErrorIs(t, err, ErrLedgerOutOfBalance)
+	require.Len(t, reconciliation.Problems, 1)
+	assert.Contains(t, reconciliation.Problems[0], "wallet player1 holds $500")
+}
+
+func TestSQLiteLedgerOpensWithExistingWallets(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "wallets.db")
+
+	// Wallets saved before the ledger existed, the second one owing a loan
+	repo, err := walletRepo.NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveWallet(ctx, &entities.Wallet{UserID: "player1", Balance: 250, LastUpdated: time.Now()}))
+	require.NoError(t, repo.SaveWallet(ctx, &entities.Wallet{UserID: "player2", Balance: 40, LoanAmount: 200, LastUpdated: time.Now()}))
+	require.NoError(t, repo.Close())
+
+	// An empty ledger is opened when the database is, so the old wallets reconcile
+	repo, err = walletRepo.NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+	defer repo.Close()
+	reconciliation, err := NewService(repo).Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(290), reconciliation.Wallets)
+}
@@ -0,0 +1,455 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidLoanTerms = errors.New("invalid loan terms")
+
+// LoanTerms are what Tuco charges for his loans
+type LoanTerms struct {
+	InterestRate   int64         // Percent of what's owed added every InterestPeriod
+	InterestPeriod time.Duration // How often interest is charged
+	InterestCap    int64         // Most interest a loan can run up, as a percent of what was lent
+	Term           time.Duration // How long a loan has before it's due
+	ReminderLead   time.Duration // How long before a loan is due the first reminder goes out
+	ReminderEvery  time.Duration // How often an overdue loan is chased, 0 stops once it's due
+	CollectionCut  int64         // Percent of an overdue borrower's winnings Tuco takes
+}
+
+// DefaultLoanTerms returns Tuco's usual terms: 10% a day up to double what
+// was lent, a week to pay, and a quarter of the winnings once it's late
+func DefaultLoanTerms() LoanTerms {
+	return LoanTerms{
+		InterestRate:   10,
+		InterestPeriod: 24 * time.Hour,
+		InterestCap:    100,
+		Term:           7 * 24 * time.Hour,
+		ReminderLead:   24 * time.Hour,
+		ReminderEvery:  24 * time.Hour,
+		CollectionCut:  25,
+	}
+}
+
+// ParseLoanTerms reads loan terms as comma separated key=value pairs, e.g.
+// "interest=10%,every=24h,cap=100%,term=168h,remind=24h,chase=24h,cut=25%".
+// Terms left out keep their default.
+func ParseLoanTerms(spec string) (LoanTerms, error) {
+	terms := DefaultLoanTerms()
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, found := strings.Cut(entry, "=")
+		if !found {
+			return LoanTerms{}, fmt.Errorf("%w: expected key=value, got %q", ErrInvalidLoanTerms, entry)
+		}
+		value = strings.TrimSpace(value)
+
+		var err error
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "interest":
+			terms.InterestRate, err = parsePercent(value)
+		case "every":
+			terms.InterestPeriod, err = parseDuration(value)
+		case "cap":
+			terms.InterestCap, err = parsePercent(value)
+		case "term":
+			terms.Term, err = parseDuration(value)
+		case "remind":
+			terms.ReminderLead, err = parseDuration(value)
+		case "chase":
+			terms.ReminderEvery, err = parseDuration(value)
+		case "cut":
+			terms.CollectionCut, err = parsePercent(value)
+		default:
+			return LoanTerms{}, fmt.Errorf("%w: unknown term %q", ErrInvalidLoanTerms, key)
+		}
+		if err != nil {
+			return LoanTerms{}, fmt.Errorf("%w: bad %s %q", ErrInvalidLoanTerms, key, value)
+		}
+	}
+
+	if terms.InterestRate > 0 && (terms.InterestPeriod <= 0 || terms.InterestCap <= 0) {
+		return LoanTerms{}, fmt.Errorf("%w: interest needs a period and a cap", ErrInvalidLoanTerms)
+	}
+	if terms.Term <= 0 {
+		return LoanTerms{}, fmt.Errorf("%w: loans need a term", ErrInvalidLoanTerms)
+	}
+	if terms.CollectionCut > 100 {
+		return LoanTerms{}, fmt.Errorf("%w: Tuco can't take more than the winnings", ErrInvalidLoanTerms)
+	}
+	return terms, nil
+}
+
+// parsePercent reads a whole percentage, the % sign is optional
+func parsePercent(value string) (int64, error) {
+	percent, err := strconv.ParseInt(strings.TrimSuffix(value, "%"), 10, 64)
+	if err != nil || percent < 0 {
+		return 0, errors.New("bad percentage")
+	}
+	return percent, nil
+}
+
+// parseDuration reads a duration that can't be negative
+func parseDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, errors.New("bad duration")
+	}
+	return duration, nil
+}
+
+// SetLoanTerms sets the terms of new loans and of the interest, reminders
+// and collections on every loan. Call it before the bot starts.
+func (s *Service) SetLoanTerms(terms LoanTerms) {
+	s.terms = terms
+}
+
+// LoanTerms returns the terms Tuco lends on
+func (s *Service) LoanTerms() LoanTerms {
+	return s.terms
+}
+
+// lend puts a new loan in a user's wallet and records it in the loans
+func (s *Service) lend(ctx context.Context, userID string, amount int64) (*entities.Wallet, error) {
+	now := time.Now()
+	loan := &entities.Loan{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Principal: amount,
+		Status:    entities.LoanStatusOpen,
+		IssuedAt:  now,
+		DueAt:     now.Add(s.terms.Term),
+		AccruedAt: now,
+	}
+
+	var wallet *entities.Wallet
+	err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		var err error
+		wallet, err = recordChange(ctx, uow, userID, amount, amount, entities.LedgerAccountLoanDesk, entities.TransactionTypeLoan, loan.ID, "Loan from Tuco")
+		if err != nil {
+			return err
+		}
+		return uow.SaveLoan(ctx, loan)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[WALLET] Lent $%d to user %s, due %s", amount, userID, loan.DueAt.Format(time.RFC3339))
+	return wallet, nil
+}
+
+// repay takes a repayment out of a user's wallet and pays it off their
+// loans oldest first. With overdueOnly it only pays off loans that are overdue.
+func repay(ctx context.Context, uow walletRepo.UnitOfWork, userID string, amount int64, overdueOnly bool, referenceID, description string) error {
+	if _, err := recordChange(ctx, uow, userID, -amount, -amount, entities.LedgerAccountLoanDesk, entities.TransactionTypeRepayment, referenceID, description); err != nil {
+		return err
+	}
+
+	loans, err := uow.GetUserOpenLoans(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, loan := range loans {
+		if amount == 0 {
+			break
+		}
+		if overdueOnly && !loan.Overdue(now) {
+			continue
+		}
+		paid := min(amount, loan.Outstanding())
+		loan.Repaid += paid
+		amount -= paid
+		if loan.Outstanding() == 0 {
+			loan.Status = entities.LoanStatusRepaid
+			loan.RepaidAt = now
+		}
+		if err := uow.SaveLoan(ctx, loan); err != nil {
+			return err
+		}
+	}
+	if amount > 0 {
+		// Loans from before they were tracked one by one have no record to pay off
+		log.Printf("[WALLET] $%d of user %s's repayment paid off untracked loans", amount, userID)
+	}
+	return nil
+}
+
+// GetLoans returns every loan Tuco ever made to a user, oldest first
+func (s *Service) GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	return s.repo.GetLoans(ctx, userID)
+}
+
+// TrackOutstandingLoans records a loan for whatever each wallet owes that
+// isn't tracked yet, so debts from before loans were tracked one by one
+// accrue interest and fall due like any other. It's due a full term from
+// now. It returns the number of loans recorded.
+func (s *Service) TrackOutstandingLoans(ctx context.Context) (int, error) {
+	wallets, err := s.repo.GetAllWallets(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting wallets: %w", err)
+	}
+
+	tracked := 0
+	for _, wallet := range wallets {
+		if wallet.LoanAmount <= 0 {
+			continue
+		}
+		err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+			current, err := uow.GetWallet(ctx, wallet.UserID)
+			if err != nil {
+				return err
+			}
+			loans, err := uow.GetUserOpenLoans(ctx, wallet.UserID)
+			if err != nil {
+				return err
+			}
+			untracked := current.LoanAmount
+			for _, loan := range loans {
+				untracked -= loan.Outstanding()
+			}
+			if untracked <= 0 {
+				return nil
+			}
+
+			now := time.Now()
+			tracked++
+			log.Printf("[WALLET] Tracking $%d user %s owed from before loans were tracked", untracked, wallet.UserID)
+			return uow.SaveLoan(ctx, &entities.Loan{
+				ID:        uuid.New().String(),
+				UserID:    wallet.UserID,
+				Principal: untracked,
+				Status:    entities.LoanStatusOpen,
+				IssuedAt:  now,
+				DueAt:     now.Add(s.terms.Term),
+				AccruedAt: now,
+			})
+		})
+		if err != nil {
+			return tracked, err
+		}
+	}
+	return tracked, nil
+}
+
+// interestDue works out the interest a loan owes for the whole periods since
+// it was last charged, compounding on what's owed up to the cap, and the
+// time it's charged up to
+func (t LoanTerms) interestDue(loan *entities.Loan, now time.Time) (int64, time.Time) {
+	if t.InterestRate <= 0 || t.InterestPeriod <= 0 {
+		return 0, loan.AccruedAt
+	}
+	periods := int64(now.Sub(loan.AccruedAt) / t.InterestPeriod)
+	if periods <= 0 {
+		return 0, loan.AccruedAt
+	}
+
+	owed := loan.Outstanding()
+	room := loan.Principal*t.InterestCap/100 - loan.Interest
+	var interest int64
+	for i := int64(0); i < periods && room > 0 && owed > 0; i++ {
+		// Tuco rounds up
+		charge := min((owed*t.InterestRate+99)/100, room)
+		interest += charge
+		owed += charge
+		room -= charge
+	}
+	return interest, loan.AccruedAt.Add(time.Duration(periods) * t.InterestPeriod)
+}
+
+// AccrueInterest charges every open loan the interest it owes up to now and
+// returns the loans that were charged. The interest is added to what the
+// wallet owes, the house books it as earned from the loan desk.
+func (s *Service) AccrueInterest(ctx context.Context, now time.Time) ([]*entities.Loan, error) {
+	loans, err := s.repo.GetOpenLoans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting open loans: %w", err)
+	}
+
+	var charged []*entities.Loan
+	for _, loan := range loans {
+		err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+			// Read the loan again, it may have been repaid since
+			current, err := userOpenLoan(ctx, uow, loan.UserID, loan.ID)
+			if err != nil || current == nil {
+				return err
+			}
+			interest, accruedAt := s.terms.interestDue(current, now)
+			if accruedAt.Equal(current.AccruedAt) {
+				return nil
+			}
+			current.AccruedAt = accruedAt
+			if interest == 0 {
+				// Capped out, only the clock moves on
+				return uow.SaveLoan(ctx, current)
+			}
+
+			current.Interest += interest
+			if err := uow.SaveLoan(ctx, current); err != nil {
+				return err
+			}
+			wallet, err := uow.AdjustBalance(ctx, current.UserID, 0, interest)
+			if err != nil {
+				return err
+			}
+			log.Printf("[WALLET] Charging $%d interest on user %s's loan %s", interest, current.UserID, current.ID)
+			if err := uow.AddTransaction(ctx, &entities.Transaction{
+				ID:           uuid.New().String(),
+				UserID:       current.UserID,
+				Type:         entities.TransactionTypeInterest,
+				ReferenceID:  current.ID,
+				Description:  fmt.Sprintf("$%d interest on Tuco's loan", interest),
+				Timestamp:    time.Now(),
+				BalanceAfter: wallet.Balance,
+				Entries:      entities.Transfer(entities.LedgerAccountLoanDesk, entities.LedgerAccountHouseBank, interest),
+			}); err != nil {
+				return err
+			}
+			charged = append(charged, current)
+			return nil
+		})
+		if err != nil {
+			return charged, err
+		}
+	}
+	return charged, nil
+}
+
+// userOpenLoan returns one of a user's open loans, or nil if it isn't open any more
+func userOpenLoan(ctx context.Context, uow walletRepo.UnitOfWork, userID, loanID string) (*entities.Loan, error) {
+	loans, err := uow.GetUserOpenLoans(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		if loan.ID == loanID {
+			return loan, nil
+		}
+	}
+	return nil, nil
+}
+
+// Reminder levels, each sterner than the last
+const (
+	ReminderComingDue = 1 // The loan is due soon
+	ReminderDue       = 2 // The loan is due now
+	ReminderOverdue   = 3 // The loan is overdue, higher levels the longer it stays that way
+)
+
+// LoanReminder is a reminder a borrower is owed
+type LoanReminder struct {
+	Loan  *entities.Loan
+	Level int // ReminderComingDue, ReminderDue, or ReminderOverdue and up
+}
+
+// reminderAt returns when a loan's nth reminder, counting from 0, goes out,
+// or false if there isn't one
+func (t LoanTerms) reminderAt(loan *entities.Loan, n int) (time.Time, bool) {
+	switch {
+	case n == 0:
+		return loan.DueAt.Add(-t.ReminderLead), true
+	case n == 1:
+		return loan.DueAt, true
+	case t.ReminderEvery > 0:
+		return loan.DueAt.Add(time.Duration(n-1) * t.ReminderEvery), true
+	}
+	return time.Time{}, false
+}
+
+// DueReminders returns the reminders owed on open loans up to now and marks
+// them sent. A borrower who missed some while the bot was away only gets the
+// sternest one.
+func (s *Service) DueReminders(ctx context.Context, now time.Time) ([]LoanReminder, error) {
+	loans, err := s.repo.GetOpenLoans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting open loans: %w", err)
+	}
+
+	var reminders []LoanReminder
+	for _, loan := range loans {
+		sent := loan.RemindersSent
+		for {
+			at, exists := s.terms.reminderAt(loan, sent)
+			if !exists || now.Before(at) {
+				break
+			}
+			sent++
+		}
+		if sent == loan.RemindersSent {
+			continue
+		}
+
+		err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+			current, err := userOpenLoan(ctx, uow, loan.UserID, loan.ID)
+			if err != nil || current == nil || current.RemindersSent >= sent {
+				return err
+			}
+			current.RemindersSent = sent
+			if err := uow.SaveLoan(ctx, current); err != nil {
+				return err
+			}
+			reminders = append(reminders, LoanReminder{Loan: current, Level: sent})
+			return nil
+		})
+		if err != nil {
+			return reminders, err
+		}
+	}
+	return reminders, nil
+}
+
+// CollectFromWinnings takes Tuco's cut of a player's winnings while they have
+// a loan overdue and pays it off their overdue loans. It returns the amount
+// taken, 0 if nothing was overdue.
+func (s *Service) CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error) {
+	if winnings <= 0 || s.terms.CollectionCut <= 0 {
+		return 0, nil
+	}
+
+	var collected int64
+	err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		loans, err := uow.GetUserOpenLoans(ctx, userID)
+		if err != nil {
+			return err
+		}
+		var overdue int64
+		now := time.Now()
+		for _, loan := range loans {
+			if loan.Overdue(now) {
+				overdue += loan.Outstanding()
+			}
+		}
+		if overdue == 0 {
+			return nil
+		}
+
+		wallet, err := uow.GetWallet(ctx, userID)
+		if err != nil {
+			return err
+		}
+		collected = min(winnings*s.terms.CollectionCut/100, overdue, wallet.Balance, wallet.LoanAmount)
+		if collected <= 0 {
+			collected = 0
+			return nil
+		}
+		log.Printf("[WALLET] Collecting $%d of user %s's $%d winnings for an overdue loan", collected, userID, winnings)
+		return repay(ctx, uow, userID, collected, true, referenceID, "Tuco's cut for an overdue loan")
+	})
+	if err != nil {
+		return 0, err
+	}
+	return collected, nil
+}
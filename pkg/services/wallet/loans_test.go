@@ -0,0 +1,186 @@
+package wallet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoansAreRepaidOldestFirst(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, _, err := service.GiveLoan(ctx, "player1", 100)
+			require.NoError(t, err)
+			_, _, err = service.GiveLoan(ctx, "player1", 200)
+			require.NoError(t, err)
+			require.NoError(t, service.RepayLoan(ctx, "player1", 200))
+
+			loans, err := service.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			require.Len(t, loans, 2)
+			assert.Equal(t, entities.LoanStatusRepaid, loans[0].Status)
+			assert.False(t, loans[0].RepaidAt.IsZero())
+			assert.Equal(t, entities.LoanStatusOpen, loans[1].Status)
+			assert.Equal(t, int64(100), loans[1].Outstanding())
+			assert.Equal(t, loans[0].IssuedAt.Add(service.LoanTerms().Term), loans[0].DueAt)
+
+			_, err = service.Reconcile(ctx)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestInterestCompoundsUpToTheCap(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			service.SetLoanTerms(LoanTerms{InterestRate: 10, InterestPeriod: 24 * time.Hour, InterestCap: 30, Term: 7 * 24 * time.Hour})
+			wallet, _, err := service.GiveLoan(ctx, "player1", 100)
+			require.NoError(t, err)
+			balance := wallet.Balance
+			loans, err := service.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			issued := loans[0].IssuedAt
+
+			// Nothing's owed until a whole period has passed
+			charged, err := service.AccrueInterest(ctx, issued.Add(23*time.Hour))
+			require.NoError(t, err)
+			assert.Empty(t, charged)
+
+			// 10% of $100, then 10% of $110 rounded up
+			charged, err = service.AccrueInterest(ctx, issued.Add(49*time.Hour))
+			require.NoError(t, err)
+			require.Len(t, charged, 1)
+			assert.Equal(t, int64(21), charged[0].Interest)
+			assert.Equal(t, issued.Add(48*time.Hour), charged[0].AccruedAt)
+
+			wallet, _, err = service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(121), wallet.LoanAmount)
+			assert.Equal(t, balance, wallet.Balance)
+
+			// It stops at 30% of what was lent
+			_, err = service.AccrueInterest(ctx, issued.Add(30*24*time.Hour))
+			require.NoError(t, err)
+			wallet, _, err = service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(130), wallet.LoanAmount)
+
+			charges, err := service.repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypeInterest, 10)
+			require.NoError(t, err)
+			require.Len(t, charges, 2)
+			for _, charge := range charges {
+				assert.Equal(t, loans[0].ID, charge.ReferenceID)
+				assert.Zero(t, charge.Amount)
+			}
+
+			_, err = service.Reconcile(ctx)
+			assert.NoError(t, err)
+
+			// The $30 left over after a standard repayment can be paid off in one go
+			require.NoError(t, service.RepayLoan(ctx, "player1", 100))
+			require.NoError(t, service.RepayLoan(ctx, "player1", 30))
+			loans, err = service.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, entities.LoanStatusRepaid, loans[0].Status)
+		})
+	}
+}
+
+func TestRemindersEscalate(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, _, err := service.GiveLoan(ctx, "player1", 100)
+			require.NoError(t, err)
+			loans, err := service.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			due := loans[0].DueAt
+
+			levels := func(now time.Time) []int {
+				reminders, err := service.DueReminders(ctx, now)
+				require.NoError(t, err)
+				var levels []int
+				for _, reminder := range reminders {
+					levels = append(levels, reminder.Level)
+				}
+				return levels
+			}
+
+			assert.Empty(t, levels(loans[0].IssuedAt))
+			assert.Equal(t, []int{ReminderComingDue}, levels(due.Add(-time.Hour)))
+			assert.Empty(t, levels(due.Add(-time.Hour)), "each reminder goes out once")
+			assert.Equal(t, []int{ReminderDue}, levels(due.Add(time.Minute)))
+
+			// A borrower who missed a reminder only gets the sternest
+			assert.Equal(t, []int{ReminderOverdue + 1}, levels(due.Add(2*24*time.Hour+time.Minute)))
+
+			loans, err = service.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, ReminderOverdue+1, loans[0].RemindersSent)
+		})
+	}
+}
+
+func TestCollectFromWinnings(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			terms := DefaultLoanTerms()
+			terms.Term = time.Millisecond
+			service.SetLoanTerms(terms)
+			_, _, err := service.GiveLoan(ctx, "player1", 200)
+			require.NoError(t, err)
+			time.Sleep(2 * time.Millisecond)
+
+			// A quarter of the winnings goes to Tuco
+			require.NoError(t, service.AddFunds(ctx, "player1", "", 100, entities.TransactionTypePayout, "round1", "Blackjack winnings"))
+			collected, err := service.CollectFromWinnings(ctx, "player1", 100, "round1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(25), collected)
+
+			wallet, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(175), wallet.LoanAmount)
+			repayments, err := service.repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypeRepayment, 10)
+			require.NoError(t, err)
+			require.Len(t, repayments, 1)
+			assert.Equal(t, "round1", repayments[0].ReferenceID)
+			assert.Equal(t, int64(-25), repayments[0].Amount)
+			loans, err := service.GetLoans(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(25), loans[0].Repaid)
+
+			// Nothing's taken from a borrower who isn't overdue
+			service.SetLoanTerms(DefaultLoanTerms())
+			_, _, err = service.GiveLoan(ctx, "player2", 100)
+			require.NoError(t, err)
+			collected, err = service.CollectFromWinnings(ctx, "player2", 100, "round1")
+			require.NoError(t, err)
+			assert.Zero(t, collected)
+
+			_, err = service.Reconcile(ctx)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseLoanTerms(t *testing.T) {
+	terms, err := ParseLoanTerms("interest=5%, every=12h, term=72h, cut=50")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), terms.InterestRate)
+	assert.Equal(t, 12*time.Hour, terms.InterestPeriod)
+	assert.Equal(t, 72*time.Hour, terms.Term)
+	assert.Equal(t, int64(50), terms.CollectionCut)
+	assert.Equal(t, DefaultLoanTerms().InterestCap, terms.InterestCap)
+
+	for _, spec := range []string{"interest", "rate=5%", "interest=-5%", "term=0s", "cut=150%", "interest=5%,cap=0", "every=soon"} {
+		_, err := ParseLoanTerms(spec)
+		assert.ErrorIs(t, err, ErrInvalidLoanTerms, spec)
+	}
+}
@@ -5,14 +5,17 @@
 //
 //	mockgen -source=interface.go -destination=mock/mock.go -package=mock_wallet_service
 //
+
 // Package mock_wallet_service is a generated GoMock package.
 package mock_wallet_service
 
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entities "github.com/fadedpez/tucoramirez/pkg/entities"
+	wallet "github.com/fadedpez/tucoramirez/pkg/services/wallet"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -20,6 +23,7 @@ import (
 type MockWalletService struct {
 	ctrl     *gomock.Controller
 	recorder *MockWalletServiceMockRecorder
+	isgomock struct{}
 }
 
 // MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
@@ -39,18 +43,121 @@ func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
 	return m.recorder
 }
 
+// AccrueInterest mocks base method.
+func (m *MockWalletService) AccrueInterest(ctx context.Context, now time.Time) ([]*entities.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueInterest", ctx, now)
+	ret0, _ := ret[0].([]*entities.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueInterest indicates an expected call of AccrueInterest.
+func (mr *MockWalletServiceMockRecorder) AccrueInterest(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueInterest", reflect.TypeOf((*MockWalletService)(nil).AccrueInterest), ctx, now)
+}
+
 // AddFunds mocks base method.
-func (m *MockWalletService) AddFunds(ctx context.Context, userID string, amount int64, description string) error {
+func (m *MockWalletService) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFunds", ctx, userID, amount, description)
+	ret := m.ctrl.Call(m, "AddFunds", ctx, userID, tableID, amount, transactionType, referenceID, description)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFunds indicates an expected call of AddFunds.
-func (mr *MockWalletServiceMockRecorder) AddFunds(ctx, userID, amount, description any) *gomock.Call {
+func (mr *MockWalletServiceMockRecorder) AddFunds(ctx, userID, tableID, amount, transactionType, referenceID, description any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFunds", reflect.TypeOf((*MockWalletService)(nil).AddFunds), ctx, userID, tableID, amount, transactionType, referenceID, description)
+}
+
+// CalculateRepaymentAmount mocks base method.
+func (m *MockWalletService) CalculateRepaymentAmount(ctx context.Context, userID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CalculateRepaymentAmount", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CalculateRepaymentAmount indicates an expected call of CalculateRepaymentAmount.
+func (mr *MockWalletServiceMockRecorder) CalculateRepaymentAmount(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateRepaymentAmount", reflect.TypeOf((*MockWalletService)(nil).CalculateRepaymentAmount), ctx, userID)
+}
+
+// CanRepayLoan mocks base method.
+func (m *MockWalletService) CanRepayLoan(ctx context.Context, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanRepayLoan", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CanRepayLoan indicates an expected call of CanRepayLoan.
+func (mr *MockWalletServiceMockRecorder) CanRepayLoan(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanRepayLoan", reflect.TypeOf((*MockWalletService)(nil).CanRepayLoan), ctx, userID)
+}
+
+// CaptureHold mocks base method.
+func (m *MockWalletService) CaptureHold(ctx context.Context, userID, roundID string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, userID, roundID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockWalletServiceMockRecorder) CaptureHold(ctx, userID, roundID, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFunds", reflect.TypeOf((*MockWalletService)(nil).AddFunds), ctx, userID, amount, description)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockWalletService)(nil).CaptureHold), ctx, userID, roundID, amount)
+}
+
+// CaptureRoundHolds mocks base method.
+func (m *MockWalletService) CaptureRoundHolds(ctx context.Context, roundID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureRoundHolds", ctx, roundID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CaptureRoundHolds indicates an expected call of CaptureRoundHolds.
+func (mr *MockWalletServiceMockRecorder) CaptureRoundHolds(ctx, roundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureRoundHolds", reflect.TypeOf((*MockWalletService)(nil).CaptureRoundHolds), ctx, roundID)
+}
+
+// CollectFromWinnings mocks base method.
+func (m *MockWalletService) CollectFromWinnings(ctx context.Context, userID string, winnings int64, referenceID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectFromWinnings", ctx, userID, winnings, referenceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectFromWinnings indicates an expected call of CollectFromWinnings.
+func (mr *MockWalletServiceMockRecorder) CollectFromWinnings(ctx, userID, winnings, referenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectFromWinnings", reflect.TypeOf((*MockWalletService)(nil).CollectFromWinnings), ctx, userID, winnings, referenceID)
+}
+
+// DueReminders mocks base method.
+func (m *MockWalletService) DueReminders(ctx context.Context, now time.Time) ([]wallet.LoanReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueReminders", ctx, now)
+	ret0, _ := ret[0].([]wallet.LoanReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueReminders indicates an expected call of DueReminders.
+func (mr *MockWalletServiceMockRecorder) DueReminders(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueReminders", reflect.TypeOf((*MockWalletService)(nil).DueReminders), ctx, now)
 }
 
 // EnsureFundsWithLoan mocks base method.
@@ -69,6 +176,36 @@ func (mr *MockWalletServiceMockRecorder) EnsureFundsWithLoan(ctx, userID, requir
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureFundsWithLoan", reflect.TypeOf((*MockWalletService)(nil).EnsureFundsWithLoan), ctx, userID, requiredAmount, loanAmount)
 }
 
+// GetLoans mocks base method.
+func (m *MockWalletService) GetLoans(ctx context.Context, userID string) ([]*entities.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoans", ctx, userID)
+	ret0, _ := ret[0].([]*entities.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoans indicates an expected call of GetLoans.
+func (mr *MockWalletServiceMockRecorder) GetLoans(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoans", reflect.TypeOf((*MockWalletService)(nil).GetLoans), ctx, userID)
+}
+
+// GetOpenHolds mocks base method.
+func (m *MockWalletService) GetOpenHolds(ctx context.Context) ([]*entities.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenHolds", ctx)
+	ret0, _ := ret[0].([]*entities.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenHolds indicates an expected call of GetOpenHolds.
+func (mr *MockWalletServiceMockRecorder) GetOpenHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenHolds", reflect.TypeOf((*MockWalletService)(nil).GetOpenHolds), ctx)
+}
+
 // GetOrCreateWallet mocks base method.
 func (m *MockWalletService) GetOrCreateWallet(ctx context.Context, userID string) (*entities.Wallet, bool, error) {
 	m.ctrl.T.Helper()
@@ -85,16 +222,175 @@ func (mr *MockWalletServiceMockRecorder) GetOrCreateWallet(ctx, userID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateWallet", reflect.TypeOf((*MockWalletService)(nil).GetOrCreateWallet), ctx, userID)
 }
 
+// GetStandardLoanIncrement mocks base method.
+func (m *MockWalletService) GetStandardLoanIncrement() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStandardLoanIncrement")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetStandardLoanIncrement indicates an expected call of GetStandardLoanIncrement.
+func (mr *MockWalletServiceMockRecorder) GetStandardLoanIncrement() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStandardLoanIncrement", reflect.TypeOf((*MockWalletService)(nil).GetStandardLoanIncrement))
+}
+
+// GiveLoan mocks base method.
+func (m *MockWalletService) GiveLoan(ctx context.Context, userID string, amount int64) (*entities.Wallet, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GiveLoan", ctx, userID, amount)
+	ret0, _ := ret[0].(*entities.Wallet)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GiveLoan indicates an expected call of GiveLoan.
+func (mr *MockWalletServiceMockRecorder) GiveLoan(ctx, userID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GiveLoan", reflect.TypeOf((*MockWalletService)(nil).GiveLoan), ctx, userID, amount)
+}
+
+// HoldFunds mocks base method.
+func (m *MockWalletService) HoldFunds(ctx context.Context, userID, tableID, roundID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldFunds", ctx, userID, tableID, roundID, amount, transactionType, referenceID, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HoldFunds indicates an expected call of HoldFunds.
+func (mr *MockWalletServiceMockRecorder) HoldFunds(ctx, userID, tableID, roundID, amount, transactionType, referenceID, description any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldFunds", reflect.TypeOf((*MockWalletService)(nil).HoldFunds), ctx, userID, tableID, roundID, amount, transactionType, referenceID, description)
+}
+
+// Reconcile mocks base method.
+func (m *MockWalletService) Reconcile(ctx context.Context) (*wallet.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx)
+	ret0, _ := ret[0].(*wallet.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockWalletServiceMockRecorder) Reconcile(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockWalletService)(nil).Reconcile), ctx)
+}
+
+// ReleaseFromHold mocks base method.
+func (m *MockWalletService) ReleaseFromHold(ctx context.Context, userID, roundID string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseFromHold", ctx, userID, roundID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseFromHold indicates an expected call of ReleaseFromHold.
+func (mr *MockWalletServiceMockRecorder) ReleaseFromHold(ctx, userID, roundID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseFromHold", reflect.TypeOf((*MockWalletService)(nil).ReleaseFromHold), ctx, userID, roundID, amount)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockWalletService) ReleaseHold(ctx context.Context, userID, roundID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", ctx, userID, roundID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockWalletServiceMockRecorder) ReleaseHold(ctx, userID, roundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockWalletService)(nil).ReleaseHold), ctx, userID, roundID)
+}
+
+// ReleaseRoundHolds mocks base method.
+func (m *MockWalletService) ReleaseRoundHolds(ctx context.Context, roundID string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRoundHolds", ctx, roundID)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseRoundHolds indicates an expected call of ReleaseRoundHolds.
+func (mr *MockWalletServiceMockRecorder) ReleaseRoundHolds(ctx, roundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRoundHolds", reflect.TypeOf((*MockWalletService)(nil).ReleaseRoundHolds), ctx, roundID)
+}
+
 // RemoveFunds mocks base method.
-func (m *MockWalletService) RemoveFunds(ctx context.Context, userID string, amount int64, description string) error {
+func (m *MockWalletService) RemoveFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveFunds", ctx, userID, amount, description)
+	ret := m.ctrl.Call(m, "RemoveFunds", ctx, userID, tableID, amount, transactionType, referenceID, description)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveFunds indicates an expected call of RemoveFunds.
-func (mr *MockWalletServiceMockRecorder) RemoveFunds(ctx, userID, amount, description any) *gomock.Call {
+func (mr *MockWalletServiceMockRecorder) RemoveFunds(ctx, userID, tableID, amount, transactionType, referenceID, description any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFunds", reflect.TypeOf((*MockWalletService)(nil).RemoveFunds), ctx, userID, tableID, amount, transactionType, referenceID, description)
+}
+
+// RepayLoan mocks base method.
+func (m *MockWalletService) RepayLoan(ctx context.Context, userID string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepayLoan", ctx, userID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RepayLoan indicates an expected call of RepayLoan.
+func (mr *MockWalletServiceMockRecorder) RepayLoan(ctx, userID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepayLoan", reflect.TypeOf((*MockWalletService)(nil).RepayLoan), ctx, userID, amount)
+}
+
+// SettleTable mocks base method.
+func (m *MockWalletService) SettleTable(ctx context.Context, tableID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleTable", ctx, tableID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SettleTable indicates an expected call of SettleTable.
+func (mr *MockWalletServiceMockRecorder) SettleTable(ctx, tableID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleTable", reflect.TypeOf((*MockWalletService)(nil).SettleTable), ctx, tableID)
+}
+
+// ValidateLoan mocks base method.
+func (m *MockWalletService) ValidateLoan(ctx context.Context, userID string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateLoan", ctx, userID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateLoan indicates an expected call of ValidateLoan.
+func (mr *MockWalletServiceMockRecorder) ValidateLoan(ctx, userID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateLoan", reflect.TypeOf((*MockWalletService)(nil).ValidateLoan), ctx, userID, amount)
+}
+
+// ValidateRepayment mocks base method.
+func (m *MockWalletService) ValidateRepayment(ctx context.Context, userID string, amount int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateRepayment", ctx, userID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateRepayment indicates an expected call of ValidateRepayment.
+func (mr *MockWalletServiceMockRecorder) ValidateRepayment(ctx, userID, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFunds", reflect.TypeOf((*MockWalletService)(nil).RemoveFunds), ctx, userID, amount, description)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateRepayment", reflect.TypeOf((*MockWalletService)(nil).ValidateRepayment), ctx, userID, amount)
 }
@@ -15,17 +15,23 @@ import (
 var (
 	ErrInsufficientFunds = errors.New("insufficient funds")
 	ErrNegativeAmount    = errors.New("amount cannot be negative")
+	ErrNotAGameType      = errors.New("transaction type is not a game movement")
 )
 
+// StartingBalance is what a new wallet starts with
+const StartingBalance int64 = 100
+
 // Service handles wallet business logic
 type Service struct {
-	repo walletRepo.Repository
+	repo  walletRepo.Repository
+	terms LoanTerms // What Tuco charges for his loans
 }
 
 // NewService creates a new wallet service
 func NewService(repo walletRepo.Repository) *Service {
 	return &Service{
-		repo: repo,
+		repo:  repo,
+		terms: DefaultLoanTerms(),
 	}
 }
 
@@ -40,120 +46,147 @@ func (s *Service) GetOrCreateWallet(ctx context.Context, userID string) (*entiti
 		return nil, false, err // Unexpected error
 	}
 
-	// Create a new wallet with starting balance of 100
-	newWallet := &entities.Wallet{
-		UserID:      userID,
-		Balance:     100, // Starting balance
-		LoanAmount:  0,
-		LastUpdated: time.Now(),
-	}
-
-	if err := s.repo.SaveWallet(ctx, newWallet); err != nil {
+	// Create a new wallet with the starting balance, unless another
+	// request created it in the meantime
+	var newWallet *entities.Wallet
+	created := false
+	err = s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		existing, err := uow.GetWallet(ctx, userID)
+		if err == nil {
+			newWallet = existing
+			return nil
+		}
+		if !errors.Is(err, walletRepo.ErrWalletNotFound) {
+			return err
+		}
+
+		newWallet = &entities.Wallet{
+			UserID:      userID,
+			Balance:     StartingBalance,
+			LoanAmount:  0,
+			LastUpdated: time.Now(),
+		}
+		created = true
+		if err := uow.CreateWallet(ctx, newWallet); err != nil {
+			return err
+		}
+
+		// The starting chips are new to the economy, the mint issues them
+		return uow.AddTransaction(ctx, &entities.Transaction{
+			ID:           uuid.New().String(),
+			UserID:       userID,
+			Amount:       StartingBalance,
+			Type:         entities.TransactionTypeGrant,
+			Description:  "Starting chips from Tuco",
+			Timestamp:    time.Now(),
+			BalanceAfter: StartingBalance,
+			Entries:      entities.Transfer(entities.LedgerAccountMint, entities.UserAccount(userID), StartingBalance),
+		})
+	})
+	if err != nil {
 		return nil, false, err
 	}
 
-	return newWallet, true, nil
+	return newWallet, created, nil
 }
 
-// GetBalance returns the current balance for a user
-func (s *Service) GetBalance(ctx context.Context, userID string) (int64, error) {
-	wallet, err := s.repo.GetWallet(ctx, userID)
+// applyChange changes a user's balance and loan and records the transaction
+// in one unit of work, so a failure can't leave one without the other and
+// two changes at once can't overwrite each other. The chips come from or go
+// to the counterparty account in the ledger. Taking out more than the
+// balance or repaying more than the loan fails with ErrInsufficientFunds.
+func (s *Service) applyChange(ctx context.Context, userID string, amount, loanAmount int64, counterparty entities.LedgerAccount, transactionType entities.TransactionType, referenceID, description string) (*entities.Wallet, error) {
+	var wallet *entities.Wallet
+	err := s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		var err error
+		wallet, err = recordChange(ctx, uow, userID, amount, loanAmount, counterparty, transactionType, referenceID, description)
+		return err
+	})
+	if errors.Is(err, walletRepo.ErrInsufficientFunds) {
+		return nil, ErrInsufficientFunds
+	}
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return wallet.Balance, nil
+	return wallet, nil
 }
 
-// AddFunds adds funds to a user's wallet
-func (s *Service) AddFunds(ctx context.Context, userID string, amount int64, description string) error {
-	if amount <= 0 {
-		return ErrNegativeAmount
-	}
-
-	// Log the start of the operation
-	log.Printf("[WALLET] Adding $%d to wallet for user %s with description: %s", amount, userID, description)
-
-	wallet, err := s.repo.GetWallet(ctx, userID)
+// recordChange changes a user's balance and loan and records the transaction
+// in a unit of work that's already running, see applyChange
+func recordChange(ctx context.Context, uow walletRepo.UnitOfWork, userID string, amount, loanAmount int64, counterparty entities.LedgerAccount, transactionType entities.TransactionType, referenceID, description string) (*entities.Wallet, error) {
+	wallet, err := uow.AdjustBalance(ctx, userID, amount, loanAmount)
 	if err != nil {
-		log.Printf("[WALLET] Error getting wallet for user %s: %v", userID, err)
-		return err
-	}
-
-	// Log the wallet state before update
-	log.Printf("[WALLET] Before update - User %s: Balance=$%d, LoanAmount=$%d", userID, wallet.Balance, wallet.LoanAmount)
-
-	// Update the wallet balance directly
-	wallet.Balance += amount
-	wallet.LastUpdated = time.Now()
-	
-	// Save the updated wallet
-	if err := s.repo.SaveWallet(ctx, wallet); err != nil {
-		log.Printf("[WALLET] Error saving wallet for user %s: %v", userID, err)
-		return err
+		return nil, err
 	}
 
-	// Log the wallet state after update
-	log.Printf("[WALLET] After update - User %s: Balance=$%d, LoanAmount=$%d", userID, wallet.Balance, wallet.LoanAmount)
-
-	// Record the transaction
 	transaction := &entities.Transaction{
 		ID:           uuid.New().String(),
 		UserID:       userID,
 		Amount:       amount,
-		Type:         entities.TransactionTypeLoan, // Default type, should be overridden by caller
+		Type:         transactionType,
+		ReferenceID:  referenceID,
 		Description:  description,
 		Timestamp:    time.Now(),
 		BalanceAfter: wallet.Balance,
+		Entries:      entities.Transfer(counterparty, entities.UserAccount(userID), amount),
 	}
-
-	// Log the transaction
-	log.Printf("[WALLET] Recording transaction: ID=%s, User=%s, Amount=$%d, Type=%s", 
+	log.Printf("[WALLET] Recording transaction: ID=%s, User=%s, Amount=$%d, Type=%s",
 		transaction.ID, userID, amount, transaction.Type)
+	if err := uow.AddTransaction(ctx, transaction); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
 
-	err = s.repo.AddTransaction(ctx, transaction)
+// GetBalance returns the current balance for a user
+func (s *Service) GetBalance(ctx context.Context, userID string) (int64, error) {
+	wallet, err := s.repo.GetWallet(ctx, userID)
 	if err != nil {
-		log.Printf("[WALLET] Error adding transaction for user %s: %v", userID, err)
+		return 0, err
 	}
-	return err
+	return wallet.Balance, nil
 }
 
-// RemoveFunds removes funds from a user's wallet if sufficient funds exist
-func (s *Service) RemoveFunds(ctx context.Context, userID string, amount int64, description string) error {
+// AddFunds pays chips from a game into a user's wallet out of the table's
+// escrow, or the house bank if tableID is empty. The type says what the chips
+// are, a payout or a stake given back, and the reference ties them to the
+// round or hand they came from.
+func (s *Service) AddFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
 	if amount <= 0 {
 		return ErrNegativeAmount
 	}
+	if !transactionType.IsGame() {
+		return fmt.Errorf("%w: %s", ErrNotAGameType, transactionType)
+	}
 
-	wallet, err := s.repo.GetWallet(ctx, userID)
+	// Log the start of the operation
+	log.Printf("[WALLET] Adding $%d to wallet for user %s with description: %s", amount, userID, description)
+
+	wallet, err := s.applyChange(ctx, userID, amount, 0, tableAccount(tableID), transactionType, referenceID, description)
 	if err != nil {
+		log.Printf("[WALLET] Error adding funds for user %s: %v", userID, err)
 		return err
 	}
 
-	// Check if user has sufficient funds
-	if wallet.Balance < amount {
-		return ErrInsufficientFunds
-	}
+	// Log the wallet state after update
+	log.Printf("[WALLET] After update - User %s: Balance=$%d, LoanAmount=$%d", userID, wallet.Balance, wallet.LoanAmount)
+	return nil
+}
 
-	// Update the wallet balance directly
-	wallet.Balance -= amount
-	wallet.LastUpdated = time.Now()
-	
-	// Save the updated wallet
-	if err := s.repo.SaveWallet(ctx, wallet); err != nil {
-		return err
+// RemoveFunds takes a stake out of a user's wallet into the table's escrow,
+// or the house bank if tableID is empty, if sufficient funds exist. The type
+// says what the stake is for and the reference ties it to its round or hand.
+func (s *Service) RemoveFunds(ctx context.Context, userID, tableID string, amount int64, transactionType entities.TransactionType, referenceID, description string) error {
+	if amount <= 0 {
+		return ErrNegativeAmount
 	}
-
-	// Record the transaction
-	transaction := &entities.Transaction{
-		ID:           uuid.New().String(),
-		UserID:       userID,
-		Amount:       -amount, // Negative amount for removal
-		Type:         entities.TransactionTypeRepayment, // Default type, should be overridden by caller
-		Description:  description,
-		Timestamp:    time.Now(),
-		BalanceAfter: wallet.Balance,
+	if !transactionType.IsGame() {
+		return fmt.Errorf("%w: %s", ErrNotAGameType, transactionType)
 	}
 
-	return s.repo.AddTransaction(ctx, transaction)
+	// The balance is checked as it's changed, so two bets at once can't both spend it
+	_, err := s.applyChange(ctx, userID, -amount, 0, tableAccount(tableID), transactionType, referenceID, description)
+	return err
 }
 
 // TakeLoan adds a loan amount to the user's wallet
@@ -162,38 +195,11 @@ func (s *Service) TakeLoan(ctx context.Context, userID string, amount int64) err
 		return ErrNegativeAmount
 	}
 
-	wallet, err := s.repo.GetWallet(ctx, userID)
-	if err != nil {
-		return err
-	}
-
-	// Update the wallet balance directly in the wallet object
-	wallet.Balance += amount
-	
-	// Update the loan amount
-	wallet.LoanAmount += amount
-	wallet.LastUpdated = time.Now()
-	
-	// Save the updated wallet with both balance and loan changes
-	if err := s.repo.SaveWallet(ctx, wallet); err != nil {
-		return err
-	}
-
-	// Record the transaction
-	transaction := &entities.Transaction{
-		ID:           uuid.New().String(),
-		UserID:       userID,
-		Amount:       amount,
-		Type:         entities.TransactionTypeLoan,
-		Description:  "Loan from Tuco",
-		Timestamp:    time.Now(),
-		BalanceAfter: wallet.Balance,
-	}
-
-	return s.repo.AddTransaction(ctx, transaction)
+	_, err := s.lend(ctx, userID, amount)
+	return err
 }
 
-// RepayLoan repays a portion of the user's loan
+// RepayLoan repays a portion of the user's loans, oldest first
 func (s *Service) RepayLoan(ctx context.Context, userID string, amount int64) error {
 	// Validate the repayment first
 	err := s.ValidateRepayment(ctx, userID, amount)
@@ -201,35 +207,15 @@ func (s *Service) RepayLoan(ctx context.Context, userID string, amount int64) er
 		return err
 	}
 
-	wallet, err := s.repo.GetWallet(ctx, userID)
-	if err != nil {
-		return err
-	}
-
-	// Update the wallet balance directly in the wallet object
-	wallet.Balance -= amount
-	
-	// Update the loan amount
-	wallet.LoanAmount -= amount
-	wallet.LastUpdated = time.Now()
-	
-	// Save the updated wallet with both balance and loan changes
-	if err := s.repo.SaveWallet(ctx, wallet); err != nil {
-		return err
-	}
-
-	// Record the transaction
-	transaction := &entities.Transaction{
-		ID:           uuid.New().String(),
-		UserID:       userID,
-		Amount:       -amount, // Negative amount for removal
-		Type:         entities.TransactionTypeRepayment,
-		Description:  "Loan repayment to Tuco",
-		Timestamp:    time.Now(),
-		BalanceAfter: wallet.Balance,
+	// The balance and loan are checked again as they're changed, in case
+	// something else spent the money since
+	err = s.repo.RunInTx(ctx, func(uow walletRepo.UnitOfWork) error {
+		return repay(ctx, uow, userID, amount, false, "", "Loan repayment to Tuco")
+	})
+	if errors.Is(err, walletRepo.ErrInsufficientFunds) {
+		return ErrInsufficientFunds
 	}
-
-	return s.repo.AddTransaction(ctx, transaction)
+	return err
 }
 
 // GetRecentTransactions retrieves recent transactions for a user
@@ -280,8 +266,9 @@ func (s *Service) ValidateRepayment(ctx context.Context, userID string, amount i
 		return errors.New("no loan to repay")
 	}
 	
-	// Ensure repayments are in increments of 100
-	if amount % 100 != 0 {
+	// Ensure repayments are in increments of 100, interest can leave a
+	// smaller remainder that's paid off all at once
+	if amount % 100 != 0 && amount != wallet.LoanAmount {
 		return errors.New("repayment amount must be in increments of 100")
 	}
 	
@@ -319,36 +306,15 @@ func (s *Service) GiveLoan(ctx context.Context, userID string, amount int64) (*e
 		return nil, false, err
 	}
 
-	// Get the user's wallet
-	wallet, _, err := s.GetOrCreateWallet(ctx, userID)
-	if err != nil {
+	// Make sure the user has a wallet to lend to
+	if _, _, err := s.GetOrCreateWallet(ctx, userID); err != nil {
 		return nil, false, fmt.Errorf("error getting wallet: %w", err)
 	}
 
-	// Add the loan amount to the wallet
-	wallet.Balance += amount
-	wallet.LoanAmount += amount // Add to existing loan amount instead of replacing
-	wallet.LastUpdated = time.Now()
-
-	// Save the updated wallet
-	if err := s.repo.SaveWallet(ctx, wallet); err != nil {
-		return nil, false, fmt.Errorf("error updating wallet: %w", err)
-	}
-
-	// Record the transaction
-	transaction := &entities.Transaction{
-		ID:          uuid.New().String(),
-		UserID:      userID,
-		Amount:      amount,
-		Type:        entities.TransactionTypeLoan,
-		Description: "Loan from Tuco",
-		Timestamp:   time.Now(),
-		BalanceAfter: wallet.Balance,
-	}
-
-	err = s.repo.AddTransaction(ctx, transaction)
+	// Add the loan amount to the wallet and to the existing loan
+	wallet, err := s.lend(ctx, userID, amount)
 	if err != nil {
-		log.Printf("Failed to record loan transaction: %v", err)
+		return nil, false, fmt.Errorf("error updating wallet: %w", err)
 	}
 
 	return wallet, true, nil
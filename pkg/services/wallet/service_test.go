@@ -0,0 +1,149 @@
+package wallet
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/fadedpez/tucoramirez/pkg/entities"
+	walletRepo "github.com/fadedpez/tucoramirez/pkg/repositories/wallet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// services returns a wallet service on each kind of repository
+func services(t *testing.T) map[string]*Service {
+	sqliteRepo, err := walletRepo.NewSQLiteRepository(filepath.Join(t.TempDir(), "wallets.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqliteRepo.Close() })
+
+	return map[string]*Service{
+		"memory": NewService(walletRepo.NewMemoryRepository()),
+		"sqlite": NewService(sqliteRepo),
+	}
+}
+
+func TestConcurrentRemoveFundsNeverOverdraws(t *testing.T) {
+	ctx := context.Background()
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+
+			// Twenty $10 bets at once against a $100 balance, only ten can go through
+			var wg sync.WaitGroup
+			errs := make(chan error, 20)
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- service.RemoveFunds(ctx, "player1", "", 10, entities.TransactionTypeBet, "round1", "Blackjack bet")
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			succeeded := 0
+			for err := range errs {
+				if err == nil {
+					succeeded++
+				} else {
+					assert.ErrorIs(t, err, ErrInsufficientFunds)
+				}
+			}
+			assert.Equal(t, 10, succeeded)
+
+			balance, err := service.GetBalance(ctx, "player1")
+			require.NoError(t, err)
+			assert.Zero(t, balance)
+
+			// Every bet that went through left exactly one transaction, after the starting chips
+			transactions, err := service.GetRecentTransactions(ctx, "player1", 100)
+			require.NoError(t, err)
+			assert.Len(t, transactions, 11)
+
+			_, err = service.Reconcile(ctx)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestConcurrentChangesAreNotLost(t *testing.T) {
+	ctx := context.Background()
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			// Wallets created by two requests at once are only created once
+			var wg sync.WaitGroup
+			created := make(chan bool, 10)
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, isNew, err := service.GetOrCreateWallet(ctx, "player1")
+					assert.NoError(t, err)
+					created <- isNew
+				}()
+			}
+			wg.Wait()
+			close(created)
+			newWallets := 0
+			for isNew := range created {
+				if isNew {
+					newWallets++
+				}
+			}
+			assert.Equal(t, 1, newWallets)
+
+			for i := 0; i < 25; i++ {
+				wg.Add(2)
+				go func() {
+					defer wg.Done()
+					assert.NoError(t, service.AddFunds(ctx, "player1", "", 20, entities.TransactionTypePayout, "round1", "Blackjack winnings"))
+				}()
+				go func() {
+					defer wg.Done()
+					_, _, err := service.GiveLoan(ctx, "player1", 100)
+					assert.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+
+			wallet, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(100+25*20+25*100), wallet.Balance)
+			assert.Equal(t, int64(25*100), wallet.LoanAmount)
+
+			_, err = service.Reconcile(ctx)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTransactionsAreTypedAndReferenced(t *testing.T) {
+	for name, service := range services(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			_, _, err := service.GetOrCreateWallet(ctx, "player1")
+			require.NoError(t, err)
+			require.NoError(t, service.RemoveFunds(ctx, "player1", "", 10, entities.TransactionTypeBet, "round1/hand1", "Blackjack bet"))
+			require.NoError(t, service.AddFunds(ctx, "player1", "", 10, entities.TransactionTypePushRefund, "round1", "Blackjack push"))
+
+			// Loans and grants have their own way in
+			assert.ErrorIs(t, service.AddFunds(ctx, "player1", "", 10, entities.TransactionTypeLoan, "", "Loan from Tuco"), ErrNotAGameType)
+			assert.ErrorIs(t, service.RemoveFunds(ctx, "player1", "", 10, entities.TransactionTypeRepayment, "", "Loan repayment to Tuco"), ErrNotAGameType)
+
+			bets, err := service.repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypeBet, 10)
+			require.NoError(t, err)
+			require.Len(t, bets, 1)
+			assert.Equal(t, "round1/hand1", bets[0].ReferenceID)
+			refunds, err := service.repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypePushRefund, 10)
+			require.NoError(t, err)
+			require.Len(t, refunds, 1)
+			assert.Equal(t, "round1", refunds[0].ReferenceID)
+			grants, err := service.repo.GetTransactionsByType(ctx, "player1", entities.TransactionTypeGrant, 10)
+			require.NoError(t, err)
+			assert.Len(t, grants, 1)
+		})
+	}
+}